@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/service/system/exec"
+	"github.com/viant/endly/service/workflow"
+)
+
+const (
+	//OutputText represents default colored human readable CLI output
+	OutputText = "text"
+	//OutputJSONL represents JSON Lines event output, one record per event
+	OutputJSONL = "jsonl"
+)
+
+const (
+	recordKindWorkflowStart = "workflow.start"
+	recordKindWorkflowEnd   = "workflow.end"
+	recordKindTaskStart     = "task.start"
+	recordKindTaskEnd       = "task.end"
+	recordKindActionStart   = "action.start"
+	recordKindActionEnd     = "action.end"
+	recordKindValidation    = "validation"
+	recordKindStdin         = "stdin"
+	recordKindStdout        = "stdout"
+	recordKindError         = "error"
+	recordKindEvent         = "event"
+	recordKindSummary       = "summary"
+)
+
+// EventRecord represents machine readable event, field names are stable across versions
+type EventRecord struct {
+	Time       time.Time   `json:"time"`
+	SessionID  string      `json:"sessionId,omitempty"`
+	Kind       string      `json:"kind"`           //workflow.start, task.end, action.start, validation, stdout, error, event, summary ...
+	Type       string      `json:"type,omitempty"` //source event type, i.e. workflow_WorkflowStartEvent
+	Workflow   string      `json:"workflow,omitempty"`
+	TaskPath   string      `json:"taskPath,omitempty"`
+	Service    string      `json:"service,omitempty"`
+	Action     string      `json:"action,omitempty"`
+	TagID      string      `json:"tagId,omitempty"`
+	Status     string      `json:"status,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs float64     `json:"durationMs,omitempty"` //set on end and summary records
+	Passed     int         `json:"passed,omitempty"`
+	Failed     int         `json:"failed,omitempty"`
+	Value      interface{} `json:"value,omitempty"` //event specific payload, omitted if it can not be encoded
+	depth      int         //workflow nesting level
+}
+
+// EventEncoder writes workflow events as JSON Lines
+type EventEncoder struct {
+	writer     io.Writer
+	sessionID  string
+	workflows  []*EventRecord
+	tasks      []*EventRecord
+	activities []*EventRecord
+	mux        sync.Mutex
+}
+
+// Encode writes supplied event as JSON line
+func (e *EventEncoder) Encode(event msg.Event) error {
+	if event == nil || event.Value() == nil {
+		return nil
+	}
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return e.write(e.asRecord(event))
+}
+
+// EncodeSummary writes run summary record
+func (e *EventEncoder) EncodeSummary(report *ReportSummaryEvent) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	record := e.newRecord(recordKindSummary, time.Now())
+	record.Status = "SUCCESS"
+	if report.Error || report.TotalTagFailed > 0 {
+		record.Status = "FAILED"
+	}
+	record.Passed = report.TotalTagPassed
+	record.Failed = report.TotalTagFailed
+	record.DurationMs = float64(report.ElapsedMs)
+	return e.write(record)
+}
+
+func (e *EventEncoder) write(record *EventRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		record.Value = nil
+		if line, err = json.Marshal(record); err != nil {
+			return err
+		}
+	}
+	_, err = e.writer.Write(append(line, '\n'))
+	return err
+}
+
+func (e *EventEncoder) newRecord(kind string, timestamp time.Time) *EventRecord {
+	result := &EventRecord{Time: timestamp, SessionID: e.sessionID, Kind: kind, depth: len(e.workflows)}
+	if count := len(e.workflows); count > 0 {
+		result.Workflow = e.workflows[count-1].Workflow
+	}
+	if count := len(e.tasks); count > 0 {
+		result.TaskPath = e.tasks[count-1].TaskPath
+	}
+	if count := len(e.activities); count > 0 {
+		activity := e.activities[count-1]
+		result.Service, result.Action, result.TagID = activity.Service, activity.Action, activity.TagID
+	}
+	return result
+}
+
+func (e *EventEncoder) asRecord(event msg.Event) *EventRecord {
+	var record *EventRecord
+	switch value := event.Value().(type) {
+	case *workflow.WorkflowStartEvent:
+		record = e.newRecord(recordKindWorkflowStart, event.Timestamp())
+		record.Workflow = value.Name
+		record.SessionID = value.SessionID
+		e.workflows = append(e.workflows, record)
+	case *workflow.WorkflowEndEvent:
+		record = e.newRecord(recordKindWorkflowEnd, event.Timestamp())
+		if start := popRecord(&e.workflows); start != nil {
+			record.DurationMs = elapsedMs(start.Time, record.Time)
+		}
+		record.Workflow, record.Status, record.Error = value.Name, value.Status, value.Error
+	case *model.TaskStartEvent:
+		record = e.newRecord(recordKindTaskStart, event.Timestamp())
+		record.Workflow = value.WorkflowName
+		record.TaskPath = value.TaskName
+		if count := len(e.tasks); count > 0 && e.tasks[count-1].depth == record.depth { //task path is composed within the workflow
+			record.TaskPath = e.tasks[count-1].TaskPath + "/" + value.TaskName
+		}
+		e.tasks = append(e.tasks, record)
+	case *model.TaskEndEvent:
+		record = e.newRecord(recordKindTaskEnd, event.Timestamp())
+		if start := popRecord(&e.tasks); start != nil {
+			record.DurationMs = elapsedMs(start.Time, record.Time)
+		}
+		record.Workflow = value.WorkflowName
+		record.Status, record.Error = value.Status, value.Error
+	case *model.Activity:
+		record = e.newRecord(recordKindActionStart, event.Timestamp())
+		record.Service, record.Action = value.Service, value.Action
+		if value.MetaTag != nil {
+			record.TagID = value.TagID
+		}
+		record.Value = value.Request
+		e.activities = append(e.activities, record)
+	case *model.ActivityEndEvent:
+		record = e.newRecord(recordKindActionEnd, event.Timestamp())
+		popRecord(&e.activities)
+		if activity, ok := value.Response.(*model.Activity); ok {
+			record.DurationMs = elapsedMs(activity.StartTime, record.Time)
+			if activity.ServiceResponse != nil {
+				record.Status, record.Error = activity.ServiceResponse.Status, activity.ServiceResponse.Error
+			}
+			record.Value = activity.Response
+		}
+	case *msg.ErrorEvent:
+		record = e.newRecord(recordKindError, event.Timestamp())
+		record.Error = value.Error
+	case *msg.StdoutEvent:
+		record = e.newRecord(recordKindStdout, event.Timestamp())
+		record.Value, record.Error = value.Stdout, value.Error
+	case *exec.StdoutEvent:
+		record = e.newRecord(recordKindStdout, event.Timestamp())
+		record.Value, record.Error = value.Stdout, value.Error
+	case *exec.StdinEvent:
+		record = e.newRecord(recordKindStdin, event.Timestamp())
+		record.Value = value.Stdin
+	case Asserted:
+		record = e.newRecord(recordKindValidation, event.Timestamp())
+		var failures = make([]interface{}, 0)
+		for _, validation := range value.Assertion() {
+			record.Passed += validation.PassedCount
+			record.Failed += validation.FailedCount
+			for _, failure := range validation.Failures {
+				failures = append(failures, failure)
+			}
+		}
+		record.Status = "passed"
+		if record.Failed > 0 {
+			record.Status = "failed"
+			record.Value = failures
+		}
+	default:
+		record = e.newRecord(recordKindEvent, event.Timestamp())
+		record.Value = value
+	}
+	record.Type = event.Type()
+	return record
+}
+
+func popRecord(records *[]*EventRecord) *EventRecord {
+	count := len(*records)
+	if count == 0 {
+		return nil
+	}
+	result := (*records)[count-1]
+	*records = (*records)[:count-1]
+	return result
+}
+
+func elapsedMs(start, end time.Time) float64 {
+	return float64(end.Sub(start).Microseconds()) / 1000
+}
+
+// NewEventEncoder creates JSON Lines event encoder
+func NewEventEncoder(writer io.Writer, sessionID string) *EventEncoder {
+	return &EventEncoder{writer: writer, sessionID: sessionID}
+}
@@ -0,0 +1,100 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"github.com/viant/endly/cli"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/endly/service/workflow"
+)
+
+func TestEventEncoder_Encode(t *testing.T) {
+	buf := new(bytes.Buffer)
+	encoder := cli.NewEventEncoder(buf, "s1")
+	activity := &model.Activity{
+		MetaTag:   &model.MetaTag{TagID: "app_build"},
+		Service:   "exec",
+		Action:    "run",
+		StartTime: time.Now(),
+		Request:   map[string]interface{}{"commands": []string{"make"}},
+	}
+	events := []interface{}{
+		&workflow.WorkflowStartEvent{Name: "app", SessionID: "s1"},
+		model.NewTaskStartEvent("app", "", "build", nil, "s1", 0, nil),
+		model.NewTaskStartEvent("app", "", "compile", nil, "s1", 0, nil),
+		activity,
+		msg.NewStdoutEvent("make", "ok"),
+		&validator.AssertResponse{Validation: &assertly.Validation{PassedCount: 1, FailedCount: 1, Failures: []*assertly.Failure{{Path: "/", Message: "mismatch"}}}},
+		model.NewActivityEndEvent(activity),
+		model.NewTaskEndEvent("app", "", "compile", nil, "s1", 0, "ok", ""),
+		model.NewTaskEndEvent("app", "", "build", nil, "s1", 0, "error", "failed"),
+		&workflow.WorkflowEndEvent{Name: "app", SessionID: "s1", Status: "error"},
+		msg.NewErrorEvent("failed"),
+		map[string]interface{}{"unsupported": func() {}},
+	}
+	for _, event := range events {
+		assert.Nil(t, encoder.Encode(msg.NewEvent(event)))
+	}
+	assert.Nil(t, encoder.EncodeSummary(&cli.ReportSummaryEvent{ElapsedMs: 10, TotalTagFailed: 1}))
+
+	var records []*cli.EventRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		record := &cli.EventRecord{}
+		if !assert.Nil(t, json.Unmarshal([]byte(line), record), line) {
+			return
+		}
+		records = append(records, record)
+	}
+	if !assert.Len(t, records, len(events)+1) {
+		return
+	}
+	var useCases = []struct {
+		description string
+		kind        string
+		taskPath    string
+		action      string
+		status      string
+	}{
+		{description: "workflow start", kind: "workflow.start"},
+		{description: "task start", kind: "task.start", taskPath: "build"},
+		{description: "nested task start", kind: "task.start", taskPath: "build/compile"},
+		{description: "action start", kind: "action.start", taskPath: "build/compile", action: "run"},
+		{description: "stdout", kind: "stdout", taskPath: "build/compile", action: "run"},
+		{description: "validation", kind: "validation", taskPath: "build/compile", action: "run", status: "failed"},
+		{description: "action end", kind: "action.end", taskPath: "build/compile", action: "run"},
+		{description: "nested task end", kind: "task.end", taskPath: "build/compile", status: "ok"},
+		{description: "task end", kind: "task.end", taskPath: "build", status: "error"},
+		{description: "workflow end", kind: "workflow.end", status: "error"},
+		{description: "error", kind: "error"},
+		{description: "generic event", kind: "event"},
+		{description: "summary", kind: "summary", status: "FAILED"},
+	}
+	for i, useCase := range useCases {
+		record := records[i]
+		assert.EqualValues(t, "s1", record.SessionID, useCase.description)
+		assert.EqualValues(t, useCase.kind, record.Kind, useCase.description)
+		assert.EqualValues(t, useCase.taskPath, record.TaskPath, useCase.description)
+		assert.EqualValues(t, useCase.action, record.Action, useCase.description)
+		assert.EqualValues(t, useCase.status, record.Status, useCase.description)
+		if i < 10 { //records after workflow end are not workflow scoped
+			assert.EqualValues(t, "app", record.Workflow, useCase.description)
+		}
+	}
+	assert.EqualValues(t, "app_build", records[4].TagID)
+	assert.EqualValues(t, "ok", records[4].Value)
+	assert.EqualValues(t, 1, records[5].Passed)
+	assert.EqualValues(t, 1, records[5].Failed)
+	assert.NotNil(t, records[5].Value)
+	assert.True(t, records[8].DurationMs >= records[7].DurationMs)
+	assert.EqualValues(t, "failed", records[10].Error)
+	assert.Nil(t, records[11].Value)
+	assert.EqualValues(t, 10, records[12].DurationMs)
+}
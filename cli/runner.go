@@ -9,6 +9,7 @@ import (
 	"github.com/viant/assertly"
 	"github.com/viant/endly"
 	"github.com/viant/endly/cli/xunit"
+	"github.com/viant/endly/internal/debug"
 	"github.com/viant/endly/model"
 	"github.com/viant/endly/model/msg"
 	"github.com/viant/endly/service/system/exec"
@@ -17,11 +18,13 @@ import (
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
 	"gopkg.in/yaml.v2"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -59,6 +62,11 @@ type Runner struct {
 	hasValidationFailures bool
 	err                   error
 	group                 *MessageGroup
+	mux                   sync.Mutex //serializes events published by concurrent tasks and async actions
+	Debugger              *debug.Debugger
+	EventWriter           io.Writer //optional JSON Lines event stream writer
+	EventCloser           io.Closer //optional event stream closer, called once run ends
+	encoder               *EventEncoder
 }
 
 func (r *Runner) printInput(output string) {
@@ -587,6 +595,11 @@ func (r *Runner) reportTagSummary() {
 }
 
 func (r *Runner) reportEvent(context *endly.Context, event msg.Event, filter map[string]bool) error {
+	if r.encoder != nil {
+		if err := r.encoder.Encode(event); err != nil {
+			log.Printf("failed to encode event: %v", err)
+		}
+	}
 	eventTag := r.EventTag()
 	r.processEvent(event, filter)
 	eventTag.AddEvent(event)
@@ -596,6 +609,8 @@ func (r *Runner) reportEvent(context *endly.Context, event msg.Event, filter map
 func (r *Runner) AsListener() msg.Listener {
 	var firstEvent, lastEvent msg.Event
 	return func(event msg.Event) {
+		r.mux.Lock()
+		defer r.mux.Unlock()
 		if firstEvent == nil {
 			firstEvent = event
 		} else {
@@ -632,6 +647,11 @@ func (r *Runner) onCallerEnd() {
 	r.processEventTags()
 	r.reportSummaryEvent()
 	r.printSummary()
+	if r.encoder != nil {
+		if err := r.encoder.EncodeSummary(r.report); err != nil {
+			log.Printf("failed to encode summary: %v", err)
+		}
+	}
 }
 
 func (r *Runner) printSummary() {
@@ -662,6 +682,16 @@ func (r *Runner) printSummary() {
 
 }
 
+// closeEventOutput closes event stream once run ends, interactive run keeps publishing events till the process terminates
+func (r *Runner) closeEventOutput() {
+	if r.EventCloser == nil {
+		return
+	}
+	if err := r.EventCloser.Close(); err != nil {
+		log.Printf("failed to close event output: %v", err)
+	}
+}
+
 // Run run Caller for the supplied run request and runner options.
 func (r *Runner) Run(request *workflow.RunRequest) (err error) {
 	r.request = request
@@ -672,6 +702,10 @@ func (r *Runner) Run(request *workflow.RunRequest) (err error) {
 
 	r.report = &ReportSummaryEvent{}
 	r.context.CLIEnabled = true
+	r.context.Debugger = r.Debugger
+	if r.EventWriter != nil {
+		r.encoder = NewEventEncoder(r.EventWriter, r.context.SessionID)
+	}
 	r.filter = request.EventFilter
 	if len(r.filter) == 0 {
 		r.filter = DefaultFilter()
@@ -683,6 +717,7 @@ func (r *Runner) Run(request *workflow.RunRequest) (err error) {
 		}
 		if !request.Interactive {
 			r.context.Close()
+			r.closeEventOutput()
 		}
 		if r.hasValidationFailures || err != nil {
 			OnError(1)
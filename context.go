@@ -34,7 +34,9 @@ type Context struct {
 	context         context.Context
 	SessionID       string
 	CLIEnabled      bool
+	DryRun          bool //plan mode, workflow service publishes resolved action requests instead of running services
 	HasLogger       bool
+	LogDirectory    string //workflow log directory, set if logging is enabled
 	AsyncUnsafeKeys map[interface{}]bool
 	Secrets         *secret.Service
 	Wait            *sync.WaitGroup
@@ -48,6 +50,7 @@ type Context struct {
 	toolbox.Context
 	cloned []*Context
 	closed int32
+	parent *Context //set for derived context
 }
 
 func (c *Context) Background() context.Context {
@@ -58,6 +61,56 @@ func (c *Context) Background() context.Context {
 	return c.context
 }
 
+// WithTimeout returns a context derived from this context with background deadline, returned function cancels the derived background context
+func (c *Context) WithTimeout(timeout time.Duration) (*Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(c.Background(), timeout)
+	return c.derive(ctx), cancel
+}
+
+// WithoutTimeout returns a context derived from this context with background detached from its deadline and cancellation
+func (c *Context) WithoutTimeout() *Context {
+	return c.derive(context.WithoutCancel(c.Background()))
+}
+
+// derive returns a context sharing this context state, services and listener with supplied background context, derived context is closed with this context
+func (c *Context) derive(ctx context.Context) *Context {
+	return &Context{
+		context:         ctx,
+		parent:          c,
+		SessionID:       c.SessionID,
+		CLIEnabled:      c.CLIEnabled,
+		DryRun:          c.DryRun,
+		HasLogger:       c.HasLogger,
+		LogDirectory:    c.LogDirectory,
+		AsyncUnsafeKeys: c.AsyncUnsafeKeys,
+		Secrets:         c.Secrets,
+		Wait:            c.Wait,
+		Listener:        c.Listener,
+		Source:          c.Source,
+		Debugger:        c.Debugger,
+		state:           c.State(),
+		udfs:            c.udfs,
+		Logging:         c.Logging,
+		Context:         c.Context,
+	}
+}
+
+// TimeoutMs returns supplied timeout capped by the background context deadline, non positive timeout is returned as is
+func (c *Context) TimeoutMs(timeoutMs int) int {
+	deadline, ok := c.Background().Deadline()
+	if !ok || timeoutMs <= 0 {
+		return timeoutMs
+	}
+	remainingMs := int(time.Until(deadline) / time.Millisecond)
+	if remainingMs < 1 {
+		remainingMs = 1
+	}
+	if remainingMs < timeoutMs {
+		return remainingMs
+	}
+	return timeoutMs
+}
+
 // Publish publishes event to listeners, it updates current run details like activity workflow name etc ...
 func (c *Context) Publish(value interface{}) msg.Event {
 	event, ok := value.(msg.Event)
@@ -88,13 +141,20 @@ func (c *Context) SetListener(listener msg.Listener) {
 
 // IsClosed returns true if it is closed.
 func (c *Context) IsClosed() bool {
+	if c.parent != nil {
+		return c.parent.IsClosed()
+	}
 	return atomic.LoadInt32(&c.closed) == 1
 }
 
 // Clone clones the context.
 func (c *Context) Clone() *Context {
-	if len(c.cloned) == 0 {
-		c.cloned = make([]*Context, 0)
+	owner := c
+	for owner.parent != nil { //clones of derived context are closed with its parent
+		owner = owner.parent
+	}
+	if len(owner.cloned) == 0 {
+		owner.cloned = make([]*Context, 0)
 	}
 	result := &Context{}
 	result.Wait = &sync.WaitGroup{}
@@ -104,12 +164,16 @@ func (c *Context) Clone() *Context {
 	result.SessionID = c.SessionID
 	result.Listener = c.Listener
 	result.CLIEnabled = c.CLIEnabled
+	result.DryRun = c.DryRun
+	result.LogDirectory = c.LogDirectory
 	result.Secrets = c.Secrets
+	result.Debugger = c.Debugger
+	result.context = c.context
 	result.AsyncUnsafeKeys = make(map[interface{}]bool)
 	for k, v := range c.AsyncUnsafeKeys {
 		result.AsyncUnsafeKeys[k] = v
 	}
-	c.cloned = append(c.cloned, result)
+	owner.cloned = append(owner.cloned, result)
 	return result
 }
 
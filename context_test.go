@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewDefaultState(t *testing.T) {
@@ -106,3 +107,38 @@ func TestContext_Clone(t *testing.T) {
 	}
 
 }
+
+func TestContext_WithTimeout(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	assert.EqualValues(t, 0, context.TimeoutMs(0))
+	assert.EqualValues(t, 5000, context.TimeoutMs(5000))
+
+	derived, cancel := context.WithTimeout(time.Second)
+	_, hasDeadline := derived.Background().Deadline()
+	assert.True(t, hasDeadline)
+	_, hasDeadline = context.Background().Deadline()
+	assert.False(t, hasDeadline, "parent context should not be modified")
+	assert.EqualValues(t, 0, derived.TimeoutMs(0))
+	assert.True(t, derived.TimeoutMs(5000) <= 1000)
+	assert.EqualValues(t, 10, derived.TimeoutMs(10))
+	state := derived.State()
+	state.Put("key1", "value1")
+	assert.EqualValues(t, "value1", context.State()["key1"], "derived context should share state")
+	cloned := derived.Clone()
+	_, hasDeadline = cloned.Background().Deadline()
+	assert.True(t, hasDeadline)
+
+	detached := derived.WithoutTimeout()
+	_, hasDeadline = detached.Background().Deadline()
+	assert.False(t, hasDeadline)
+
+	cancel()
+	assert.NotNil(t, cloned.Background().Err())
+	assert.Nil(t, detached.Background().Err())
+
+	context.Close()
+	assert.True(t, derived.IsClosed())
+	assert.True(t, detached.IsClosed())
+	assert.True(t, cloned.IsClosed())
+}
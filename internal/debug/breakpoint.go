@@ -0,0 +1,39 @@
+package debug
+
+import (
+	"path"
+	"strings"
+)
+
+// Breakpoint represents a node path pattern and/or criteria pausing workflow execution
+type Breakpoint struct {
+	ID   int    `json:"id"`
+	Path string `json:"path,omitempty"` //path pattern, i.e. app/build, app/*/deploy or deploy; empty path matches any node
+	When string `json:"when,omitempty"` //optional criteria, i.e. $i > 3
+}
+
+// Matches returns true if breakpoint path matches supplied point path, pattern without '/' matches the last path segment
+func (b *Breakpoint) Matches(point *Point) bool {
+	if b.Path == "" {
+		return true
+	}
+	if matched, _ := path.Match(b.Path, point.Path); matched {
+		return true
+	}
+	if strings.Contains(b.Path, "/") {
+		return false
+	}
+	matched, _ := path.Match(b.Path, path.Base(point.Path))
+	return matched
+}
+
+// Hit returns true if breakpoint matches point and its criteria is met
+func (b *Breakpoint) Hit(point *Point) (bool, error) {
+	if !b.Matches(point) {
+		return false, nil
+	}
+	if b.When == "" || point.Eval == nil {
+		return true, nil
+	}
+	return point.Eval(b.When)
+}
@@ -0,0 +1,126 @@
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	//CommandContinue resumes execution till the next breakpoint
+	CommandContinue = "continue"
+	//CommandStep resumes execution till the next node, it steps into nested tasks, actions and workflows
+	CommandStep = "step"
+	//CommandNext resumes execution till the next node on the same or outer level, it steps over nested workflows
+	CommandNext = "next"
+	//CommandOut resumes execution till the next node on the outer level
+	CommandOut = "out"
+	//CommandBreak adds breakpoint
+	CommandBreak = "break"
+	//CommandClear removes breakpoint
+	CommandClear = "clear"
+	//CommandBreakpoints lists breakpoints
+	CommandBreakpoints = "breakpoints"
+	//CommandWhere returns paused point
+	CommandWhere = "where"
+	//CommandGet returns paused node state value
+	CommandGet = "get"
+	//CommandSet sets paused node state value
+	CommandSet = "set"
+)
+
+var commandAliases = map[string]string{
+	"c":     CommandContinue,
+	"s":     CommandStep,
+	"n":     CommandNext,
+	"o":     CommandOut,
+	"b":     CommandBreak,
+	"bl":    CommandBreakpoints,
+	"w":     CommandWhere,
+	"p":     CommandGet,
+	"print": CommandGet,
+}
+
+// Command represents debugger command
+type Command struct {
+	Command string      `json:"command"`
+	ID      int         `json:"id,omitempty"`   //breakpoint id for clear command
+	Path    string      `json:"path,omitempty"` //breakpoint path for break command
+	When    string      `json:"when,omitempty"` //breakpoint criteria for break command
+	Key     string      `json:"key,omitempty"`  //state key for get and set commands, get also accepts $expression
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// IsResume returns true if command resumes paused execution
+func (c *Command) IsResume() bool {
+	switch c.Command {
+	case CommandContinue, CommandStep, CommandNext, CommandOut:
+		return true
+	}
+	return false
+}
+
+// Response represents debugger command response
+type Response struct {
+	Command     string        `json:"command"`
+	Point       *Point        `json:"point,omitempty"`
+	Breakpoint  *Breakpoint   `json:"breakpoint,omitempty"`
+	Breakpoints []*Breakpoint `json:"breakpoints,omitempty"`
+	Value       interface{}   `json:"value,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// ParseCommand parses console command, i.e: break app/build when $i > 3, set app.version "1.2", get $params
+func ParseCommand(line string) (*Command, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return &Command{Command: CommandStep}, nil
+	}
+	name, args := line, ""
+	if index := strings.IndexAny(line, " \t"); index != -1 {
+		name, args = line[:index], strings.TrimSpace(line[index+1:])
+	}
+	name = strings.ToLower(name)
+	if alias, ok := commandAliases[name]; ok {
+		name = alias
+	}
+	var result = &Command{Command: name}
+	switch name {
+	case CommandContinue, CommandStep, CommandNext, CommandOut, CommandBreakpoints, CommandWhere:
+	case CommandBreak:
+		result.Path = args
+		if strings.HasPrefix(args, "when ") {
+			result.Path, result.When = "", strings.TrimSpace(args[5:])
+		} else if index := strings.Index(args, " when "); index != -1 {
+			result.Path, result.When = strings.TrimSpace(args[:index]), strings.TrimSpace(args[index+6:])
+		}
+		if result.Path == "" && result.When == "" {
+			return nil, fmt.Errorf("break requires path and/or 'when' criteria")
+		}
+	case CommandClear:
+		id, err := strconv.Atoi(args)
+		if err != nil {
+			return nil, fmt.Errorf("invalid breakpoint id: %v", args)
+		}
+		result.ID = id
+	case CommandGet:
+		if args == "" {
+			return nil, fmt.Errorf("get requires key")
+		}
+		result.Key = args
+	case CommandSet:
+		pair := strings.SplitN(args, " ", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("set requires key and value")
+		}
+		result.Key = pair[0]
+		value := strings.TrimSpace(pair[1])
+		if err := json.Unmarshal([]byte(value), &result.Value); err != nil {
+			result.Value = value
+		}
+	default:
+		return nil, fmt.Errorf("unknown command: %v", name)
+	}
+	return result, nil
+}
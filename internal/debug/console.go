@@ -0,0 +1,87 @@
+package debug
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Console represents interactive command line debugger frontend
+type Console struct {
+	debugger *Debugger
+	reader   io.Reader
+	writer   io.Writer
+	paused   chan bool
+}
+
+// OnPause prints paused point and command prompt
+func (c *Console) OnPause(point *Point) {
+	_, _ = fmt.Fprintf(c.writer, "\npaused at %v %v (depth: %v)\ndebug> ", point.Kind, point.Path, point.Depth)
+	select {
+	case c.paused <- true:
+	default:
+	}
+}
+
+// OnResume implements Listener
+func (c *Console) OnResume(point *Point) {}
+
+// Start reads and executes commands till reader is closed, commands other than breakpoint ones wait for paused execution
+func (c *Console) Start() {
+	scanner := bufio.NewScanner(c.reader)
+	for scanner.Scan() {
+		command, err := ParseCommand(scanner.Text())
+		if err != nil {
+			_, _ = fmt.Fprintf(c.writer, "%v\ndebug> ", err)
+			continue
+		}
+		switch command.Command {
+		case CommandBreak, CommandClear, CommandBreakpoints:
+		default:
+			if c.debugger.Paused() == nil {
+				<-c.paused
+			}
+		}
+		response := c.debugger.Execute(command)
+		if command.IsResume() && response.Error == "" {
+			select {
+			case <-c.paused:
+			default:
+			}
+			continue
+		}
+		c.print(response)
+	}
+}
+
+func (c *Console) print(response *Response) {
+	switch {
+	case response.Error != "":
+		_, _ = fmt.Fprintf(c.writer, "error: %v\n", response.Error)
+	case response.Breakpoint != nil:
+		_, _ = fmt.Fprintf(c.writer, "breakpoint %v: %v %v\n", response.Breakpoint.ID, response.Breakpoint.Path, response.Breakpoint.When)
+	case response.Command == CommandBreakpoints:
+		for _, breakpoint := range response.Breakpoints {
+			_, _ = fmt.Fprintf(c.writer, "%v: %v %v\n", breakpoint.ID, breakpoint.Path, breakpoint.When)
+		}
+	case response.Command == CommandWhere && response.Point != nil:
+		_, _ = fmt.Fprintf(c.writer, "%v %v (depth: %v)\n", response.Point.Kind, response.Point.Path, response.Point.Depth)
+	case response.Value != nil:
+		if encoded, err := json.MarshalIndent(response.Value, "", "  "); err == nil {
+			_, _ = fmt.Fprintf(c.writer, "%s\n", encoded)
+		} else {
+			_, _ = fmt.Fprintf(c.writer, "%v\n", response.Value)
+		}
+	}
+	if response.Point != nil {
+		_, _ = fmt.Fprint(c.writer, "debug> ")
+	}
+}
+
+// NewConsole creates a console frontend attached to the debugger
+func NewConsole(debugger *Debugger, reader io.Reader, writer io.Writer) *Console {
+	result := &Console{debugger: debugger, reader: reader, writer: writer, paused: make(chan bool, 1)}
+	debugger.Attach(result)
+	return result
+}
@@ -2,72 +2,233 @@ package debug
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 )
 
-// Debugger is responsible for debugging Endly workflows.
+// Listener represents debugger frontend notified when execution pauses and resumes
+type Listener interface {
+	OnPause(point *Point)
+	OnResume(point *Point)
+}
+
+type request struct {
+	command *Command
+	reply   chan *Response
+}
+
+// session represents paused execution accepting commands
+type session struct {
+	point    *Point
+	commands chan *request
+	done     chan struct{}
+}
+
+// Debugger is responsible for debugging Endly workflows, workflow service pauses on each node that matches a breakpoint
+// or current step mode, paused execution is controlled with commands sent by attached frontends.
 type Debugger struct {
-	mux          sync.Mutex
-	Breakpoints  map[Step]struct{} // Task names where the debugger will pause execution
-	StepMode     bool              // Flag to step through the workflow one task at a time
-	continueExec chan bool         // Channel used to control execution flow
+	mux         sync.Mutex
+	pauseMux    sync.Mutex //serializes concurrently running tasks hitting breakpoints
+	breakpoints []*Breakpoint
+	sequence    int
+	mode        string //continue, step, next or out
+	depth       int    //depth of the node the mode was set at
+	session     *session
+	listeners   []Listener
 }
 
-// NewDebugger creates a new debugger instance with initialized channels.
+// NewDebugger creates a new debugger instance
 func NewDebugger() *Debugger {
-	return &Debugger{
-		Breakpoints:  make(map[Step]struct{}),
-		continueExec: make(chan bool),
+	return &Debugger{mode: CommandContinue}
+}
+
+// Attach adds debugger frontend listener
+func (d *Debugger) Attach(listener Listener) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.listeners = append(d.listeners, listener)
+}
+
+// Detach removes debugger frontend listener
+func (d *Debugger) Detach(listener Listener) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	for i, candidate := range d.listeners {
+		if candidate == listener {
+			d.listeners = append(d.listeners[:i], d.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetBreakpoint adds a breakpoint for supplied path pattern and optional criteria
+func (d *Debugger) SetBreakpoint(path, when string) *Breakpoint {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.sequence++
+	breakpoint := &Breakpoint{ID: d.sequence, Path: strings.TrimSpace(path), When: strings.TrimSpace(when)}
+	d.breakpoints = append(d.breakpoints, breakpoint)
+	return breakpoint
+}
+
+// RemoveBreakpoint removes a breakpoint by its id
+func (d *Debugger) RemoveBreakpoint(id int) bool {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	for i, candidate := range d.breakpoints {
+		if candidate.ID == id {
+			d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+			return true
+		}
 	}
+	return false
 }
 
-// SetBreakpoint sets a breakpoint on a task by its name.
-func (d *Debugger) SetBreakpoint(step Step) {
+// Breakpoints returns breakpoints
+func (d *Debugger) Breakpoints() []*Breakpoint {
 	d.mux.Lock()
 	defer d.mux.Unlock()
-	d.Breakpoints[step] = struct{}{}
+	var result = make([]*Breakpoint, len(d.breakpoints))
+	copy(result, d.breakpoints)
+	return result
 }
 
-// RemoveBreakpoint removes a breakpoint from a task by its name.
-func (d *Debugger) RemoveBreakpoint(breakpoint Step) {
+// EnableStepMode enables or disables step mode, in step mode execution pauses before each node.
+func (d *Debugger) EnableStepMode(enable bool) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
-	delete(d.Breakpoints, breakpoint)
+	d.mode = CommandContinue
+	if enable {
+		d.mode = CommandStep
+	}
 }
 
-// BeforeTaskExecution is modified to pause at breakpoints or in step mode, waiting for channel input to continue.
-func (d *Debugger) BeforeTaskExecution(step Step, request interface{}) {
-	fmt.Printf("Before executing task %s: request = %+v\n", step, request)
-	hasBreakpoint := false
+// Paused returns paused point or nil
+func (d *Debugger) Paused() *Point {
 	d.mux.Lock()
-	_, hasBreakpoint = d.Breakpoints[step]
+	defer d.mux.Unlock()
+	if d.session == nil {
+		return nil
+	}
+	return d.session.point
+}
+
+// Pause blocks execution of the supplied point if it matches current step mode or any breakpoint, till resume command is executed
+func (d *Debugger) Pause(point *Point) {
+	d.pauseMux.Lock()
+	defer d.pauseMux.Unlock()
+	if !d.shouldPause(point) {
+		return
+	}
+	paused := &session{point: point, commands: make(chan *request), done: make(chan struct{})}
+	d.mux.Lock()
+	d.session = paused
+	listeners := append([]Listener{}, d.listeners...)
 	d.mux.Unlock()
+	for _, listener := range listeners {
+		listener.OnPause(point)
+	}
+	for req := range paused.commands {
+		response := d.handle(point, req.command)
+		if req.command.IsResume() {
+			d.mux.Lock()
+			d.mode = req.command.Command
+			d.depth = point.Depth
+			d.session = nil
+			d.mux.Unlock()
+			close(paused.done)
+			req.reply <- response
+			break
+		}
+		req.reply <- response
+	}
+	for _, listener := range listeners {
+		listener.OnResume(point)
+	}
+}
 
-	if hasBreakpoint || d.StepMode {
-		fmt.Println("Execution paused. Press enter to continue...")
-		go func() {
-			fmt.Scanln()           // Wait for user input
-			d.continueExec <- true // Signal to continue execution
-		}()
-		<-d.continueExec // Wait for signal to continue
+// Execute executes debugger command, execution control and state commands require paused execution
+func (d *Debugger) Execute(command *Command) *Response {
+	switch command.Command {
+	case CommandBreak, CommandClear, CommandBreakpoints:
+		return d.handle(d.Paused(), command)
+	}
+	d.mux.Lock()
+	paused := d.session
+	d.mux.Unlock()
+	if paused == nil {
+		return &Response{Command: command.Command, Error: "execution is not paused"}
+	}
+	req := &request{command: command, reply: make(chan *Response, 1)}
+	select {
+	case paused.commands <- req:
+		return <-req.reply
+	case <-paused.done:
+		return &Response{Command: command.Command, Error: "execution is not paused"}
 	}
 }
 
-// AfterTaskExecution logs task results, similar to the previous implementation.
-func (d *Debugger) AfterTaskExecution(step Step, result interface{}) {
-	fmt.Printf("After executing task %s: result = %+v\n", step, result)
-	if d.StepMode {
-		// In step mode, pause after each task execution.
-		fmt.Println("Step execution paused. Press enter to continue to the next task...")
-		go func() {
-			fmt.Scanln()           // Wait for user input
-			d.continueExec <- true // Signal to continue execution
-		}()
-		<-d.continueExec // Wait for signal to continue
+func (d *Debugger) shouldPause(point *Point) bool {
+	d.mux.Lock()
+	mode, depth := d.mode, d.depth
+	breakpoints := append([]*Breakpoint{}, d.breakpoints...)
+	d.mux.Unlock()
+	switch mode {
+	case CommandStep:
+		return true
+	case CommandNext:
+		if point.Depth <= depth {
+			return true
+		}
+	case CommandOut:
+		if point.Depth < depth {
+			return true
+		}
+	}
+	for _, breakpoint := range breakpoints {
+		if hit, _ := breakpoint.Hit(point); hit {
+			return true
+		}
 	}
+	return false
 }
 
-// EnableStepMode enables or disables step mode.
-func (d *Debugger) EnableStepMode(enable bool) {
-	d.StepMode = enable
+func (d *Debugger) handle(point *Point, command *Command) *Response {
+	var response = &Response{Command: command.Command, Point: point}
+	switch command.Command {
+	case CommandContinue, CommandStep, CommandNext, CommandOut, CommandWhere:
+	case CommandBreak:
+		if command.Path == "" && command.When == "" {
+			response.Error = "breakpoint requires path and/or when criteria"
+			break
+		}
+		response.Breakpoint = d.SetBreakpoint(command.Path, command.When)
+	case CommandClear:
+		if !d.RemoveBreakpoint(command.ID) {
+			response.Error = fmt.Sprintf("unknown breakpoint: %v", command.ID)
+		}
+	case CommandBreakpoints:
+		response.Breakpoints = d.Breakpoints()
+	case CommandGet:
+		if strings.Contains(command.Key, "$") {
+			response.Value = point.State.Expand(command.Key)
+			break
+		}
+		value, ok := point.State.GetValue(command.Key)
+		if !ok {
+			response.Error = fmt.Sprintf("undefined state key: %v", command.Key)
+			break
+		}
+		response.Value = value
+	case CommandSet:
+		if command.Key == "" {
+			response.Error = "state key was empty"
+			break
+		}
+		point.State.SetValue(command.Key, command.Value)
+		response.Value = command.Value
+	default:
+		response.Error = fmt.Sprintf("unknown command: %v", command.Command)
+	}
+	return response
 }
@@ -0,0 +1,226 @@
+package debug
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+)
+
+func TestParseCommand(t *testing.T) {
+	var useCases = []struct {
+		description string
+		line        string
+		expect      *Command
+		hasError    bool
+	}{
+		{description: "empty line steps", line: "", expect: &Command{Command: CommandStep}},
+		{description: "alias", line: "n", expect: &Command{Command: CommandNext}},
+		{description: "path breakpoint", line: "break app/build", expect: &Command{Command: CommandBreak, Path: "app/build"}},
+		{description: "conditional breakpoint", line: "b deploy when $i > 3", expect: &Command{Command: CommandBreak, Path: "deploy", When: "$i > 3"}},
+		{description: "criteria breakpoint", line: "b when $i > 3", expect: &Command{Command: CommandBreak, When: "$i > 3"}},
+		{description: "clear", line: "clear 2", expect: &Command{Command: CommandClear, ID: 2}},
+		{description: "set json value", line: "set app.replicas 3", expect: &Command{Command: CommandSet, Key: "app.replicas", Value: float64(3)}},
+		{description: "set text value", line: "set app.name my app", expect: &Command{Command: CommandSet, Key: "app.name", Value: "my app"}},
+		{description: "get", line: "p $params", expect: &Command{Command: CommandGet, Key: "$params"}},
+		{description: "invalid clear", line: "clear x", hasError: true},
+		{description: "unknown", line: "jump", hasError: true},
+	}
+	for _, useCase := range useCases {
+		actual, err := ParseCommand(useCase.line)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestBreakpoint_Matches(t *testing.T) {
+	var useCases = []struct {
+		pattern string
+		path    string
+		expect  bool
+	}{
+		{pattern: "app/build", path: "app/build", expect: true},
+		{pattern: "app/*", path: "app/build", expect: true},
+		{pattern: "app/*", path: "app/build/checkout", expect: false},
+		{pattern: "*/build/*", path: "app/build/checkout", expect: true},
+		{pattern: "checkout", path: "app/build/checkout", expect: true},
+		{pattern: "build", path: "app/build/checkout", expect: false},
+		{pattern: "", path: "app", expect: true},
+	}
+	for _, useCase := range useCases {
+		breakpoint := &Breakpoint{Path: useCase.pattern}
+		assert.EqualValues(t, useCase.expect, breakpoint.Matches(&Point{Path: useCase.path}), useCase.pattern+" "+useCase.path)
+	}
+}
+
+type recorder struct {
+	paused chan *Point
+}
+
+func (r *recorder) OnPause(point *Point) { r.paused <- point }
+
+func (r *recorder) OnResume(point *Point) {}
+
+// run pauses on supplied points in the background and returns channel closed once all points passed
+func run(debugger *Debugger, points ...*Point) chan bool {
+	done := make(chan bool)
+	go func() {
+		for _, point := range points {
+			debugger.Pause(point)
+		}
+		close(done)
+	}()
+	return done
+}
+
+func TestDebugger_Pause(t *testing.T) {
+	newPoints := func() []*Point {
+		return []*Point{
+			{Kind: KindWorkflow, Path: "app", Depth: 0, State: data.NewMap()},
+			{Kind: KindTask, Path: "app/build", Depth: 1, State: data.NewMap()},
+			{Kind: KindAction, Path: "app/build/run", Depth: 2, State: data.NewMap()},
+			{Kind: KindWorkflow, Path: "sub", Depth: 3, State: data.NewMap()},
+			{Kind: KindAction, Path: "sub/task/print", Depth: 5, State: data.NewMap()},
+			{Kind: KindTask, Path: "app/deploy", Depth: 1, State: data.NewMap()},
+		}
+	}
+	var useCases = []struct {
+		description string
+		breakpoints []string
+		commands    []string
+		expect      []string
+	}{
+		{
+			description: "step into nested workflow",
+			commands:    []string{CommandStep, CommandStep, CommandStep, CommandStep, CommandStep, CommandStep},
+			expect:      []string{"app", "app/build", "app/build/run", "sub", "sub/task/print", "app/deploy"},
+		},
+		{
+			description: "step over nested workflow",
+			commands:    []string{CommandStep, CommandStep, CommandNext, CommandContinue},
+			expect:      []string{"app", "app/build", "app/build/run", "app/deploy"},
+		},
+		{
+			description: "step out of nested workflow",
+			breakpoints: []string{"sub"},
+			commands:    []string{CommandOut, CommandContinue},
+			expect:      []string{"sub", "app/deploy"},
+		},
+		{
+			description: "breakpoints",
+			breakpoints: []string{"run", "app/deploy"},
+			commands:    []string{CommandContinue, CommandContinue},
+			expect:      []string{"app/build/run", "app/deploy"},
+		},
+	}
+	for _, useCase := range useCases {
+		debugger := NewDebugger()
+		listener := &recorder{paused: make(chan *Point)}
+		debugger.Attach(listener)
+		if len(useCase.breakpoints) == 0 {
+			debugger.EnableStepMode(true)
+		}
+		for _, breakpoint := range useCase.breakpoints {
+			debugger.SetBreakpoint(breakpoint, "")
+		}
+		done := run(debugger, newPoints()...)
+		var actual []string
+		for _, command := range useCase.commands {
+			select {
+			case point := <-listener.paused:
+				actual = append(actual, point.Path)
+			case <-time.After(time.Second):
+				assert.Fail(t, "expected pause", useCase.description)
+			}
+			response := debugger.Execute(&Command{Command: command})
+			assert.EqualValues(t, "", response.Error, useCase.description)
+		}
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.Fail(t, "expected completion", useCase.description)
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestDebugger_State(t *testing.T) {
+	debugger := NewDebugger()
+	listener := &recorder{paused: make(chan *Point)}
+	debugger.Attach(listener)
+	breakpoint := debugger.SetBreakpoint("build", "$i > 1")
+	assert.EqualValues(t, "execution is not paused", debugger.Execute(&Command{Command: CommandContinue}).Error)
+
+	state := data.NewMap()
+	state.Put("i", 2)
+	evaluate := func(criteria string) (bool, error) {
+		return criteria == breakpoint.When && state.GetInt("i") > 1, nil
+	}
+	done := run(debugger,
+		&Point{Kind: KindTask, Path: "app/build", State: state},
+		&Point{Kind: KindTask, Path: "app/build", State: data.NewMap(), Eval: func(string) (bool, error) { return false, nil }},
+		&Point{Kind: KindTask, Path: "app/build", State: state, Eval: evaluate},
+	)
+	point := <-listener.paused
+	assert.EqualValues(t, "app/build", point.Path)
+
+	response := debugger.Execute(&Command{Command: CommandGet, Key: "i"})
+	assert.EqualValues(t, 2, response.Value)
+	response = debugger.Execute(&Command{Command: CommandSet, Key: "app.version", Value: "1.2"})
+	assert.EqualValues(t, "", response.Error)
+	version, _ := state.GetValue("app.version")
+	assert.EqualValues(t, "1.2", version)
+	response = debugger.Execute(&Command{Command: CommandGet, Key: "v$app.version"})
+	assert.EqualValues(t, "v1.2", response.Value)
+	response = debugger.Execute(&Command{Command: CommandGet, Key: "missing"})
+	assert.NotEqual(t, "", response.Error)
+
+	debugger.Execute(&Command{Command: CommandContinue})
+	<-listener.paused
+	assert.True(t, debugger.Execute(&Command{Command: CommandClear, ID: breakpoint.ID}).Error == "")
+	debugger.Execute(&Command{Command: CommandContinue})
+	<-done
+}
+
+func TestServer(t *testing.T) {
+	debugger := NewDebugger()
+	debugger.EnableStepMode(true)
+	server := NewServer(debugger, "")
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+	done := run(debugger, &Point{Kind: KindWorkflow, Path: "app", State: data.NewMap()})
+	for debugger.Paused() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	event := &Event{}
+	if !assert.Nil(t, conn.ReadJSON(event)) {
+		return
+	}
+	assert.EqualValues(t, EventPaused, event.Event)
+	assert.EqualValues(t, "app", event.Point.Path)
+
+	assert.Nil(t, conn.WriteJSON(&Command{Command: CommandSet, Key: "x", Value: 1}))
+	response := &Response{}
+	assert.Nil(t, conn.ReadJSON(response))
+	assert.EqualValues(t, CommandSet, response.Command)
+	assert.EqualValues(t, "", response.Error)
+
+	assert.Nil(t, conn.WriteJSON(&Command{Command: CommandContinue}))
+	<-done
+}
@@ -0,0 +1,21 @@
+package debug
+
+import "github.com/viant/toolbox/data"
+
+const (
+	//KindWorkflow represents workflow execution point
+	KindWorkflow = "workflow"
+	//KindTask represents task execution point
+	KindTask = "task"
+	//KindAction represents action execution point
+	KindAction = "action"
+)
+
+// Point represents workflow node about to be executed
+type Point struct {
+	Kind  string                              `json:"kind"`  //workflow, task or action
+	Path  string                              `json:"path"`  //workflow/task/action path
+	Depth int                                 `json:"depth"` //node nesting level, nested workflow nodes are deeper than the caller ones
+	State data.Map                            `json:"-"`
+	Eval  func(criteria string) (bool, error) `json:"-"` //evaluates criteria against the node state
+}
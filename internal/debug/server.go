@@ -0,0 +1,150 @@
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	//EventPaused represents paused execution notification
+	EventPaused = "paused"
+	//EventResumed represents resumed execution notification
+	EventResumed = "resumed"
+)
+
+// defaultHost represents host the debugger binds to if address does not specify one
+const defaultHost = "127.0.0.1"
+
+// upgrader uses websocket default origin check, it rejects browser requests with Origin other than request Host
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// Event represents notification sent to attached WebSocket clients
+type Event struct {
+	Event string `json:"event"`
+	Point *Point `json:"point"`
+}
+
+type client struct {
+	conn *websocket.Conn
+	mux  sync.Mutex
+}
+
+func (c *client) write(value interface{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.conn.WriteJSON(value)
+}
+
+// Server represents JSON over WebSocket debugger frontend, clients send Command and receive Response and Event messages
+type Server struct {
+	debugger *Debugger
+	address  string
+	mux      sync.Mutex
+	clients  map[*client]bool
+}
+
+// OnPause notifies attached clients
+func (s *Server) OnPause(point *Point) {
+	s.broadcast(&Event{Event: EventPaused, Point: point})
+}
+
+// OnResume notifies attached clients
+func (s *Server) OnResume(point *Point) {
+	s.broadcast(&Event{Event: EventResumed, Point: point})
+}
+
+func (s *Server) broadcast(event *Event) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for client := range s.clients {
+		_ = client.write(event)
+	}
+}
+
+// Handler returns WebSocket handler
+func (s *Server) Handler() http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		conn, err := upgrader.Upgrade(writer, request, nil)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		client := &client{conn: conn}
+		s.mux.Lock()
+		s.clients[client] = true
+		s.mux.Unlock()
+		defer func() {
+			s.mux.Lock()
+			delete(s.clients, client)
+			s.mux.Unlock()
+			_ = conn.Close()
+		}()
+		if point := s.debugger.Paused(); point != nil {
+			_ = client.write(&Event{Event: EventPaused, Point: point})
+		}
+		for {
+			command := &Command{}
+			if err := conn.ReadJSON(command); err != nil {
+				var syntaxErr *json.SyntaxError
+				var typeErr *json.UnmarshalTypeError
+				if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+					return
+				}
+				if err = client.write(&Response{Error: fmt.Sprintf("invalid command: %v", err)}); err != nil {
+					return
+				}
+				continue
+			}
+			if err := client.write(s.debugger.Execute(command)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Start starts WebSocket server in the background, clients attach at ws://<address>/debug
+func (s *Server) Start() error {
+	mux := http.NewServeMux()
+	mux.Handle("/debug", s.Handler())
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to start debugger on %v: %w", s.address, err)
+	}
+	go func() {
+		_ = http.Serve(listener, mux)
+	}()
+	return nil
+}
+
+// Address returns server listening address
+func (s *Server) Address() string {
+	return s.address
+}
+
+// listenAddress returns address bound to the loopback interface if host was not specified, i.e. :8071 or 8071
+func listenAddress(address string) string {
+	if !strings.Contains(address, ":") {
+		return net.JoinHostPort(defaultHost, address)
+	}
+	if host, port, err := net.SplitHostPort(address); err == nil && host == "" {
+		return net.JoinHostPort(defaultHost, port)
+	}
+	return address
+}
+
+// NewServer creates a WebSocket frontend attached to the debugger
+func NewServer(debugger *Debugger, address string) *Server {
+	result := &Server{debugger: debugger, address: listenAddress(address), clients: map[*client]bool{}}
+	debugger.Attach(result)
+	return result
+}
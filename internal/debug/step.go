@@ -1,6 +0,0 @@
-package debug
-
-type Step struct {
-	Workflow string
-	TaskName string
-}
@@ -0,0 +1,181 @@
+package descriptor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/viant/toolbox"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Source represents protobuf descriptors source
+type Source struct {
+	ProtoFile     string   `description:".proto file defining services"`
+	ImportPaths   []string `description:"proto import paths, default: proto file directory"`
+	DescriptorSet string   `description:"binary FileDescriptorSet file, i.e. produced by protoc --include_imports --descriptor_set_out"`
+}
+
+// IsEmpty returns true if neither proto file nor descriptor set was specified
+func (s *Source) IsEmpty() bool {
+	return s.ProtoFile == "" && s.DescriptorSet == ""
+}
+
+// Load loads file descriptors
+func (s *Source) Load() ([]*desc.FileDescriptor, error) {
+	var result = make([]*desc.FileDescriptor, 0)
+	if s.ProtoFile != "" {
+		importPaths := s.ImportPaths
+		filename := s.ProtoFile
+		if len(importPaths) == 0 {
+			var dir string
+			dir, filename = path.Split(s.ProtoFile)
+			importPaths = []string{dir}
+		}
+		parser := protoparse.Parser{ImportPaths: importPaths}
+		files, err := parser.ParseFiles(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %v: %w", s.ProtoFile, err)
+		}
+		result = append(result, files...)
+	}
+	if s.DescriptorSet != "" {
+		data, err := os.ReadFile(s.DescriptorSet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load descriptor set %v: %w", s.DescriptorSet, err)
+		}
+		descriptorSet := &descriptorpb.FileDescriptorSet{}
+		if err = proto.Unmarshal(data, descriptorSet); err != nil {
+			return nil, fmt.Errorf("failed to decode descriptor set %v: %w", s.DescriptorSet, err)
+		}
+		files, err := desc.CreateFileDescriptorsFromSet(descriptorSet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid descriptor set %v: %w", s.DescriptorSet, err)
+		}
+		for _, file := range files {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+// SplitMethod splits fully qualified method name into service and method name, supported forms:
+// package.Service/Method, /package.Service/Method and package.Service.Method
+func SplitMethod(name string) (string, string, error) {
+	name = strings.TrimPrefix(name, "/")
+	index := strings.LastIndex(name, "/")
+	if index == -1 {
+		index = strings.LastIndex(name, ".")
+	}
+	if index <= 0 || index == len(name)-1 {
+		return "", "", fmt.Errorf("invalid method: %v, expected package.Service/Method", name)
+	}
+	return name[:index], name[index+1:], nil
+}
+
+// FindService returns service descriptor
+func FindService(files []*desc.FileDescriptor, name string) (*desc.ServiceDescriptor, error) {
+	for _, file := range files {
+		if service := file.FindService(name); service != nil {
+			return service, nil
+		}
+		for _, dependency := range file.GetDependencies() {
+			if service := dependency.FindService(name); service != nil {
+				return service, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("service %v was not found", name)
+}
+
+// FindMethod returns method descriptor for fully qualified method name
+func FindMethod(files []*desc.FileDescriptor, name string) (*desc.MethodDescriptor, error) {
+	serviceName, methodName, err := SplitMethod(name)
+	if err != nil {
+		return nil, err
+	}
+	service, err := FindService(files, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return Method(service, methodName)
+}
+
+// Method returns service method descriptor
+func Method(service *desc.ServiceDescriptor, name string) (*desc.MethodDescriptor, error) {
+	method := service.FindMethodByName(name)
+	if method == nil {
+		return nil, fmt.Errorf("method %v was not found in %v", name, service.GetFullyQualifiedName())
+	}
+	return method, nil
+}
+
+// NewResolver creates descriptor resolver with supplied files and their dependencies
+func NewResolver(files []*desc.FileDescriptor) (*protoregistry.Files, error) {
+	var result = new(protoregistry.Files)
+	var register func(file *desc.FileDescriptor) error
+	register = func(file *desc.FileDescriptor) error {
+		if _, err := result.FindFileByPath(file.GetName()); err == nil {
+			return nil
+		}
+		for _, dependency := range file.GetDependencies() {
+			if err := register(dependency); err != nil {
+				return err
+			}
+		}
+		return result.RegisterFile(file.UnwrapFile())
+	}
+	for _, file := range files {
+		if err := register(file); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// NewMessage creates dynamic message from JSON text or JSON compatible data structure
+func NewMessage(descriptor *desc.MessageDescriptor, value interface{}) (*dynamic.Message, error) {
+	result := dynamic.NewMessage(descriptor)
+	if value == nil {
+		return result, nil
+	}
+	var text string
+	switch actual := value.(type) {
+	case string:
+		text = actual
+	case []byte:
+		text = string(actual)
+	default:
+		var err error
+		if text, err = toolbox.AsJSONText(value); err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(text) == "" {
+		return result, nil
+	}
+	if err := result.UnmarshalJSON([]byte(text)); err != nil {
+		return nil, fmt.Errorf("failed to build %v message: %w", descriptor.GetFullyQualifiedName(), err)
+	}
+	return result, nil
+}
+
+// AsData converts protobuf message into JSON compatible data structure
+func AsData(message protoiface.MessageV1) (interface{}, error) {
+	dynamicMessage, err := dynamic.AsDynamicMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	JSON, err := dynamicMessage.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return toolbox.JSONToInterface(string(JSON))
+}
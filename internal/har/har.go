@@ -0,0 +1,280 @@
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/viant/endly/model/location"
+	"github.com/viant/toolbox/bridge"
+)
+
+// Version represents supported HAR spec version
+const Version = "1.2"
+
+// Document represents HTTP Archive file
+type Document struct {
+	Log *Log `json:"log"`
+}
+
+// Log represents HAR log
+type Log struct {
+	Version string   `json:"version"`
+	Creator *Creator `json:"creator"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Creator represents HAR creator
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry represents recorded HTTP trip
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Request         *Request  `json:"request"`
+	Response        *Response `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         *Timings  `json:"timings"`
+}
+
+// Request represents HAR request
+type Request struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*NameValue `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	QueryString []*NameValue `json:"queryString"`
+	PostData    *PostData    `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+// Response represents HAR response
+type Response struct {
+	Status      int          `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*NameValue `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	Content     *Content     `json:"content"`
+	RedirectURL string       `json:"redirectURL"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+// NameValue represents HAR header, cookie or query parameter
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData represents HAR request body
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content represents HAR response body
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Timings represents HAR entry timings in ms
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Body returns decoded response body
+func (c *Content) Body() (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	if c.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(c.Text)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode response content: %w", err)
+		}
+		return string(decoded), nil
+	}
+	return c.Text, nil
+}
+
+// Header returns HAR request headers as http.Header
+func (r *Request) Header() http.Header {
+	return asHeader(r.Headers)
+}
+
+// Body returns request body
+func (r *Request) Body() string {
+	if r.PostData == nil {
+		return ""
+	}
+	return r.PostData.Text
+}
+
+// Header returns HAR response headers as http.Header
+func (r *Response) Header() http.Header {
+	return asHeader(r.Headers)
+}
+
+// AsTrip converts entry to recorded http trip
+func (e *Entry) AsTrip() (*bridge.RecordedHttpTrip, error) {
+	if e.Request == nil || e.Response == nil {
+		return nil, fmt.Errorf("invalid entry: request and response are required")
+	}
+	body, err := e.Response.Content.Body()
+	if err != nil {
+		return nil, err
+	}
+	return &bridge.RecordedHttpTrip{
+		Request: &bridge.HttpRequest{
+			Method: e.Request.Method,
+			URL:    e.Request.URL,
+			Header: e.Request.Header(),
+			Body:   e.Request.Body(),
+		},
+		Response: &bridge.HttpResponse{
+			Code:   e.Response.Status,
+			Header: e.Response.Header(),
+			Body:   body,
+		},
+	}, nil
+}
+
+// Trips returns recorded http trips
+func (d *Document) Trips() ([]*bridge.RecordedHttpTrip, error) {
+	var result = make([]*bridge.RecordedHttpTrip, 0)
+	if d.Log == nil {
+		return result, nil
+	}
+	for i, entry := range d.Log.Entries {
+		trip, err := entry.AsTrip()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert entry[%d]: %w", i, err)
+		}
+		result = append(result, trip)
+	}
+	return result, nil
+}
+
+// NewEntry creates HAR entry for supplied trip
+func NewEntry(started time.Time, elapsed time.Duration, request *bridge.HttpRequest, response *bridge.HttpResponse) *Entry {
+	elapsedMs := float64(elapsed.Microseconds()) / 1000
+	harRequest := &Request{
+		Method:      request.Method,
+		URL:         request.URL,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []*NameValue{},
+		Headers:     asNameValues(request.Header),
+		QueryString: []*NameValue{},
+		HeadersSize: -1,
+		BodySize:    len(request.Body),
+	}
+	if URL, err := url.Parse(request.URL); err == nil {
+		harRequest.QueryString = asNameValues(URL.Query())
+	}
+	if request.Body != "" {
+		harRequest.PostData = &PostData{MimeType: request.Header.Get("Content-Type"), Text: request.Body}
+	}
+	content := &Content{Size: len(response.Body), MimeType: response.Header.Get("Content-Type"), Text: response.Body}
+	if !isText(content.MimeType, response.Body) {
+		content.Text = base64.StdEncoding.EncodeToString([]byte(response.Body))
+		content.Encoding = "base64"
+	}
+	return &Entry{
+		StartedDateTime: started,
+		Time:            elapsedMs,
+		Request:         harRequest,
+		Response: &Response{
+			Status:      response.Code,
+			StatusText:  http.StatusText(response.Code),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []*NameValue{},
+			Headers:     asNameValues(response.Header),
+			Content:     content,
+			RedirectURL: response.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(response.Body),
+		},
+		Timings: &Timings{Send: 0, Wait: elapsedMs, Receive: 0},
+	}
+}
+
+// New creates an empty HAR document
+func New(creator, version string) *Document {
+	return &Document{Log: &Log{Version: Version, Creator: &Creator{Name: creator, Version: version}, Entries: []*Entry{}}}
+}
+
+// Decode decodes HAR document
+func Decode(data []byte) (*Document, error) {
+	result := &Document{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("failed to decode HAR: %w", err)
+	}
+	if result.Log == nil {
+		return nil, fmt.Errorf("invalid HAR: log was empty")
+	}
+	return result, nil
+}
+
+// Load loads HAR document from supplied location
+func Load(URL string) (*Document, error) {
+	text, err := location.NewResource(URL).DownloadText()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load HAR %v: %w", URL, err)
+	}
+	return Decode([]byte(text))
+}
+
+func asHeader(pairs []*NameValue) http.Header {
+	var result = make(http.Header)
+	for _, pair := range pairs {
+		if strings.HasPrefix(pair.Name, ":") { //HTTP/2 pseudo headers
+			continue
+		}
+		result.Add(pair.Name, pair.Value)
+	}
+	return result
+}
+
+func asNameValues(values map[string][]string) []*NameValue {
+	var names = make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result = make([]*NameValue, 0)
+	for _, name := range names {
+		for _, value := range values[name] {
+			result = append(result, &NameValue{Name: name, Value: value})
+		}
+	}
+	return result
+}
+
+func isText(mimeType, body string) bool {
+	if mimeType == "" {
+		return !strings.ContainsRune(body, 0)
+	}
+	mimeType = strings.ToLower(mimeType)
+	for _, candidate := range []string{"text/", "json", "xml", "javascript", "x-www-form-urlencoded", "html"} {
+		if strings.Contains(mimeType, candidate) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package har
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/bridge"
+)
+
+func TestRecorder_Append(t *testing.T) {
+	filename := path.Join(t.TempDir(), "trips.har")
+	recorder := NewRecorder(filename, "endly", "test")
+
+	var useCases = []struct {
+		description string
+		request     *bridge.HttpRequest
+		response    *bridge.HttpResponse
+		encoding    string
+	}{
+		{
+			description: "json trip",
+			request:     &bridge.HttpRequest{Method: "POST", URL: "http://localhost/users?limit=1", Header: http.Header{"Content-Type": {"application/json"}}, Body: `{"name":"Bob"}`},
+			response:    &bridge.HttpResponse{Code: 201, Header: http.Header{"Content-Type": {"application/json"}}, Body: `{"id":1}`},
+		},
+		{
+			description: "binary trip",
+			request:     &bridge.HttpRequest{Method: "GET", URL: "http://localhost/logo.png", Header: http.Header{}},
+			response:    &bridge.HttpResponse{Code: 200, Header: http.Header{"Content-Type": {"image/png"}}, Body: "\x89PNG\x00\x01"},
+			encoding:    "base64",
+		},
+	}
+	for _, useCase := range useCases {
+		entry := NewEntry(time.Now(), time.Millisecond, useCase.request, useCase.response)
+		assert.EqualValues(t, useCase.encoding, entry.Response.Content.Encoding, useCase.description)
+		recorder.Append(entry)
+	}
+	_, err := os.Stat(filename)
+	assert.True(t, os.IsNotExist(err), "entries should be buffered until close")
+	if !assert.Nil(t, recorder.Close()) {
+		return
+	}
+
+	data, err := os.ReadFile(filename)
+	if !assert.Nil(t, err) {
+		return
+	}
+	document, err := Decode(data)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, Version, document.Log.Version)
+	trips, err := document.Trips()
+	if !assert.Nil(t, err) || !assert.Len(t, trips, len(useCases)) {
+		return
+	}
+	for i, useCase := range useCases {
+		assert.EqualValues(t, useCase.request.Method, trips[i].Request.Method, useCase.description)
+		assert.EqualValues(t, useCase.request.URL, trips[i].Request.URL, useCase.description)
+		assert.EqualValues(t, useCase.request.Body, trips[i].Request.Body, useCase.description)
+		assert.EqualValues(t, useCase.response.Code, trips[i].Response.Code, useCase.description)
+		assert.EqualValues(t, useCase.response.Body, trips[i].Response.Body, useCase.description)
+		assert.EqualValues(t, useCase.response.Header.Get("Content-Type"), trips[i].Response.Header.Get("Content-Type"), useCase.description)
+	}
+	assert.EqualValues(t, "limit", document.Log.Entries[0].Request.QueryString[0].Name)
+}
+
+func TestRecorder_Handler(t *testing.T) {
+	filename := path.Join(t.TempDir(), "trips.har")
+	recorder := NewRecorder(filename, "endly", "test")
+	handler := bridge.NewListeningHandler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = writer.Write([]byte("ok"))
+	}), 1, 1024, recorder.Record)
+	started := time.Now()
+	request := httptest.NewRequest("GET", "http://localhost/status", nil)
+	recorder.Handler(handler).ServeHTTP(httptest.NewRecorder(), request)
+	if !assert.Len(t, recorder.document.Log.Entries, 1) {
+		return
+	}
+	entry := recorder.document.Log.Entries[0]
+	assert.False(t, entry.StartedDateTime.Before(started))
+	assert.True(t, entry.StartedDateTime.Before(started.Add(20*time.Millisecond)), "entry should start before round trip")
+	assert.True(t, entry.Time >= 20, "entry time should include round trip duration")
+	assert.EqualValues(t, "ok", entry.Response.Content.Text)
+}
+
+func TestDecode(t *testing.T) {
+	_, err := Decode([]byte(`{}`))
+	assert.NotNil(t, err)
+	_, err = Decode([]byte(`{"log":{"version":"1.2","entries":[{"request":{"method":"GET","url":"http://localhost/"}}]}}`))
+	assert.Nil(t, err)
+}
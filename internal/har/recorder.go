@@ -0,0 +1,101 @@
+package har
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/viant/toolbox/bridge"
+)
+
+// Recorder represents HAR file recorder, entries are buffered in memory and written with Flush or Close
+type Recorder struct {
+	filename string
+	document *Document
+	mux      sync.Mutex
+}
+
+// Append appends entry to the recorded document
+func (r *Recorder) Append(entry *Entry) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.document.Log.Entries = append(r.document.Log.Entries, entry)
+}
+
+// Flush writes HAR file with all recorded entries
+func (r *Recorder) Flush() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	data, err := json.MarshalIndent(r.document, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.filename, data, 0644)
+}
+
+// Close writes HAR file, it has to be called once recording is done
+func (r *Recorder) Close() error {
+	return r.Flush()
+}
+
+// startedKey represents request context key with the trip start time
+type startedKey struct{}
+
+// Handler returns handler recording trip start time into the request context, it has to wrap bridge listening handler,
+// so that recorded entries have actual trip start time and duration
+func (r *Recorder) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		ctx := context.WithValue(request.Context(), startedKey{}, time.Now())
+		handler.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// Record records proxied HTTP trip, it can be used as bridge route listener
+func (r *Recorder) Record(request *http.Request, response *http.Response) {
+	started, ok := request.Context().Value(startedKey{}).(time.Time)
+	if !ok {
+		started = time.Now()
+	}
+	elapsed := time.Since(started)
+	httpRequest := &bridge.HttpRequest{
+		Method: request.Method,
+		URL:    requestURL(request),
+		Header: request.Header,
+		Body:   readBody(request.Body),
+	}
+	httpResponse := &bridge.HttpResponse{
+		Code:   response.StatusCode,
+		Header: response.Header,
+		Body:   readBody(response.Body),
+	}
+	r.Append(NewEntry(started, elapsed, httpRequest, httpResponse))
+}
+
+// NewRecorder creates HAR recorder
+func NewRecorder(filename, creator, version string) *Recorder {
+	return &Recorder{filename: filename, document: New(creator, version)}
+}
+
+func requestURL(request *http.Request) string {
+	if request.URL.IsAbs() || request.Host == "" {
+		return request.URL.String()
+	}
+	URL := *request.URL
+	URL.Scheme, URL.Host = "http", request.Host
+	if request.TLS != nil {
+		URL.Scheme = "https"
+	}
+	return URL.String()
+}
+
+func readBody(body io.ReadCloser) string {
+	if body == nil {
+		return ""
+	}
+	data, _ := io.ReadAll(body)
+	return string(data)
+}
@@ -10,6 +10,7 @@ type AbstractNode struct {
 	Post        Variables `description:"post execution state update instruction" yaml:",omitempty"`
 	When        string    `description:"run criteria" yaml:",omitempty"`
 	SleepTimeMs int       `yaml:",omitempty"`
+	TimeoutMs   int       `description:"node execution timeout, exceeded timeout cancels node context and fails the node" yaml:",omitempty"`
 	Logging     *bool     `description:"optional flag to disable logging, enabled by default" yaml:",omitempty"`
 	whenEval    eval.Compute
 }
@@ -10,6 +10,7 @@ type Action struct {
 	*Repeater       `yaml:",inline"`
 	Async           bool   `description:"flag to run action async" yaml:",omitempty"`
 	Skip            string `description:"criteria to skip current TagID"  yaml:",omitempty"`
+	Retry           *Retry `description:"action retry policy with exponential backoff" yaml:",omitempty"`
 	skipEvan        eval.Compute
 }
 
@@ -41,6 +42,11 @@ func (a *Action) Init() error {
 		a.ServiceRequest = a.ServiceRequest.Init()
 	}
 	a.Repeater = a.Repeater.Init()
+	if a.Retry != nil {
+		if err := a.Retry.Init(); err != nil {
+			return err
+		}
+	}
 	if err := a.Validate(); err != nil {
 		return err
 	}
@@ -65,6 +71,11 @@ func (a *Action) Clone() *Action {
 	serviceRequest := *a.ServiceRequest
 	metaTag := *a.MetaTag
 	repeater := *a.Repeater
+	var retry *Retry
+	if a.Retry != nil {
+		policy := *a.Retry
+		retry = &policy
+	}
 	return &Action{
 		AbstractNode:   &abstract,
 		ServiceRequest: &serviceRequest,
@@ -72,6 +83,7 @@ func (a *Action) Clone() *Action {
 		Repeater:       &repeater,
 		Async:          a.Async,
 		Skip:           a.Skip,
+		Retry:          retry,
 	}
 }
 
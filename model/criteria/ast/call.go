@@ -0,0 +1,18 @@
+package ast
+
+import "strings"
+
+// Call represents UDF call, i.e. $Len($items)
+type Call struct {
+	Name string
+	Args []Node
+	Raw  string
+}
+
+func (c *Call) Stringify() string {
+	var args = make([]string, 0, len(c.Args))
+	for _, arg := range c.Args {
+		args = append(args, arg.Stringify())
+	}
+	return "$" + c.Name + "(" + strings.Join(args, ", ") + ")"
+}
@@ -0,0 +1,16 @@
+package ast
+
+import "strings"
+
+// List represents set membership operand, i.e. (200, 201, 204)
+type List struct {
+	Items []Node
+}
+
+func (l *List) Stringify() string {
+	var items = make([]string, 0, len(l.Items))
+	for _, item := range l.Items {
+		items = append(items, item.Stringify())
+	}
+	return "(" + strings.Join(items, ", ") + ")"
+}
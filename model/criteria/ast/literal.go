@@ -13,6 +13,8 @@ func (b *Literal) Stringify() string {
 			if b.Quote != "" {
 				return b.Quote + b.Value + b.Quote
 			}
+		case "regexp":
+			return b.Value + b.Quote
 	}
 	return b.Value
 }
\ No newline at end of file
@@ -0,0 +1,11 @@
+package ast
+
+// Range represents inclusive range operand, i.e. 10 and 500
+type Range struct {
+	From Node
+	To   Node
+}
+
+func (r *Range) Stringify() string {
+	return r.From.Stringify() + " and " + r.To.Stringify()
+}
@@ -0,0 +1,109 @@
+package compiler
+
+import (
+	"fmt"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// arithmetic returns arithmetic operator evaluator, operands are coerced to int when possible, float64 otherwise,
+// + concatenates non numeric operands
+func (b *binary) arithmetic(op string) func(state data.Map) (interface{}, bool, error) {
+	return func(state data.Map) (interface{}, bool, error) {
+		x, hasX, err := b.xValue(state)
+		if err != nil {
+			return nil, false, err
+		}
+		y, hasY, err := b.yValue(state)
+		if err != nil {
+			return nil, false, err
+		}
+		if !hasX || !hasY {
+			return nil, false, nil
+		}
+		xNum, xOk := asNumeric(x)
+		yNum, yOk := asNumeric(y)
+		if !xOk || !yOk {
+			if op == "+" {
+				return toolbox.AsString(x) + toolbox.AsString(y), true, nil
+			}
+			return nil, false, fmt.Errorf("unable to evaluate %v %v %v: expected numeric operands, but had: %T, %T", x, op, y, x, y)
+		}
+		xInt, isXInt := xNum.(int)
+		yInt, isYInt := yNum.(int)
+		if isXInt && isYInt {
+			switch op {
+			case "+":
+				return xInt + yInt, true, nil
+			case "-":
+				return xInt - yInt, true, nil
+			case "*":
+				return xInt * yInt, true, nil
+			case "/":
+				if yInt == 0 {
+					return nil, false, fmt.Errorf("unable to evaluate %v / %v: division by zero", xInt, yInt)
+				}
+				if xInt%yInt == 0 {
+					return xInt / yInt, true, nil
+				}
+			case "%":
+				if yInt == 0 {
+					return nil, false, fmt.Errorf("unable to evaluate %v %% %v: division by zero", xInt, yInt)
+				}
+				return xInt % yInt, true, nil
+			}
+		}
+		xFloat := toolbox.AsFloat(xNum)
+		yFloat := toolbox.AsFloat(yNum)
+		switch op {
+		case "+":
+			return xFloat + yFloat, true, nil
+		case "-":
+			return xFloat - yFloat, true, nil
+		case "*":
+			return xFloat * yFloat, true, nil
+		case "/":
+			if yFloat == 0 {
+				return nil, false, fmt.Errorf("unable to evaluate %v / %v: division by zero", xFloat, yFloat)
+			}
+			return xFloat / yFloat, true, nil
+		case "%":
+			if yFloat == 0 {
+				return nil, false, fmt.Errorf("unable to evaluate %v %% %v: division by zero", xFloat, yFloat)
+			}
+			return math.Mod(xFloat, yFloat), true, nil
+		}
+		return nil, false, fmt.Errorf("unsupported operator: %v", op)
+	}
+}
+
+// asNumeric returns value as int or float64
+func asNumeric(value interface{}) (interface{}, bool) {
+	switch actual := value.(type) {
+	case int:
+		return actual, true
+	case float64:
+		return actual, true
+	case bool, nil:
+		return nil, false
+	case string:
+		text := strings.TrimSpace(actual)
+		if intValue, err := strconv.Atoi(text); err == nil {
+			return intValue, true
+		}
+		if floatValue, err := strconv.ParseFloat(text, 64); err == nil {
+			return floatValue, true
+		}
+		return nil, false
+	}
+	if toolbox.IsInt(value) {
+		return toolbox.AsInt(value), true
+	}
+	if toolbox.IsFloat(value) {
+		return toolbox.AsFloat(value), true
+	}
+	return nil, false
+}
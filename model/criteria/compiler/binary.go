@@ -9,13 +9,15 @@ import (
 )
 
 type binary struct {
-	x, y *operand
-	trim bool
+	x, y     *operand
+	trim     bool
+	patterns *patterns
 }
 
 func NewBinary(op string, operands ...*Operand) New {
 	switch op {
-	case "!", "=", "==", ":/", ":!/", ":~/", ":", ":!", "<>", "!=", "<", ">", "<=", ">=", "contains", "contains!", "&&", "||":
+	case "!", "=", "==", ":/", ":!/", ":~/", ":!~/", ":", ":!", "<>", "!=", "<", ">", "<=", ">=", "contains", "contains!", "&&", "||",
+		"+", "-", "*", "/", "%", "in", "between":
 	default:
 		return func() (eval.Compute, error) {
 			return nil, fmt.Errorf("unsupported operator: %v", op)
@@ -23,9 +25,17 @@ func NewBinary(op string, operands ...*Operand) New {
 	}
 
 	return func() (eval.Compute, error) {
+		x, err := operands[0].operand()
+		if err != nil {
+			return nil, err
+		}
+		y, err := operands[1].operand()
+		if err != nil {
+			return nil, err
+		}
 		expr := &binary{
-			x:    operands[0].operand(),
-			y:    operands[1].operand(),
+			x:    x,
+			y:    y,
 			trim: strings.Contains(op, ":/"),
 		}
 		switch op {
@@ -45,6 +55,20 @@ func NewBinary(op string, operands ...*Operand) New {
 			return expr.contains, nil
 		case "contains!", ":!/":
 			return expr.notContains, nil
+		case ":~/", ":!~/":
+			if err := expr.compilePattern(); err != nil {
+				return nil, err
+			}
+			if op == ":!~/" {
+				return expr.notMatches, nil
+			}
+			return expr.matches, nil
+		case "+", "-", "*", "/", "%":
+			return expr.arithmetic(op), nil
+		case "in":
+			return expr.in, nil
+		case "between":
+			return expr.between, nil
 		case "&&":
 			return expr.and, nil
 		case "||":
@@ -72,17 +96,7 @@ func (b *binary) equal(state data.Map) (interface{}, bool, error) {
 	if errY != nil {
 		return nil, false, errY
 	}
-	switch x.(type) {
-	case string:
-		y = toolbox.AsString(y)
-	case int:
-		y = toolbox.AsInt(y)
-	case float64:
-		y = toolbox.AsFloat(y)
-	case bool:
-		y = toolbox.AsBoolean(y)
-
-	}
+	y = coerce(x, y)
 
 	if !hasX && !hasY {
 		return false, false, nil
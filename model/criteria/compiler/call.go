@@ -0,0 +1,77 @@
+package compiler
+
+import (
+	"fmt"
+	"github.com/viant/endly/model/criteria/ast"
+	"github.com/viant/endly/model/criteria/eval"
+	"github.com/viant/toolbox/data"
+)
+
+// NewCall creates UDF call evaluator, arguments are evaluated before the call; when no UDF is registered under the call name,
+// the call is expanded as state expression.
+func NewCall(call *ast.Call) (New, error) {
+	var args = make([]*Operand, 0, len(call.Args))
+	for _, arg := range call.Args {
+		operand, err := NewOperand(arg)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, operand)
+	}
+	expression := &Operand{selector: &ast.Selector{X: call.Raw}}
+	return func() (eval.Compute, error) {
+		fallback, err := expression.operand()
+		if err != nil {
+			return nil, err
+		}
+		var operands = make([]*operand, 0, len(args))
+		for _, arg := range args {
+			op, err := arg.operand()
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, op)
+		}
+		return func(state data.Map) (interface{}, bool, error) {
+			udf := lookupUDF(state, call.Name)
+			if udf == nil {
+				return fallback.Value(state)
+			}
+			var values = make([]interface{}, 0, len(operands))
+			for _, op := range operands {
+				value, has, err := op.Value(state)
+				if err != nil || !has {
+					return nil, false, err
+				}
+				values = append(values, value)
+			}
+			var argument interface{}
+			switch len(values) {
+			case 0:
+			case 1:
+				argument = values[0]
+			default:
+				argument = values
+			}
+			result, err := udf(argument, state)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to call $%v: %w", call.Name, err)
+			}
+			return result, true, nil
+		}, nil
+	}, nil
+}
+
+func lookupUDF(state data.Map, name string) func(interface{}, data.Map) (interface{}, error) {
+	udfs := state.GetMap(data.UDFKey)
+	if len(udfs) == 0 {
+		return nil
+	}
+	switch actual := udfs[name].(type) {
+	case func(interface{}, data.Map) (interface{}, error):
+		return actual
+	case data.Udf:
+		return actual
+	}
+	return nil
+}
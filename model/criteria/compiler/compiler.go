@@ -40,6 +40,12 @@ func compile(node ast.Node) (New, error) {
 		return compile(actual.X)
 	case *ast.Group:
 		return compile(actual.X)
+	case *ast.Call:
+		return NewCall(actual)
+	case *ast.List:
+		return NewList(actual)
+	case *ast.Range:
+		return NewRange(actual)
 	default:
 		if node == nil {
 			return nil, nil
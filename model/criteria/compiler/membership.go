@@ -0,0 +1,177 @@
+package compiler
+
+import (
+	"fmt"
+	"github.com/viant/endly/model/criteria/ast"
+	"github.com/viant/endly/model/criteria/eval"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"reflect"
+)
+
+// NewList creates set evaluator returning defined items values
+func NewList(list *ast.List) (New, error) {
+	items, err := newOperands(list.Items...)
+	if err != nil {
+		return nil, err
+	}
+	return func() (eval.Compute, error) {
+		operands, err := asOperands(items)
+		if err != nil {
+			return nil, err
+		}
+		return func(state data.Map) (interface{}, bool, error) {
+			var result = make([]interface{}, 0, len(operands))
+			for _, op := range operands {
+				value, has, err := op.Value(state)
+				if err != nil {
+					return nil, false, err
+				}
+				if has {
+					result = append(result, value)
+				}
+			}
+			return result, true, nil
+		}, nil
+	}, nil
+}
+
+// NewRange creates range evaluator returning lower and upper bound values
+func NewRange(aRange *ast.Range) (New, error) {
+	bounds, err := newOperands(aRange.From, aRange.To)
+	if err != nil {
+		return nil, err
+	}
+	return func() (eval.Compute, error) {
+		operands, err := asOperands(bounds)
+		if err != nil {
+			return nil, err
+		}
+		return func(state data.Map) (interface{}, bool, error) {
+			from, hasFrom, err := operands[0].Value(state)
+			if err != nil {
+				return nil, false, err
+			}
+			to, hasTo, err := operands[1].Value(state)
+			if err != nil {
+				return nil, false, err
+			}
+			return []interface{}{from, to}, hasFrom && hasTo, nil
+		}, nil
+	}, nil
+}
+
+func newOperands(nodes ...ast.Node) ([]*Operand, error) {
+	var result = make([]*Operand, 0, len(nodes))
+	for _, node := range nodes {
+		operand, err := NewOperand(node)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, operand)
+	}
+	return result, nil
+}
+
+func asOperands(operands []*Operand) ([]*operand, error) {
+	var result = make([]*operand, 0, len(operands))
+	for _, item := range operands {
+		op, err := item.operand()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, op)
+	}
+	return result, nil
+}
+
+func (b *binary) in(state data.Map) (interface{}, bool, error) {
+	x, hasX, errX := b.xValue(state)
+	if errX != nil {
+		return false, false, errX
+	}
+	y, hasY, errY := b.yValue(state)
+	if errY != nil {
+		return false, false, errY
+	}
+	if !hasX && !hasY {
+		return false, false, nil
+	}
+	if !hasX || !hasY {
+		return false, true, nil
+	}
+	switch {
+	case toolbox.IsSlice(y):
+		for _, item := range toolbox.AsSlice(y) {
+			if isEqual(x, item) {
+				return true, true, nil
+			}
+		}
+		return false, true, nil
+	case toolbox.IsMap(y):
+		for key := range toolbox.AsMap(y) {
+			if isEqual(x, key) {
+				return true, true, nil
+			}
+		}
+		return false, true, nil
+	}
+	return isEqual(x, y), true, nil
+}
+
+func (b *binary) between(state data.Map) (interface{}, bool, error) {
+	x, hasX, errX := b.xValue(state)
+	if errX != nil {
+		return false, false, errX
+	}
+	y, hasY, errY := b.yValue(state)
+	if errY != nil {
+		return false, false, errY
+	}
+	if !hasX && !hasY {
+		return false, false, nil
+	}
+	if !hasX || !hasY {
+		return false, true, nil
+	}
+	bounds, ok := y.([]interface{})
+	if !ok || len(bounds) != 2 {
+		return false, false, fmt.Errorf("expected between lower and upper bound, but had: %v", y)
+	}
+	value, ok := asNumeric(x)
+	if !ok {
+		return false, false, fmt.Errorf("expected numeric between operand, but had: %v(%T)", x, x)
+	}
+	from, fromOk := asNumeric(bounds[0])
+	to, toOk := asNumeric(bounds[1])
+	if !fromOk || !toOk {
+		return false, false, fmt.Errorf("expected numeric between bounds, but had: %v and %v", bounds[0], bounds[1])
+	}
+	number := toolbox.AsFloat(value)
+	return number >= toolbox.AsFloat(from) && number <= toolbox.AsFloat(to), true, nil
+}
+
+// isEqual compares y coerced to x type, numeric values are compared as float64
+func isEqual(x, y interface{}) bool {
+	if xNum, ok := asNumeric(x); ok {
+		if yNum, ok := asNumeric(y); ok {
+			return toolbox.AsFloat(xNum) == toolbox.AsFloat(yNum)
+		}
+	}
+	return reflect.DeepEqual(x, coerce(x, y))
+}
+
+// coerce converts y to x type
+func coerce(x, y interface{}) interface{} {
+	switch x.(type) {
+	case string:
+		return toolbox.AsString(y)
+	case int:
+		return toolbox.AsInt(y)
+	case float64:
+		return toolbox.AsFloat(y)
+	case bool:
+		return toolbox.AsBoolean(y)
+	}
+	return y
+}
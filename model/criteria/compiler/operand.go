@@ -15,17 +15,20 @@ type Operand struct {
 	compute  New
 }
 
-func (o *Operand) operand() *operand {
+func (o *Operand) operand() (*operand, error) {
 	var compute eval.Compute
 	if o.compute != nil {
-		compute, _ = o.compute()
+		var err error
+		if compute, err = o.compute(); err != nil {
+			return nil, err
+		}
 	}
 	return &operand{
 		nil:      o.nil,
 		literal:  o.literal,
 		selector: o.selector,
 		compute:  compute,
-	}
+	}, nil
 }
 
 type operand struct {
@@ -0,0 +1,113 @@
+package compiler
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+const (
+	//MatchKey represents state key with the last regular expression match groups, i.e. $match[1]
+	MatchKey = "match"
+	//MatchGroupKey represents state key with the last regular expression named match groups, i.e. $matchGroup.version
+	MatchGroupKey = "matchGroup"
+	//CapturesKey represents evaluation state key holding *Captures, match groups are recorded only if caller supplied it
+	CapturesKey = "_criteriaCaptures"
+)
+
+// Captures represents regular expression match groups of a single evaluation
+type Captures struct {
+	Match  []interface{}
+	Groups data.Map
+}
+
+// Publish exposes match groups to the state as $match and $matchGroup
+func (c *Captures) Publish(state data.Map) {
+	state.Put(MatchKey, c.Match)
+	state.Put(MatchGroupKey, c.Groups)
+}
+
+func (c *Captures) record(pattern *regexp.Regexp, groups []string) {
+	c.Match = make([]interface{}, len(groups))
+	c.Groups = data.NewMap()
+	for i, group := range groups {
+		c.Match[i] = group
+		if name := pattern.SubexpNames()[i]; name != "" {
+			c.Groups.Put(name, group)
+		}
+	}
+}
+
+// patterns represents compiled regular expression cache
+type patterns struct {
+	mux      sync.RWMutex
+	compiled map[string]*regexp.Regexp
+}
+
+func (p *patterns) get(expr string) (*regexp.Regexp, error) {
+	p.mux.RLock()
+	result, ok := p.compiled[expr]
+	p.mux.RUnlock()
+	if ok {
+		return result, nil
+	}
+	result, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regexp: /%v/, %w", expr, err)
+	}
+	p.mux.Lock()
+	p.compiled[expr] = result
+	p.mux.Unlock()
+	return result, nil
+}
+
+func newPatterns() *patterns {
+	return &patterns{compiled: make(map[string]*regexp.Regexp)}
+}
+
+// compilePattern compiles literal pattern upfront to report invalid expression at compile time
+func (b *binary) compilePattern() error {
+	b.patterns = newPatterns()
+	if b.y.literal == nil {
+		return nil
+	}
+	_, err := b.patterns.get(b.y.literal.Value)
+	return err
+}
+
+func (b *binary) matches(state data.Map) (interface{}, bool, error) {
+	x, hasX, errX := b.xValue(state)
+	if errX != nil {
+		return false, false, errX
+	}
+	y, hasY, errY := b.yValue(state)
+	if errY != nil {
+		return false, false, errY
+	}
+	if !hasX || !hasY {
+		return false, hasX || hasY, nil
+	}
+	pattern, err := b.patterns.get(toolbox.AsString(y))
+	if err != nil {
+		return false, false, err
+	}
+	groups := pattern.FindStringSubmatch(toolbox.AsString(x))
+	if groups == nil {
+		return false, true, nil
+	}
+	if captures, ok := state[CapturesKey].(*Captures); ok {
+		captures.record(pattern, groups)
+	}
+	return true, true, nil
+}
+
+func (b *binary) notMatches(state data.Map) (interface{}, bool, error) {
+	ret, has, err := b.matches(state)
+	if err != nil || !has {
+		return false, has, err
+	}
+	return !toolbox.AsBoolean(ret), true, nil
+}
@@ -12,7 +12,10 @@ func NewUnary(op string, operand *Operand) New {
 	switch op {
 	case "!", "not":
 		return func() (eval.Compute, error) {
-			op := operand.operand()
+			op, err := operand.operand()
+			if err != nil {
+				return nil, err
+			}
 			return func(state data.Map) (interface{}, bool, error) {
 				value, ok, err := op.Value(state)
 				if err != nil {
@@ -24,7 +27,10 @@ func NewUnary(op string, operand *Operand) New {
 
 	case "":
 		return func() (eval.Compute, error) {
-			op := operand.operand()
+			op, err := operand.operand()
+			if err != nil {
+				return nil, err
+			}
 			return func(state data.Map) (interface{}, bool, error) {
 				value, ok, err := op.Value(state)
 				if err != nil {
@@ -52,7 +58,10 @@ func NewUnary(op string, operand *Operand) New {
 		}
 	case "defined":
 		return func() (eval.Compute, error) {
-			op := operand.operand()
+			op, err := operand.operand()
+			if err != nil {
+				return nil, err
+			}
 			return func(state data.Map) (interface{}, bool, error) {
 				_, ok, err := op.Value(state)
 				if err != nil {
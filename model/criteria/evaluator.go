@@ -7,6 +7,7 @@ import (
 	"github.com/viant/endly/model/criteria/compiler"
 	"github.com/viant/endly/model/criteria/eval"
 	"github.com/viant/toolbox/data"
+	"strings"
 )
 
 // EvalEvent represents criteria event
@@ -34,6 +35,7 @@ func NewEvalEvent(criteriaType string, defaultValue, evaluation bool, criteria s
 	return result
 }
 
+// Evaluate evaluates criteria expression with supplied state, compiled expression is cached in compute
 func Evaluate(context *endly.Context, state data.Map, expression string, compute *eval.Compute, eventType string, defaultValue bool) (bool, error) {
 	if expression == "" {
 		return defaultValue, nil
@@ -51,6 +53,7 @@ func Evaluate(context *endly.Context, state data.Map, expression string, compute
 		if err != nil {
 			return defaultValue, err
 		}
+		*compute = evaluator
 	}
 	result, has, err := evaluator(state)
 	ret, ok := result.(bool)
@@ -69,6 +72,29 @@ func Evaluate(context *endly.Context, state data.Map, expression string, compute
 	return ret, nil
 }
 
+// EvaluateWithCaptures evaluates criteria expression, returns regular expression match groups of this evaluation or nil,
+// supplied state is not modified, caller decides whether to publish captures
+func EvaluateWithCaptures(context *endly.Context, state data.Map, expression string, compute *eval.Compute, eventType string, defaultValue bool) (bool, *compiler.Captures, error) {
+	if expression == "" {
+		return defaultValue, nil, nil
+	}
+	if !strings.Contains(expression, ":~/") && !strings.Contains(expression, ":!~/") { //only regexp operators capture match groups
+		result, err := Evaluate(context, state, expression, compute, eventType, defaultValue)
+		return result, nil, err
+	}
+	var captures = &compiler.Captures{}
+	var evalState = make(data.Map, len(state)+1)
+	for key, value := range state {
+		evalState[key] = value
+	}
+	evalState[compiler.CapturesKey] = captures
+	result, err := Evaluate(context, evalState, expression, compute, eventType, defaultValue)
+	if captures.Match == nil {
+		captures = nil
+	}
+	return result, captures, err
+}
+
 // Assert validates expected against actual
 func Assert(context *endly.Context, root string, expected, actual interface{}) (*assertly.Validation, error) {
 	ctx := assertly.NewDefaultContext()
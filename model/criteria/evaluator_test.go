@@ -223,6 +223,129 @@ func Test_EvaluateCriteria(t *testing.T) {
 				},
 			},
 		},
+		{
+			Description: "regexp match",
+			Expression:  `$stdout:~/version (\d+)\.\d+/`,
+			Expected:    true,
+			State: map[string]interface{}{
+				"stdout": "go version 1.21 linux",
+			},
+		},
+		{
+			Description: "regexp no match",
+			Expression:  `$stdout:~/^version/`,
+			Expected:    false,
+			State: map[string]interface{}{
+				"stdout": "go version 1.21 linux",
+			},
+		},
+		{
+			Description: "negated regexp match",
+			Expression:  `$stdout:!~/error|fatal/`,
+			Expected:    true,
+			State: map[string]interface{}{
+				"stdout": "all good",
+			},
+		},
+		{
+			Description: "regexp with logical operator",
+			Expression:  `$status:~/^(200|201)$/ && $ready`,
+			Expected:    true,
+			State: map[string]interface{}{
+				"status": 201,
+				"ready":  true,
+			},
+		},
+		{
+			Description: "arithmetic with udf call",
+			Expression:  "$Len($items) >= $expected + 1",
+			Expected:    true,
+			State: map[string]interface{}{
+				"items":    []interface{}{1, 2, 3},
+				"expected": 2,
+			},
+		},
+		{
+			Description: "arithmetic precedence",
+			Expression:  "$a + $b * 2 == 7 && $a * $b - 1 == 5",
+			Expected:    true,
+			State: map[string]interface{}{
+				"a": 3,
+				"b": "2",
+			},
+		},
+		{
+			Description: "arithmetic division and modulo",
+			Expression:  "$total / 4 == 2.5 && $total % 3 == 1",
+			Expected:    true,
+			State: map[string]interface{}{
+				"total": 10,
+			},
+		},
+		{
+			Description: "arithmetic non numeric operand",
+			Expression:  "$name * 2 > 1",
+			HasError:    true,
+			State: map[string]interface{}{
+				"name": "abc",
+			},
+		},
+		{
+			Description: "set membership",
+			Expression:  "$status in (200, 201, 204)",
+			Expected:    true,
+			State: map[string]interface{}{
+				"status": 201,
+			},
+		},
+		{
+			Description: "set membership - no match",
+			Expression:  "$status in (200, 201, 204) && $ready",
+			Expected:    false,
+			State: map[string]interface{}{
+				"status": "404",
+				"ready":  true,
+			},
+		},
+		{
+			Description: "set membership with selector",
+			Expression:  "$env in $envs",
+			Expected:    true,
+			State: map[string]interface{}{
+				"env":  "stage",
+				"envs": []interface{}{"dev", "stage"},
+			},
+		},
+		{
+			Description: "range check",
+			Expression:  "$latencyMs between 10 and 500",
+			Expected:    true,
+			State: map[string]interface{}{
+				"latencyMs": 10.5,
+			},
+		},
+		{
+			Description: "range check - out of range",
+			Expression:  "$latencyMs between 10 and $max || $retry",
+			Expected:    false,
+			State: map[string]interface{}{
+				"latencyMs": 501,
+				"max":       500,
+			},
+		},
+		{
+			Description: "missing between upper bound",
+			Expression:  "$latencyMs between 10",
+			HasError:    true,
+		},
+		{
+			Description: "invalid regexp",
+			Expression:  `$stdout:~/(abc/`,
+			HasError:    true,
+			State: map[string]interface{}{
+				"stdout": "abc",
+			},
+		},
 	}
 
 	for i, useCase := range useCases {
@@ -243,3 +366,46 @@ func Test_EvaluateCriteria(t *testing.T) {
 	}
 
 }
+
+func Test_EvaluateCriteria_MatchGroups(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	state := context.State()
+	state.Put("stdout", "endly version 0.81.2")
+	var compute eval.Compute
+	matched, captures, err := EvaluateWithCaptures(context, state, `$stdout:~/version (?P<major>\d+)\.(\d+)/`, &compute, "test", false)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, matched)
+	assert.NotNil(t, compute)
+	assert.False(t, state.Has("match"), "evaluation should not modify supplied state")
+	if !assert.NotNil(t, captures) {
+		return
+	}
+	captures.Publish(state)
+	assert.EqualValues(t, "81", state.ExpandAsText("${match[2]}"))
+	matchGroup := state.GetMap("matchGroup")
+	assert.EqualValues(t, "0", matchGroup.GetString("major"))
+
+	matched, err = Evaluate(context, state, `$stdout:~/version (?P<major>\d+)\.(\d+)/`, &compute, "test", false)
+	assert.Nil(t, err)
+	assert.True(t, matched, "cached evaluator should match without captures")
+
+	state.Put("stdout", "no version")
+	matched, captures, err = EvaluateWithCaptures(context, state, `$stdout:~/version (?P<major>\d+)\.(\d+)/`, &compute, "test", false)
+	assert.Nil(t, err)
+	assert.False(t, matched)
+	assert.Nil(t, captures)
+
+	matched, captures, err = EvaluateWithCaptures(context, state, "", &compute, "test", true)
+	assert.Nil(t, err)
+	assert.True(t, matched, "empty expression should return default")
+	assert.Nil(t, captures)
+
+	var plainCompute eval.Compute
+	matched, captures, err = EvaluateWithCaptures(context, state, `$stdout = 'no version'`, &plainCompute, "test", false)
+	assert.Nil(t, err)
+	assert.True(t, matched)
+	assert.Nil(t, captures, "expression without regexp operator should not capture")
+}
@@ -0,0 +1,40 @@
+package matcher
+
+import (
+	"github.com/viant/parsly"
+	"github.com/viant/parsly/matcher"
+)
+
+// Arithmetic represents arithmetic operator matcher, '/' and '-' are also used in paths and selectors,
+// thus they are only matched when surrounded by whitespace or between digits
+type Arithmetic struct{}
+
+// Match matches arithmetic operator
+func (a *Arithmetic) Match(cursor *parsly.Cursor) (matched int) {
+	input := cursor.Input
+	pos := cursor.Pos
+	if pos >= len(input) {
+		return 0
+	}
+	switch input[pos] {
+	case '+', '*', '%':
+		return 1
+	case '/', '-':
+		if pos == 0 || pos+1 >= len(input) {
+			return 0
+		}
+		before, after := input[pos-1], input[pos+1]
+		if matcher.IsWhiteSpace(before) && matcher.IsWhiteSpace(after) {
+			return 1
+		}
+		if matcher.IsDigit(before) && matcher.IsDigit(after) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// NewArithmetic creates an arithmetic operator matcher
+func NewArithmetic() *Arithmetic {
+	return &Arithmetic{}
+}
@@ -0,0 +1,35 @@
+package matcher
+
+import (
+	"github.com/viant/parsly"
+	"github.com/viant/parsly/matcher"
+)
+
+// Keyword represents case insensitive word matcher, a word has to be followed by whitespace, parentheses or end of input
+type Keyword struct {
+	values [][]byte
+}
+
+// Match matches any of keywords
+func (k *Keyword) Match(cursor *parsly.Cursor) (matched int) {
+	input := cursor.Input
+	for _, value := range k.values {
+		end := cursor.Pos + len(value)
+		if end > len(input) || !matcher.MatchFold(value, input, 0, cursor.Pos) {
+			continue
+		}
+		if end == len(input) || matcher.IsWhiteSpace(input[end]) || input[end] == '(' {
+			return len(value)
+		}
+	}
+	return 0
+}
+
+// NewKeyword creates a keyword matcher
+func NewKeyword(values ...string) *Keyword {
+	var result = &Keyword{}
+	for _, value := range values {
+		result.values = append(result.values, []byte(value))
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package matcher
+
+import (
+	"github.com/viant/parsly"
+)
+
+// Pattern represents regular expression body matcher, it matches up to and including unescaped terminator
+type Pattern struct {
+	terminator byte
+	escape     byte
+}
+
+// Match matches regular expression body
+func (p *Pattern) Match(cursor *parsly.Cursor) (matched int) {
+	input := cursor.Input
+	for i := cursor.Pos; i < len(input); i++ {
+		switch input[i] {
+		case p.escape:
+			i++
+		case p.terminator:
+			return i - cursor.Pos + 1
+		}
+	}
+	return 0
+}
+
+// NewPattern creates a regular expression body matcher
+func NewPattern(terminator, escape byte) *Pattern {
+	return &Pattern{terminator: terminator, escape: escape}
+}
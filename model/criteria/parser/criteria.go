@@ -3,9 +3,13 @@ package parser
 import (
 	"github.com/viant/endly/model/criteria/ast"
 	"github.com/viant/parsly"
+	"github.com/viant/parsly/matcher"
 	"strings"
 )
 
+// patternTerminator represents regular expression operator suffix, i.e. :~/ or :!~/
+const patternTerminator = "~/"
+
 // ParseCriteria parses qualify expr
 func parseCriteria(cursor *parsly.Cursor, qualify *ast.Qualify) error {
 	binary := &ast.Binary{}
@@ -37,7 +41,9 @@ func parseQualify(cursor *parsly.Cursor, binary *ast.Binary, withDeclare bool, t
 
 	if binary.X == nil {
 		var tokens []*parsly.Token
-		if terminator != "" {
+		if terminator == patternTerminator {
+			tokens = []*parsly.Token{patternMatcher}
+		} else if terminator != "" {
 			if withDeclare {
 				tokens = []*parsly.Token{terminatorMatcherInc}
 			} else {
@@ -48,9 +54,15 @@ func parseQualify(cursor *parsly.Cursor, binary *ast.Binary, withDeclare bool, t
 		if err != nil || binary == nil {
 			return err
 		}
+		if terminator != "" { //terminator applies only to the operator operand
+			if !withDeclare && terminator == "/" && cursor.Pos < cursor.InputSize && cursor.Input[cursor.Pos] == '/' {
+				cursor.Pos++
+			}
+			terminator = ""
+		}
 	}
 	if binary.Op == "" {
-		match := cursor.MatchAfterOptional(whitespaceMatcher, parenthesesMatcher, binaryOperatorMatcher, unaryOperatorMatcher, logicalOperatorMatcher)
+		match := cursor.MatchAfterOptional(whitespaceMatcher, parenthesesMatcher, binaryOperatorMatcher, unaryOperatorMatcher, logicalOperatorMatcher, arithmeticOperatorMatcher, keywordOperatorMatcher)
 		op := match.Text(cursor)
 		switch match.Code {
 		case unaryOperator:
@@ -59,16 +71,32 @@ func parseQualify(cursor *parsly.Cursor, binary *ast.Binary, withDeclare bool, t
 			binary.Op = op
 		case binaryOperator:
 			binary.Op = op
+		case arithmeticOperator:
+			binary.Op = op
+		case keywordOperator:
+			binary.Op = strings.ToLower(op)
 		default:
 			return nil
 		}
 	}
-	if strings.HasSuffix(binary.Op, "/") {
+	if strings.HasSuffix(binary.Op, patternTerminator) {
+		terminator = patternTerminator
+	} else if strings.HasSuffix(binary.Op, "/") && len(binary.Op) > 1 {
 		terminator = "/"
 	}
 
 	if binary.Y == nil {
 		yExpr := &ast.Binary{}
+		switch binary.Op {
+		case "in":
+			if yExpr.X, err = expectList(cursor); err != nil {
+				return err
+			}
+		case "between":
+			if yExpr.X, err = expectRange(cursor); err != nil {
+				return err
+			}
+		}
 		if err := parseQualify(cursor, yExpr, withDeclare, terminator); err != nil {
 			return err
 		}
@@ -83,22 +111,163 @@ func parseQualify(cursor *parsly.Cursor, binary *ast.Binary, withDeclare bool, t
 	return nil
 }
 
+// precedence returns binary operator precedence, the higher value binds tighter
+func precedence(op string) int {
+	switch op {
+	case "||":
+		return 1
+	case "&&":
+		return 2
+	case "+", "-":
+		return 4
+	case "*", "/", "%":
+		return 5
+	}
+	return 3
+}
+
+// normalizeBinary rebuilds right nested binary chain, so that operators are left associative and honour precedence
 func normalizeBinary(binary *ast.Binary) {
-	switch binary.Op {
-	case "&&", "||":
-	default:
-		switch yOp := binary.Y.(type) {
-		case *ast.Binary:
-			swap := &ast.Binary{X: binary.X, Op: binary.Op, Y: yOp.X}
-			binary.Op = yOp.Op
-			binary.X = swap
-			binary.Y = yOp.Y
-			if binExpr, ok := binary.Y.(*ast.Binary); ok {
-				normalizeBinary(binExpr)
+	y, ok := binary.Y.(*ast.Binary)
+	if !ok || precedence(binary.Op) < precedence(y.Op) {
+		return
+	}
+	binary.X = attachOperand(binary.X, binary.Op, y.X)
+	binary.Op = y.Op
+	binary.Y = y.Y
+}
+
+// attachOperand attaches left operand to the left most binary node with the same or lower precedence
+func attachOperand(x ast.Node, op string, node ast.Node) ast.Node {
+	if binary, ok := node.(*ast.Binary); ok && precedence(op) >= precedence(binary.Op) {
+		binary.X = attachOperand(x, op, binary.X)
+		return binary
+	}
+	return &ast.Binary{X: x, Op: op, Y: node}
+}
+
+// expectList parses in operator set, i.e. (200, 201) or a selector
+func expectList(cursor *parsly.Cursor) (ast.Node, error) {
+	match := cursor.MatchAfterOptional(whitespaceMatcher, parenthesesMatcher, selectorMatcher)
+	switch match.Code {
+	case selectorCode:
+		return &ast.Selector{X: match.Text(cursor)}, nil
+	case parenthesesCode:
+		matched := match.Text(cursor)
+		items, err := parseArguments(matched[1 : len(matched)-1])
+		if err != nil {
+			return nil, shiftError(err, cursor, cursor.Pos-len(matched)+1)
+		}
+		return &ast.List{Items: items}, nil
+	}
+	return nil, newError(cursor, "expected (item, ...) or selector after in, but had %q", nearToken(cursor))
+}
+
+// expectRange parses between operator range, i.e. 10 and 500
+func expectRange(cursor *parsly.Cursor) (ast.Node, error) {
+	from, err := expectOperand(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, newError(cursor, "expected lower bound after between, but had %q", nearToken(cursor))
+	}
+	if match := cursor.MatchAfterOptional(whitespaceMatcher, andKeywordMatcher); match.Code != andKeyword {
+		return nil, newError(cursor, "expected and after between lower bound, but had %q", nearToken(cursor))
+	}
+	to, err := expectOperand(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		return nil, newError(cursor, "expected upper bound after and, but had %q", nearToken(cursor))
+	}
+	return &ast.Range{From: from, To: to}, nil
+}
+
+// parseArguments parses comma separated expressions
+func parseArguments(input string) ([]ast.Node, error) {
+	var result = make([]ast.Node, 0)
+	if strings.TrimSpace(input) == "" {
+		return result, nil
+	}
+	offset := 0
+	for _, arg := range splitArguments(input) {
+		qualify, err := ParseCriteria(arg)
+		if err != nil {
+			if parseErr, ok := err.(*Error); ok {
+				return nil, &Error{Input: input, Column: parseErr.Column + offset, Message: parseErr.Message}
 			}
+			return nil, err
+		}
+		if qualify.X == nil {
+			return nil, &Error{Input: input, Column: offset + 1, Message: "expected argument"}
+		}
+		result = append(result, unwrap(qualify.X))
+		offset += len(arg) + 1
+	}
+	return result, nil
+}
 
+// splitArguments splits input by top level commas
+func splitArguments(input string) []string {
+	var result []string
+	depth := 0
+	var quote byte
+	begin := 0
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if quote != 0 {
+			switch c {
+			case '\\':
+				i++
+			case quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, input[begin:i])
+				begin = i + 1
+			}
 		}
 	}
+	return append(result, input[begin:])
+}
+
+// unwrap returns operand of no operator unary node
+func unwrap(node ast.Node) ast.Node {
+	if unary, ok := node.(*ast.Unary); ok && unary.Op == "" {
+		return unary.X
+	}
+	return node
+}
+
+// asCall returns UDF call node for $name(args) selector or nil if selector is not a simple call
+func asCall(selector string) *ast.Call {
+	index := strings.Index(selector, "(")
+	if index < 2 || !strings.HasSuffix(selector, ")") {
+		return nil
+	}
+	name := selector[1:index]
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; !(matcher.IsLetter(c) || c == '_' || (i > 0 && c >= '0' && c <= '9')) {
+			return nil
+		}
+	}
+	args, err := parseArguments(selector[index+1 : len(selector)-1])
+	if err != nil {
+		return nil
+	}
+	return &ast.Call{Name: name, Args: args, Raw: selector}
 }
 
 var defaultsOperands = []*parsly.Token{
@@ -125,6 +294,9 @@ func expectOperand(cursor *parsly.Cursor, operands ...*parsly.Token) (ast.Node,
 	switch match.Code {
 	case terminatorCode:
 		return &ast.Literal{Value: match.Text(cursor), Type: "string"}, nil
+	case patternCode:
+		matched := match.Text(cursor)
+		return &ast.Literal{Value: matched[:len(matched)-1], Type: "regexp", Quote: "/"}, nil
 	case boolLiteral:
 		return &ast.Literal{Value: match.Text(cursor), Type: "bool"}, nil
 	case stringLiteral:
@@ -147,21 +319,32 @@ func expectOperand(cursor *parsly.Cursor, operands ...*parsly.Token) (ast.Node,
 		return unary, nil
 	case selectorCode:
 		matched := match.Text(cursor)
+		if call := asCall(matched); call != nil {
+			return call, nil
+		}
 		return &ast.Selector{X: matched}, nil
 	case parenthesesCode:
 		matched := match.Text(cursor)
 		block := matched[1 : len(matched)-1]
 		qualify, err := ParseCriteria(block)
 		if err != nil || qualify == nil {
-			return nil, err
+			return nil, shiftError(err, cursor, cursor.Pos-len(matched)+1)
 		}
 		return &ast.Group{X: qualify.X}, nil
 	case parsly.EOF:
 		return nil, nil
 	case parsly.Invalid:
-		return nil, cursor.NewError(operands...)
+		return nil, newError(cursor, "unexpected %q, expected: %v", nearToken(cursor), tokenNames(operands))
 	default:
 		cursor.Pos = pos
 		return nil, nil
 	}
 }
+
+func tokenNames(tokens []*parsly.Token) string {
+	var names = make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		names = append(names, token.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -73,6 +73,185 @@ func TestParseCriteria(t *testing.T) {
 				X: &ast.Unary{X: &ast.Selector{X: "$a"}, Op: "defined"},
 			},
 		},
+		{
+			name:  "regexp match",
+			input: `$stdout:~/version (\d+)\.\d+/`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X:  &ast.Selector{X: "$stdout"},
+					Op: ":~/",
+					Y:  &ast.Literal{Value: `version (\d+)\.\d+`, Type: "regexp", Quote: "/"},
+				},
+			},
+		},
+		{
+			name:  "negated regexp match with logical operator",
+			input: `$a:!~/^a\/b$/ && $b`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X: &ast.Binary{
+						X:  &ast.Selector{X: "$a"},
+						Op: ":!~/",
+						Y:  &ast.Literal{Value: `^a\/b$`, Type: "regexp", Quote: "/"},
+					},
+					Op: "&&",
+					Y:  &ast.Selector{X: "$b"},
+				},
+			},
+		},
+		{
+			name:  "arithmetic precedence",
+			input: `$len >= $expected + 1 * 2`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X:  &ast.Selector{X: "$len"},
+					Op: ">=",
+					Y: &ast.Binary{
+						X:  &ast.Selector{X: "$expected"},
+						Op: "+",
+						Y: &ast.Binary{
+							X:  &ast.Literal{Value: "1", Type: "numeric"},
+							Op: "*",
+							Y:  &ast.Literal{Value: "2", Type: "numeric"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "left associative arithmetic",
+			input: `$a - 1 - 2 > 0`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X: &ast.Binary{
+						X: &ast.Binary{
+							X:  &ast.Selector{X: "$a"},
+							Op: "-",
+							Y:  &ast.Literal{Value: "1", Type: "numeric"},
+						},
+						Op: "-",
+						Y:  &ast.Literal{Value: "2", Type: "numeric"},
+					},
+					Op: ">",
+					Y:  &ast.Literal{Value: "0", Type: "numeric"},
+				},
+			},
+		},
+		{
+			name:  "udf call",
+			input: `$Len($items) > 0`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X:  &ast.Call{Name: "Len", Args: []ast.Node{&ast.Selector{X: "$items"}}, Raw: "$Len($items)"},
+					Op: ">",
+					Y:  &ast.Literal{Value: "0", Type: "numeric"},
+				},
+			},
+		},
+		{
+			name:  "set membership",
+			input: `$status in (200, 'ok') && $b`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X: &ast.Binary{
+						X:  &ast.Selector{X: "$status"},
+						Op: "in",
+						Y: &ast.List{Items: []ast.Node{
+							&ast.Literal{Value: "200", Type: "numeric"},
+							&ast.Literal{Value: "ok", Type: "string", Quote: "'"},
+						}},
+					},
+					Op: "&&",
+					Y:  &ast.Selector{X: "$b"},
+				},
+			},
+		},
+		{
+			name:  "range check",
+			input: `$latencyMs between 10 and $max`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X:  &ast.Selector{X: "$latencyMs"},
+					Op: "between",
+					Y: &ast.Range{
+						From: &ast.Literal{Value: "10", Type: "numeric"},
+						To:   &ast.Selector{X: "$max"},
+					},
+				},
+			},
+		},
+		{
+			name:  "missing range and",
+			input: `$latencyMs between 10 or 20`,
+			err:   "at column 23",
+		},
+		{
+			name:  "invalid set item",
+			input: `$status in (200, 5 5)`,
+			err:   "at column 20",
+		},
+		{
+			name:  "unexpected trailing input",
+			input: `$a == 1 )`,
+			err:   "at column 9",
+		},
+		{
+			name:  "unparsed trailing input",
+			input: `$name = foo bar`,
+			err:   `unexpected "bar" at column 13`,
+		},
+		{
+			name:  "udf call with path argument",
+			input: `$HasResource(${path}/req/print.json)`,
+			expected: &ast.Qualify{
+				X: &ast.Unary{
+					X: &ast.Selector{X: "$HasResource(${path}/req/print.json)"},
+				},
+			},
+		},
+		{
+			name:  "selector with dash",
+			input: `$a.b-c`,
+			err:   `unexpected "-c" at column 5`,
+		},
+		{
+			name:  "spaced arithmetic",
+			input: `$a / $b - 1 > 0`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X: &ast.Binary{
+						X: &ast.Binary{
+							X:  &ast.Selector{X: "$a"},
+							Op: "/",
+							Y:  &ast.Selector{X: "$b"},
+						},
+						Op: "-",
+						Y:  &ast.Literal{Value: "1", Type: "numeric"},
+					},
+					Op: ">",
+					Y:  &ast.Literal{Value: "0", Type: "numeric"},
+				},
+			},
+		},
+		{
+			name:  "numeric arithmetic",
+			input: `10/2-1 > 0`,
+			expected: &ast.Qualify{
+				X: &ast.Binary{
+					X: &ast.Binary{
+						X: &ast.Binary{
+							X:  &ast.Literal{Value: "10", Type: "numeric"},
+							Op: "/",
+							Y:  &ast.Literal{Value: "2", Type: "numeric"},
+						},
+						Op: "-",
+						Y:  &ast.Literal{Value: "1", Type: "numeric"},
+					},
+					Op: ">",
+					Y:  &ast.Literal{Value: "0", Type: "numeric"},
+				},
+			},
+		},
 		// Add more test cases here for different expressions and expected outcomes
 	}
 
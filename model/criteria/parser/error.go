@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/parsly"
+	"github.com/viant/parsly/matcher"
+)
+
+// Error represents criteria parse error pointing at the offending column
+type Error struct {
+	Input   string
+	Column  int
+	Message string
+}
+
+// Error returns error message
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v at column %d: %v", e.Message, e.Column, e.Input)
+}
+
+func newError(cursor *parsly.Cursor, format string, args ...interface{}) error {
+	pos := cursor.Pos
+	for pos < len(cursor.Input) && matcher.IsWhiteSpace(cursor.Input[pos]) {
+		pos++
+	}
+	return &Error{Input: string(cursor.Input), Column: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// nearToken returns input fragment at the cursor position
+func nearToken(cursor *parsly.Cursor) string {
+	pos := cursor.Pos
+	for pos < len(cursor.Input) && matcher.IsWhiteSpace(cursor.Input[pos]) {
+		pos++
+	}
+	end := pos
+	for end < len(cursor.Input) && end-pos < 10 && !matcher.IsWhiteSpace(cursor.Input[end]) {
+		end++
+	}
+	return string(cursor.Input[pos:end])
+}
+
+// shiftError adjusts nested expression error to the enclosing input
+func shiftError(err error, cursor *parsly.Cursor, offset int) error {
+	if parseErr, ok := err.(*Error); ok {
+		return &Error{Input: string(cursor.Input), Column: parseErr.Column + offset, Message: parseErr.Message}
+	}
+	return err
+}
@@ -15,10 +15,11 @@ func ParseDeclaration(input string) (when string, thenExpr string, elseExpr stri
 func ParseCriteria(input string) (*ast.Qualify, error) {
 	cursor := parsly.NewCursor("", []byte(input), 0)
 	qualify := &ast.Qualify{}
-	err := parseCriteria(cursor, qualify)
-	if err != nil {
+	if err := parseCriteria(cursor, qualify); err != nil {
 		return nil, err
 	}
+	if cursor.Pos += whitespaceMatcher.Match(cursor); cursor.Pos < cursor.InputSize {
+		return nil, newError(cursor, "unexpected %q", nearToken(cursor))
+	}
 	return qualify, nil
 }
-
@@ -32,6 +32,11 @@ const (
 	postKey        = "post"
 	exitKey        = "exit"
 	tagKey         = "tag"
+	dependsOnKey   = "dependson"
+	parallelKey    = "parallel"
+	concurrencyKey = "concurrency"
+	retryKey       = "retry"
+	timeoutMsKey   = "timeoutms"
 	defaultPath    = "default"
 )
 
@@ -50,9 +55,11 @@ type Inlined struct {
 	Init       interface{}
 	Post       interface{}
 	Logging    *bool
+	TimeoutMs  int
 	Defaults   map[string]interface{}
 	Data       map[string]interface{}
 	Pipeline   []*MapEntry
+	Matrix     *Matrix //optional pipeline expansion over parameter combinations
 	State      data.Map
 	workflow   *Workflow //inline workflow from pipeline
 }
@@ -67,6 +74,14 @@ func (p Inlined) updatereservedAttributes(aMap map[string]interface{}) {
 			aMap[ExplicitActionAttributePrefix+key] = val
 		}
 	}
+	for key, val := range aMap {
+		lowerKey := strings.ToLower(key)
+		isRetryPolicy := lowerKey == retryKey && (toolbox.IsMap(val) || toolbox.IsSlice(val)) //retry policy block, not a request retry flag
+		if lowerKey == dependsOnKey || lowerKey == parallelKey || isRetryPolicy {
+			delete(aMap, key)
+			aMap[ExplicitActionAttributePrefix+lowerKey] = val
+		}
+	}
 	for _, key := range []string{tagKey} {
 		if val, ok := aMap[key]; ok {
 			if _, has := aMap[ExplicitRequestAttributePrefix+key]; has {
@@ -238,8 +253,9 @@ func (p *Inlined) AsWorkflow(name string, baseURL string) (*Workflow, error) {
 	}
 	var workflow = &Workflow{
 		AbstractNode: &AbstractNode{
-			Name:    name,
-			Logging: p.Logging,
+			Name:      name,
+			Logging:   p.Logging,
+			TimeoutMs: p.TimeoutMs,
 		},
 		TasksNode: &TasksNode{
 			Tasks: []*Task{},
@@ -261,7 +277,11 @@ func (p *Inlined) AsWorkflow(name string, baseURL string) (*Workflow, error) {
 	root := p.buildTask("", map[string]interface{}{})
 	tagID := name
 
-	if len(p.Pipeline) > 0 {
+	if p.Matrix != nil {
+		if err = p.buildMatrixNodes(root, tagID); err != nil {
+			return nil, err
+		}
+	} else if len(p.Pipeline) > 0 {
 		for _, entry := range p.Pipeline {
 			if err = p.buildWorkflowNodes(entry.Key, entry.Value, root, tagID, p.State); err != nil {
 				return nil, err
@@ -281,6 +301,55 @@ func (p *Inlined) AsWorkflow(name string, baseURL string) (*Workflow, error) {
 	return workflow, nil
 }
 
+// buildMatrixNodes builds pipeline task per matrix combination, combination values are set by the task init,
+// all combination actions share combination tag, thus each combination is reported as a separate use case
+func (p *Inlined) buildMatrixNodes(root *Task, tagID string) error {
+	if err := p.Matrix.Validate(); err != nil {
+		return err
+	}
+	for i, combination := range p.Matrix.Combinations() {
+		task := p.buildTask(combination.Name, map[string]interface{}{})
+		task.Parallel = p.Matrix.Parallel
+		task.Init = combination.Variables()
+		combinationTagID := tagID + "_" + combination.Name
+		task.MetaTag = &MetaTag{
+			Tag:            combination.Name,
+			TagIndex:       toolbox.AsString(i + 1),
+			TagID:          combinationTagID,
+			TagDescription: combination.Description(),
+		}
+		root.Tasks = append(root.Tasks, task)
+		for _, entry := range p.Pipeline {
+			if err := p.buildWorkflowNodes(entry.Key, entry.Value, task, combinationTagID, p.State); err != nil {
+				return err
+			}
+		}
+		tagMatrixActions(task, task.MetaTag, true)
+	}
+	root.Concurrency = p.Matrix.Concurrency
+	return nil
+}
+
+// tagMatrixActions assigns combination tag to generated action tags, description is set on the first action only
+func tagMatrixActions(task *Task, tag *MetaTag, first bool) bool {
+	for _, action := range task.Actions {
+		if strings.HasPrefix(action.TagID, tag.TagID) {
+			action.TagID = tag.TagID
+		}
+		if action.TagIndex == "" {
+			action.TagIndex = tag.TagIndex
+		}
+		if first && action.TagDescription == "" {
+			action.TagDescription = tag.TagDescription
+		}
+		first = false
+	}
+	for _, subTask := range task.Tasks {
+		first = tagMatrixActions(subTask, tag, first)
+	}
+	return first
+}
+
 func (p *Inlined) normalize(node *TasksNode) {
 	for _, task := range node.Tasks {
 		if task.Name == CatchTask {
@@ -444,6 +513,9 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 		if reset, ok := actionAttributes[failKey]; ok {
 			task.Fail = toolbox.AsBoolean(reset)
 		}
+		if !parentTask.multiAction {
+			setTaskGraphAttributes(task, actionAttributes)
+		}
 		return nil
 	}
 
@@ -464,8 +536,12 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 		if isTemplateNode && "template" == textKey {
 			return true
 		}
-		if textKey == loggingKey || textKey == whenKey || textKey == descriptionKey || textKey == failKey { //abstract node attributes
+		if textKey == loggingKey || textKey == whenKey || textKey == descriptionKey || textKey == failKey || textKey == timeoutMsKey { //abstract node attributes
+			nodeAttributes[textKey] = value
+		}
+		if textKey == dependsOnKey || textKey == parallelKey || textKey == concurrencyKey { //task graph attributes
 			nodeAttributes[textKey] = value
+			return true
 		}
 		flagAsMultiActionIfMatched(textKey, task, value)
 		if value == nil || !toolbox.IsSlice(value) {
@@ -484,6 +560,7 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 	if task == nil {
 		task = parentTask
 	}
+	setTaskGraphAttributes(task, nodeAttributes)
 	if _, actionNode := nodeAttributes[actionKey]; !actionNode && !isTemplateNode {
 		if taskAttributes, _, err := p.groupAttributes(nodeAttributes, state); err == nil {
 			if len(taskAttributes) > 0 {
@@ -495,6 +572,7 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 						task.When = tempTask.When
 						task.Logging = tempTask.Logging
 						task.Description = tempTask.Description
+						task.TimeoutMs = tempTask.TimeoutMs
 					}
 				}
 			}
@@ -503,6 +581,35 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 	return buildErr
 }
 
+func setTaskGraphAttributes(task *Task, attributes map[string]interface{}) {
+	if value, ok := attributes[parallelKey]; ok {
+		task.Parallel = toolbox.AsBoolean(value)
+	}
+	if value, ok := attributes[dependsOnKey]; ok {
+		task.DependsOn = asTaskNames(value)
+	}
+	if value, ok := attributes[concurrencyKey]; ok && task.TasksNode != nil {
+		task.Concurrency = toolbox.AsInt(value)
+	}
+}
+
+func asTaskNames(value interface{}) []string {
+	if toolbox.IsSlice(value) {
+		var result = make([]string, 0)
+		for _, item := range toolbox.AsSlice(value) {
+			result = append(result, toolbox.AsString(item))
+		}
+		return result
+	}
+	var result = make([]string, 0)
+	for _, item := range strings.Split(toolbox.AsString(value), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 func flagAsMultiActionIfMatched(textKey string, task *Task, value interface{}) {
 	for _, key := range multiActionKeys {
 		if textKey == key && toolbox.IsBool(value) {
@@ -429,3 +429,38 @@ pipeline:
 	}
 
 }
+
+func TestInlined_AsWorkflow_Timeout(t *testing.T) {
+	YAML := `timeoutMs: 60000
+pipeline:
+  main:
+    timeoutMs: 5000
+    slow:
+      action: exec:run
+      commands:
+        - sleep 5
+      ':timeoutMs': 1000
+`
+	var mapSlice = &yaml.MapSlice{}
+	if !assert.Nil(t, yaml.NewDecoder(strings.NewReader(YAML)).Decode(mapSlice)) {
+		return
+	}
+	source := map[string]interface{}{}
+	for _, entry := range *mapSlice {
+		source[toolbox.AsString(entry.Key)] = entry.Value
+	}
+	inlined := &Inlined{}
+	if !assert.Nil(t, toolbox.DefaultConverter.AssignConverted(inlined, source)) {
+		return
+	}
+	workflow, err := inlined.AsWorkflow("timeout", "mem://localhost/")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 60000, workflow.TimeoutMs)
+	main, _ := workflow.Task("main")
+	assert.EqualValues(t, 5000, main.TimeoutMs)
+	slow, _ := workflow.Task("slow")
+	assert.EqualValues(t, 1000, slow.Actions[0].TimeoutMs)
+	assert.EqualValues(t, 0, slow.TimeoutMs)
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/viant/toolbox"
+)
+
+// MatrixKey represents state key with the current matrix combination values
+const MatrixKey = "matrix"
+
+var matrixNameExpr = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+
+// Matrix represents pipeline expansion over a cartesian product of parameter values
+type Matrix struct {
+	Values      map[string][]interface{} `description:"parameter values, each combination runs the pipeline once" yaml:",omitempty"`
+	Exclude     []map[string]interface{} `description:"combinations to skip, an entry matches combination if all its values match" yaml:",omitempty"`
+	Parallel    bool                     `description:"flag to run combinations concurrently" yaml:",omitempty"`
+	Concurrency int                      `description:"max number of combinations running concurrently in parallel mode" yaml:",omitempty"`
+}
+
+// MatrixCombination represents a single matrix parameter values combination
+type MatrixCombination struct {
+	Name   string                 //unique combination task name
+	Values map[string]interface{} //parameter values
+	keys   []string
+}
+
+// Description returns key=value combination description
+func (c *MatrixCombination) Description() string {
+	var pairs = make([]string, 0, len(c.keys))
+	for _, key := range c.keys {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", key, c.Values[key]))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// Variables returns init variables publishing combination values as individual state keys and as the matrix map
+func (c *MatrixCombination) Variables() Variables {
+	var result = make(Variables, 0, len(c.keys)+1)
+	var values = make(map[string]interface{})
+	for _, key := range c.keys {
+		result = append(result, &Variable{Name: key, Value: c.Values[key]})
+		values[key] = c.Values[key]
+	}
+	return append(result, &Variable{Name: MatrixKey, Value: values})
+}
+
+// Keys returns sorted matrix parameter names
+func (m *Matrix) Keys() []string {
+	var result = make([]string, 0, len(m.Values))
+	for key := range m.Values {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Validate checks if matrix is valid
+func (m *Matrix) Validate() error {
+	if len(m.Values) == 0 {
+		return fmt.Errorf("matrix values were empty")
+	}
+	for key, values := range m.Values {
+		if len(values) == 0 {
+			return fmt.Errorf("matrix %v values were empty", key)
+		}
+	}
+	if m.Concurrency < 0 {
+		return fmt.Errorf("matrix concurrency was negative: %v", m.Concurrency)
+	}
+	return nil
+}
+
+// Combinations returns cartesian product of matrix values without excluded combinations
+func (m *Matrix) Combinations() []*MatrixCombination {
+	keys := m.Keys()
+	var product = []map[string]interface{}{{}}
+	for _, key := range keys {
+		var expanded = make([]map[string]interface{}, 0, len(product)*len(m.Values[key]))
+		for _, combination := range product {
+			for _, value := range m.Values[key] {
+				var values = make(map[string]interface{})
+				for k, v := range combination {
+					values[k] = v
+				}
+				values[key] = value
+				expanded = append(expanded, values)
+			}
+		}
+		product = expanded
+	}
+	var result = make([]*MatrixCombination, 0, len(product))
+	var names = make(map[string]int)
+	for _, values := range product {
+		if m.isExcluded(values) {
+			continue
+		}
+		var parts = make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, matrixNameExpr.ReplaceAllString(toolbox.AsString(values[key]), "_"))
+		}
+		name := strings.Join(parts, "_")
+		names[name]++
+		if count := names[name]; count > 1 {
+			name = fmt.Sprintf("%v_%v", name, count)
+		}
+		result = append(result, &MatrixCombination{Name: name, Values: values, keys: keys})
+	}
+	return result
+}
+
+func (m *Matrix) isExcluded(values map[string]interface{}) bool {
+	for _, exclude := range m.Exclude {
+		if len(exclude) == 0 {
+			continue
+		}
+		matched := true
+		for key, value := range exclude {
+			if candidate, ok := values[key]; !ok || toolbox.AsString(candidate) != toolbox.AsString(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
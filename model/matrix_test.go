@@ -0,0 +1,149 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"gopkg.in/yaml.v2"
+)
+
+func TestMatrix_Combinations(t *testing.T) {
+	var useCases = []struct {
+		description string
+		matrix      *Matrix
+		expect      []string
+	}{
+		{
+			description: "cartesian product",
+			matrix: &Matrix{Values: map[string][]interface{}{
+				"version": {"1.0", "2.0"},
+				"db":      {"mysql", "pg"},
+			}},
+			expect: []string{"mysql_1.0", "mysql_2.0", "pg_1.0", "pg_2.0"},
+		},
+		{
+			description: "excluded combination",
+			matrix: &Matrix{
+				Values: map[string][]interface{}{
+					"version": {"1.0", "2.0"},
+					"db":      {"mysql", "pg"},
+				},
+				Exclude: []map[string]interface{}{{"db": "pg", "version": "1.0"}},
+			},
+			expect: []string{"mysql_1.0", "mysql_2.0", "pg_2.0"},
+		},
+		{
+			description: "partial exclude",
+			matrix: &Matrix{
+				Values: map[string][]interface{}{
+					"version": {1, 2},
+					"db":      {"mysql", "pg"},
+				},
+				Exclude: []map[string]interface{}{{"version": "2"}},
+			},
+			expect: []string{"mysql_1", "pg_1"},
+		},
+		{
+			description: "sanitized unique names",
+			matrix: &Matrix{Values: map[string][]interface{}{
+				"locale": {"en US", "en/US"},
+			}},
+			expect: []string{"en_US", "en_US_2"},
+		},
+	}
+	for _, useCase := range useCases {
+		var actual []string
+		for _, combination := range useCase.matrix.Combinations() {
+			actual = append(actual, combination.Name)
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestMatrixCombination_Variables(t *testing.T) {
+	matrix := &Matrix{Values: map[string][]interface{}{"db": {"mysql"}, "version": {"1.0"}}}
+	combinations := matrix.Combinations()
+	if !assert.Len(t, combinations, 1) {
+		return
+	}
+	assert.EqualValues(t, "db=mysql, version=1.0", combinations[0].Description())
+	variables := combinations[0].Variables()
+	if !assert.Len(t, variables, 3) {
+		return
+	}
+	assert.EqualValues(t, "db", variables[0].Name)
+	assert.EqualValues(t, "mysql", variables[0].Value)
+	assert.EqualValues(t, MatrixKey, variables[2].Name)
+	assert.EqualValues(t, map[string]interface{}{"db": "mysql", "version": "1.0"}, variables[2].Value)
+}
+
+func TestInlined_AsWorkflow_Matrix(t *testing.T) {
+	YAML := `matrix:
+  values:
+    db: [mysql, pg]
+    version: ['1.0', '2.0']
+  exclude:
+    - db: pg
+      version: '1.0'
+  parallel: true
+  concurrency: 2
+pipeline:
+  build:
+    action: print
+    message: build $version
+  test:
+    action: print
+    message: test $db
+`
+	var mapSlice = &yaml.MapSlice{}
+	if !assert.Nil(t, yaml.NewDecoder(strings.NewReader(YAML)).Decode(mapSlice)) {
+		return
+	}
+	aMap := map[string]interface{}{}
+	var pipeline []*MapEntry
+	for _, entry := range *mapSlice {
+		key := toolbox.AsString(entry.Key)
+		if key == "pipeline" {
+			for _, item := range entry.Value.(yaml.MapSlice) {
+				pipeline = append(pipeline, &MapEntry{Key: toolbox.AsString(item.Key), Value: item.Value})
+			}
+			continue
+		}
+		aMap[key] = entry.Value
+	}
+	inlined := &Inlined{}
+	if !assert.Nil(t, toolbox.DefaultConverter.AssignConverted(inlined, aMap)) {
+		return
+	}
+	inlined.Pipeline = pipeline
+	workflow, err := inlined.AsWorkflow("app", "mem://localhost/")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 2, workflow.Concurrency)
+	assert.True(t, workflow.IsConcurrent())
+	var names []string
+	for _, task := range workflow.Tasks {
+		names = append(names, task.Name)
+	}
+	assert.EqualValues(t, []string{"mysql_1.0", "mysql_2.0", "pg_2.0"}, names)
+
+	task, err := workflow.Task("pg_2.0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, "3", task.TagIndex)
+	assert.EqualValues(t, "db=pg, version=2.0", task.TagDescription)
+	assert.EqualValues(t, "pg", task.Init[0].Value)
+	if !assert.Len(t, task.Tasks, 2) {
+		return
+	}
+	build, test := task.Tasks[0].Actions[0], task.Tasks[1].Actions[0]
+	assert.EqualValues(t, "app_pg_2.0", build.TagID)
+	assert.EqualValues(t, "app_pg_2.0", test.TagID)
+	assert.EqualValues(t, "3", test.TagIndex)
+	assert.EqualValues(t, "db=pg, version=2.0", build.TagDescription)
+	assert.EqualValues(t, "", test.TagDescription)
+}
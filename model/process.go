@@ -21,6 +21,7 @@ type Process struct {
 	State      data.Map
 	Terminated int32
 	Scheduled  *Task
+	Depth      int //number of upstream workflow processes
 	*ExecutionError
 }
 
@@ -56,6 +57,23 @@ func (p *Process) Push(activity *Activity) {
 	p.Activities.Push(activity)
 }
 
+// Fork returns a process copy sharing workflow, state and tag ids, with its own task, activities and error, to run a task concurrently
+func (p *Process) Fork() *Process {
+	var result = &Process{
+		Source:         p.Source,
+		Owner:          p.Owner,
+		TagIDs:         p.TagIDs,
+		Workflow:       p.Workflow,
+		TaskNode:       p.TaskNode,
+		Activities:     NewActivities(),
+		State:          p.State,
+		Terminated:     atomic.LoadInt32(&p.Terminated),
+		Depth:          p.Depth,
+		ExecutionError: &ExecutionError{},
+	}
+	return result
+}
+
 // Push adds a workflow to the workflow stack.
 func (p *Process) AddTagIDs(tagIDs ...string) {
 	for _, tagID := range tagIDs {
@@ -75,6 +93,9 @@ func NewProcess(source *location.Resource, workflow *Workflow, upstream *Process
 		_, process.Owner = toolbox.URLSplit(source.URL)
 	}
 	process.TagIDs = map[string]bool{}
+	if upstream != nil {
+		process.Depth = upstream.Depth + 1
+	}
 	if upstream != nil && len(upstream.TagIDs) > 0 {
 		for k := range upstream.TagIDs {
 			process.TagIDs[k] = true
@@ -96,6 +117,19 @@ func (p *Processes) Push(process *Process) {
 	p.processes = append(p.processes, process)
 }
 
+// Fork returns a copy of the process stack with supplied process on top
+func (p *Processes) Fork(process *Process) *Processes {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	var result = &Processes{
+		mux:       &sync.RWMutex{},
+		processes: make([]*Process, len(p.processes), len(p.processes)+1),
+	}
+	copy(result.processes, p.processes)
+	result.processes = append(result.processes, process)
+	return result
+}
+
 // Pop removes the first workflow from the workflow stack.
 func (p *Processes) Pop() *Process {
 	p.mux.Lock()
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"time"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryDelayMs     = 1000
+	defaultRetryMultiplier  = 2.0
+	//RetryErrorKey represents state key with the last attempt error, used by retry criteria
+	RetryErrorKey = "error"
+	//RetryResponseKey represents state key with the last attempt response, used by retry criteria
+	RetryResponseKey = "response"
+)
+
+// Retry represents action retry policy with exponential backoff
+type Retry struct {
+	MaxAttempts int     `description:"max number of attempts including the first one, default 3" yaml:",omitempty"`
+	DelayMs     int     `description:"delay before the first retry, default 1000" yaml:",omitempty"`
+	Multiplier  float64 `description:"delay multiplier applied after each retry, default 2" yaml:",omitempty"`
+	MaxDelayMs  int     `description:"max delay between attempts" yaml:",omitempty"`
+	Jitter      float64 `description:"random fraction (0..1) of computed delay added or subtracted" yaml:",omitempty"`
+	OnError     string  `description:"regular expression matching failed attempt error eligible for retry" yaml:",omitempty"`
+	When        string  `description:"retry criteria, it can use $error and $response of the last attempt" yaml:",omitempty"`
+	onErrorExpr *regexp.Regexp
+	whenEval    eval.Compute
+}
+
+// Init initialises retry defaults
+func (r *Retry) Init() error {
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if r.DelayMs == 0 {
+		r.DelayMs = defaultRetryDelayMs
+	}
+	if r.Multiplier == 0 {
+		r.Multiplier = defaultRetryMultiplier
+	}
+	if r.OnError != "" && r.onErrorExpr == nil {
+		var err error
+		if r.onErrorExpr, err = regexp.Compile(r.OnError); err != nil {
+			return fmt.Errorf("invalid retry onError: %v, %w", r.OnError, err)
+		}
+	}
+	return r.Validate()
+}
+
+// Validate checks if retry policy is valid
+func (r *Retry) Validate() error {
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("retry maxAttempts was negative: %v", r.MaxAttempts)
+	}
+	if r.Multiplier < 1 {
+		return fmt.Errorf("retry multiplier has to be greater or equal 1: %v", r.Multiplier)
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return fmt.Errorf("retry jitter has to be between 0 and 1: %v", r.Jitter)
+	}
+	return nil
+}
+
+// Delay returns delay before the next attempt, attempt starts with 1
+func (r *Retry) Delay(attempt int) time.Duration {
+	delayMs := float64(r.DelayMs) * math.Pow(r.Multiplier, float64(attempt-1))
+	if r.MaxDelayMs > 0 && delayMs > float64(r.MaxDelayMs) {
+		delayMs = float64(r.MaxDelayMs)
+	}
+	if r.Jitter > 0 {
+		delayMs += delayMs * r.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delayMs) * time.Millisecond
+}
+
+// ShouldRetry returns true if the last attempt qualifies for retry, failed attempt has to match OnError expression,
+// when criteria is specified it has to be met for both failed and successful attempt.
+func (r *Retry) ShouldRetry(context *endly.Context, response interface{}, err error) (bool, error) {
+	if err != nil && r.onErrorExpr != nil && !r.onErrorExpr.MatchString(err.Error()) {
+		return false, nil
+	}
+	if r.When == "" {
+		return err != nil, nil
+	}
+	var contextState = context.State()
+	var state = contextState.Clone()
+	state.Put(RetryResponseKey, response)
+	state.Put(RetryErrorKey, "")
+	if err != nil {
+		state.Put(RetryErrorKey, err.Error())
+	}
+	canRetry, evalErr := criteria.Evaluate(context, state, r.When, &r.whenEval, "Retry.When", false)
+	if evalErr != nil {
+		return false, fmt.Errorf("failed to check retry criteria: %w", evalErr)
+	}
+	return canRetry, nil
+}
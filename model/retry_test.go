@@ -0,0 +1,141 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"gopkg.in/yaml.v2"
+)
+
+func TestRetry_Delay(t *testing.T) {
+	retry := &Retry{DelayMs: 100, Multiplier: 2, MaxDelayMs: 300}
+	if !assert.Nil(t, retry.Init()) {
+		return
+	}
+	assert.EqualValues(t, 100*time.Millisecond, retry.Delay(1))
+	assert.EqualValues(t, 200*time.Millisecond, retry.Delay(2))
+	assert.EqualValues(t, 300*time.Millisecond, retry.Delay(3))
+
+	retry = &Retry{DelayMs: 100, Jitter: 0.5}
+	if !assert.Nil(t, retry.Init()) {
+		return
+	}
+	for i := 0; i < 10; i++ {
+		delay := retry.Delay(2)
+		assert.True(t, delay >= 100*time.Millisecond && delay <= 300*time.Millisecond, delay)
+	}
+}
+
+func TestRetry_Init(t *testing.T) {
+	retry := &Retry{}
+	assert.Nil(t, retry.Init())
+	assert.EqualValues(t, 3, retry.MaxAttempts)
+	assert.EqualValues(t, 1000, retry.DelayMs)
+	assert.EqualValues(t, 2, retry.Multiplier)
+
+	assert.NotNil(t, (&Retry{OnError: "(abc"}).Init())
+	assert.NotNil(t, (&Retry{Jitter: 2}).Init())
+}
+
+func TestRetry_ShouldRetry(t *testing.T) {
+	var useCases = []struct {
+		description string
+		retry       *Retry
+		response    interface{}
+		err         error
+		expect      bool
+	}{
+		{
+			description: "failed attempt",
+			retry:       &Retry{},
+			err:         errors.New("connection refused"),
+			expect:      true,
+		},
+		{
+			description: "successful attempt",
+			retry:       &Retry{},
+			expect:      false,
+		},
+		{
+			description: "matching error",
+			retry:       &Retry{OnError: "refused|timeout"},
+			err:         errors.New("dial tcp: i/o timeout"),
+			expect:      true,
+		},
+		{
+			description: "non matching error",
+			retry:       &Retry{OnError: "refused|timeout"},
+			err:         errors.New("404 not found"),
+			expect:      false,
+		},
+		{
+			description: "response criteria",
+			retry:       &Retry{When: "$response.Code >= 500"},
+			response:    map[string]interface{}{"Code": 503},
+			expect:      true,
+		},
+		{
+			description: "error criteria",
+			retry:       &Retry{When: "$error:/timeout/"},
+			err:         errors.New("404 not found"),
+			expect:      false,
+		},
+	}
+	manager := endly.New()
+	for _, useCase := range useCases {
+		context := manager.NewContext(toolbox.NewContext())
+		if !assert.Nil(t, useCase.retry.Init(), useCase.description) {
+			continue
+		}
+		actual, err := useCase.retry.ShouldRetry(context, useCase.response, useCase.err)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestInlined_AsWorkflow_Retry(t *testing.T) {
+	YAML := `pipeline:
+  ping:
+    action: print
+    message: ping
+    retry:
+      maxAttempts: 5
+      delayMs: 200
+      onError: timeout
+`
+	var mapSlice = &yaml.MapSlice{}
+	if !assert.Nil(t, yaml.NewDecoder(strings.NewReader(YAML)).Decode(mapSlice)) {
+		return
+	}
+	pipeline := map[string]interface{}{}
+	for _, entry := range *mapSlice {
+		pipeline[toolbox.AsString(entry.Key)] = entry.Value
+	}
+	inlined := &Inlined{}
+	if !assert.Nil(t, toolbox.DefaultConverter.AssignConverted(inlined, pipeline)) {
+		return
+	}
+	workflow, err := inlined.AsWorkflow("retry", "mem://localhost/")
+	if !assert.Nil(t, err) {
+		return
+	}
+	ping, err := workflow.Task("ping")
+	if !assert.Nil(t, err) {
+		return
+	}
+	action := ping.Actions[0]
+	if !assert.NotNil(t, action.Retry) {
+		return
+	}
+	assert.EqualValues(t, 5, action.Retry.MaxAttempts)
+	assert.EqualValues(t, 200, action.Retry.DelayMs)
+	assert.EqualValues(t, "timeout", action.Retry.OnError)
+	assert.EqualValues(t, map[string]interface{}{"message": "ping"}, action.Request)
+}
@@ -8,6 +8,8 @@ type Task struct {
 	*TasksNode    ` yaml:",inline"`
 	Fail          bool      ` yaml:",omitempty"` //controls if return fail status workflow on catch task
 	Template      *Template ` yaml:",omitempty"`
+	DependsOn     []string  `description:"sibling task names that have to complete before this task runs" yaml:",omitempty"`
+	Parallel      bool      `description:"flag to run task concurrently with adjacent parallel sibling tasks" yaml:",omitempty"`
 	//internal only for inline workflow meta data
 
 	multiAction bool //flag directing grouping actions (otherwise each action has its own task)
@@ -34,6 +36,9 @@ func (t *Task) Clone() *Task {
 		result.Actions[i] = item.Clone()
 	}
 	result.TasksNode = t.TasksNode.Clone()
+	if len(t.DependsOn) > 0 {
+		result.DependsOn = append([]string{}, t.DependsOn...)
+	}
 	result.AbstractNode = t.AbstractNode.Clone()
 	if t.MetaTag != nil {
 		tag := *t.MetaTag
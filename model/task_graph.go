@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskGraph represents sub tasks dependency graph used to run tasks concurrently
+type TaskGraph struct {
+	Tasks        []*Task
+	Dependencies map[string][]string
+}
+
+func (g *TaskGraph) checkCycles() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	var status = make(map[string]int)
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch status[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("cyclic task dependency: %v", strings.Join(append(path, name), " -> "))
+		}
+		status[name] = visiting
+		for _, dependency := range g.Dependencies[name] {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		status[name] = visited
+		return nil
+	}
+	for _, task := range g.Tasks {
+		if err := visit(task.Name, []string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewTaskGraph builds a dependency graph for runnable sub tasks of supplied node.
+// A task with explicit DependsOn waits only for the listed tasks; a parallel task without DependsOn waits for the closest preceding non-parallel task,
+// and a regular task waits for all preceding tasks, which preserves sequential semantics for tasks without parallel attributes.
+// Dependencies on tasks outside the node (i.e. filtered out by task selector) are ignored.
+func NewTaskGraph(node *TasksNode) (*TaskGraph, error) {
+	var result = &TaskGraph{
+		Tasks:        make([]*Task, 0),
+		Dependencies: make(map[string][]string),
+	}
+	var names = make(map[string]bool)
+	for _, task := range node.Tasks {
+		if task.Name == node.OnErrorTask || task.Name == node.DeferredTask {
+			continue
+		}
+		if names[task.Name] {
+			return nil, fmt.Errorf("duplicate task name: %v, unable to build task graph", task.Name)
+		}
+		names[task.Name] = true
+		result.Tasks = append(result.Tasks, task)
+	}
+	var barrier []string
+	var preceding = make([]string, 0)
+	for _, task := range result.Tasks {
+		var dependencies = make([]string, 0)
+		switch {
+		case len(task.DependsOn) > 0:
+			for _, dependency := range task.DependsOn {
+				dependency = strings.TrimSpace(dependency)
+				if dependency == task.Name {
+					return nil, fmt.Errorf("task %v can not depend on itself", task.Name)
+				}
+				if names[dependency] {
+					dependencies = append(dependencies, dependency)
+				}
+			}
+		case task.Parallel:
+			dependencies = append(dependencies, barrier...)
+		default:
+			dependencies = append(dependencies, preceding...)
+		}
+		result.Dependencies[task.Name] = dependencies
+		if !task.Parallel && len(task.DependsOn) == 0 {
+			barrier = []string{task.Name}
+		}
+		preceding = append(preceding, task.Name)
+	}
+	return result, result.checkCycles()
+}
+
+// ValidateDependencies checks that all sub tasks dependencies are defined and acyclic
+func (t *TasksNode) ValidateDependencies() error {
+	if t == nil || len(t.Tasks) == 0 {
+		return nil
+	}
+	var names = make(map[string]bool)
+	for _, task := range t.Tasks {
+		names[task.Name] = true
+	}
+	for _, task := range t.Tasks {
+		for _, dependency := range task.DependsOn {
+			if !names[strings.TrimSpace(dependency)] {
+				return fmt.Errorf("task %v depends on unknown task: %v", task.Name, dependency)
+			}
+		}
+		if task.TasksNode != nil {
+			if err := task.TasksNode.ValidateDependencies(); err != nil {
+				return err
+			}
+		}
+	}
+	if !t.IsConcurrent() {
+		return nil
+	}
+	_, err := NewTaskGraph(t)
+	return err
+}
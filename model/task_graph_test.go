@@ -0,0 +1,135 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"gopkg.in/yaml.v2"
+	"strings"
+	"testing"
+)
+
+func TestNewTaskGraph(t *testing.T) {
+
+	var useCases = []struct {
+		description  string
+		tasks        []*Task
+		expect       map[string][]string
+		hasError     bool
+		onErrorTask  string
+		deferredTask string
+	}{
+		{
+			description: "sequential tasks",
+			tasks:       []*Task{newGraphTask("a", false), newGraphTask("b", false), newGraphTask("c", false)},
+			expect: map[string][]string{
+				"a": {},
+				"b": {"a"},
+				"c": {"a", "b"},
+			},
+		},
+		{
+			description: "parallel tasks with barrier",
+			tasks:       []*Task{newGraphTask("init", false), newGraphTask("db1", true), newGraphTask("db2", true), newGraphTask("test", false)},
+			expect: map[string][]string{
+				"init": {},
+				"db1":  {"init"},
+				"db2":  {"init"},
+				"test": {"init", "db1", "db2"},
+			},
+		},
+		{
+			description: "explicit dependencies",
+			tasks: []*Task{newGraphTask("a", true), newGraphTask("b", true), newGraphTask("c", false, "a"),
+				newGraphTask("catch", false)},
+			onErrorTask: "catch",
+			expect: map[string][]string{
+				"a": {},
+				"b": {},
+				"c": {"a"},
+			},
+		},
+		{
+			description: "cyclic dependencies",
+			tasks:       []*Task{newGraphTask("a", false, "b"), newGraphTask("b", false, "a")},
+			hasError:    true,
+		},
+		{
+			description: "self dependency",
+			tasks:       []*Task{newGraphTask("a", false, "a")},
+			hasError:    true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		node := &TasksNode{Tasks: useCase.tasks, OnErrorTask: useCase.onErrorTask, DeferredTask: useCase.deferredTask}
+		graph, err := NewTaskGraph(node)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, graph.Dependencies, useCase.description)
+	}
+}
+
+func TestTasksNode_ValidateDependencies(t *testing.T) {
+	node := &TasksNode{Tasks: []*Task{newGraphTask("a", true), newGraphTask("b", false, "x")}}
+	assert.NotNil(t, node.ValidateDependencies())
+	node = &TasksNode{Tasks: []*Task{newGraphTask("a", true), newGraphTask("b", false, "a")}}
+	assert.Nil(t, node.ValidateDependencies())
+}
+
+func TestInlined_AsWorkflow_TaskGraph(t *testing.T) {
+	YAML := `pipeline:
+  prepare:
+    concurrency: 2
+    db1:
+      action: print
+      message: db1
+      parallel: true
+    db2:
+      action: print
+      message: db2
+      parallel: true
+    app:
+      action: print
+      message: app
+      dependsOn: db1
+`
+	var mapSlice = &yaml.MapSlice{}
+	if !assert.Nil(t, yaml.NewDecoder(strings.NewReader(YAML)).Decode(mapSlice)) {
+		return
+	}
+	pipeline := map[string]interface{}{}
+	for _, entry := range *mapSlice {
+		pipeline[toolbox.AsString(entry.Key)] = entry.Value
+	}
+	inlined := &Inlined{}
+	if !assert.Nil(t, toolbox.DefaultConverter.AssignConverted(inlined, pipeline)) {
+		return
+	}
+	workflow, err := inlined.AsWorkflow("graph", "mem://localhost/")
+	if !assert.Nil(t, err) {
+		return
+	}
+	prepare, err := workflow.Task("prepare")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 2, prepare.Concurrency)
+	assert.True(t, prepare.IsConcurrent())
+	db1, _ := workflow.Task("db1")
+	assert.True(t, db1.Parallel)
+	app, _ := workflow.Task("app")
+	assert.EqualValues(t, []string{"db1"}, app.DependsOn)
+	assert.EqualValues(t, map[string]interface{}{"message": "app"}, app.Actions[0].Request)
+}
+
+func newGraphTask(name string, parallel bool, dependsOn ...string) *Task {
+	task := NewTask(name, false)
+	task.Parallel = parallel
+	task.DependsOn = dependsOn
+	return task
+}
@@ -10,6 +10,7 @@ type TasksNode struct {
 	Tasks        Tasks  ` yaml:",omitempty"` //sub tasks
 	OnErrorTask  string ` yaml:",omitempty"` //task that will run if error occur, the final workflow will return this task response
 	DeferredTask string ` yaml:",omitempty"` //task that will always run if there has been previous  error or not
+	Concurrency  int    ` yaml:",omitempty"` //max number of sub tasks running concurrently in parallel mode
 }
 
 type Tasks []*Task
@@ -26,6 +27,7 @@ func (t *TasksNode) Select(selector TasksSelector) *TasksNode {
 	var result = &TasksNode{
 		OnErrorTask:  t.OnErrorTask,
 		DeferredTask: t.DeferredTask,
+		Concurrency:  t.Concurrency,
 		Tasks:        []*Task{},
 	}
 
@@ -82,6 +84,19 @@ func (t *TasksNode) Has(name string) bool {
 	return err == nil
 }
 
+// IsConcurrent returns true if any runnable sub task is parallel or declares dependencies
+func (t *TasksNode) IsConcurrent() bool {
+	for _, task := range t.Tasks {
+		if task.Name == t.OnErrorTask || task.Name == t.DeferredTask {
+			continue
+		}
+		if task.Parallel || len(task.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *TasksNode) Clone() *TasksNode {
 	ret := *t
 	return &ret
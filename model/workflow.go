@@ -42,6 +42,9 @@ func (w *Workflow) Validate() error {
 			return err
 		}
 	}
+	if err := w.TasksNode.ValidateDependencies(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/viant/afs"
+	"github.com/viant/endly/internal/debug"
 	"github.com/viant/endly/internal/webplanner"
 	"github.com/viant/endly/model/location"
 	loader "github.com/viant/endly/model/project/loader"
@@ -61,12 +62,16 @@ import (
 	_ "github.com/viant/endly/service/testing/log"
 	_ "github.com/viant/endly/service/testing/validator"
 
+	_ "github.com/viant/endly/service/testing/endpoint/grpc"
 	_ "github.com/viant/endly/service/testing/endpoint/http"
 	_ "github.com/viant/endly/service/testing/endpoint/smtp"
 	_ "github.com/viant/endly/service/testing/msg"
+	_ "github.com/viant/endly/service/testing/runner/graphql"
+	_ "github.com/viant/endly/service/testing/runner/grpc"
 	_ "github.com/viant/endly/service/testing/runner/http"
 	_ "github.com/viant/endly/service/testing/runner/rest"
 	_ "github.com/viant/endly/service/testing/runner/webdriver"
+	_ "github.com/viant/endly/service/testing/runner/ws"
 
 	_ "github.com/viant/endly/service/deployment/build"
 	_ "github.com/viant/endly/service/deployment/deploy"
@@ -110,6 +115,7 @@ import (
 	_ "github.com/viant/endly/service/system/exec"
 	_ "github.com/viant/endly/service/system/process"
 	_ "github.com/viant/endly/service/system/storage"
+	_ "github.com/viant/endly/service/system/wait"
 
 	"github.com/viant/endly"
 	"github.com/viant/endly/cli"
@@ -118,6 +124,7 @@ import (
 	"github.com/viant/scy/cred"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
+	"io"
 	"log"
 	"os"
 	"os/exec"
@@ -162,11 +169,19 @@ func init() {
 	flag.Bool("g", false, "open test project generator")
 
 	flag.String("u", "", "start HTTP recorder for the supplied URLs (testing/endpoint/http)")
+	flag.String("har", "", "<HAR file>, works only with -u option, captures HTTP traffic into HTTP Archive file")
 	flag.Bool("m", false, "interactive mode (does not terminates process after workflow completes)")
 	flag.Int("e", 5, "max number of failures CLI reported per validation, 0 - all failures reported")
 	flag.String("run", "", "run specified service action it expect valid service:action to run")
 	flag.String("req", "", "optional request URL when run option is specified")
 	flag.String("w", "", "start HTTP webdriver test planner")
+	flag.Bool("plan", false, "dry run, print expanded workflow service:action calls with resolved requests without running them")
+	flag.Bool("checkpoint", false, "persist workflow checkpoint into <log directory>/checkpoint/<sessionID>.json after each completed task")
+	flag.String("resume", "", "<sessionID> of the failed run to resume from its checkpoint, completed tasks are skipped")
+	flag.String("debug", "", "run workflow in debug mode: console (default) or [host]:port to attach IDE at ws://<host:port>/debug, host defaults to 127.0.0.1")
+	flag.String("break", "", "<coma separated breakpoints> for debug mode, i.e. app/build,deploy, without breakpoints debugger pauses on the first node")
+	flag.String("o", cli.OutputText, "<output format>: text or jsonl, jsonl emits every workflow event as JSON line")
+	flag.String("ofile", "", "<file> to write jsonl events to, stdout if empty")
 
 	_ = mysql.SetLogger(&emptyLogger{})
 
@@ -196,20 +211,26 @@ func detectFirstArguments(flagset map[string]string) {
 	}
 }
 
+// bareFlags represents string flags that can be used without explicit value
+var bareFlags = map[string]string{
+	"-d":     "true",
+	"-debug": "console",
+}
+
 func normalizeDFlag() {
 	// Ensure backward compatibility: allow `-d` without value
 	// by converting it to `-d=true` unless a value follows.
 	args := os.Args
 	out := make([]string, 0, len(args))
 	for i := 0; i < len(args); i++ {
-		if args[i] == "-d" {
+		if defaultValue, ok := bareFlags[args[i]]; ok {
 			// Has value attached next and it's not another flag
 			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
 				out = append(out, args[i])
 				continue
 			}
-			// Convert bare -d into -d=true
-			out = append(out, "-d=true")
+			// Convert bare flag into flag with default value, i.e. -d=true
+			out = append(out, args[i]+"="+defaultValue)
 			continue
 		}
 		out = append(out, args[i])
@@ -246,7 +267,7 @@ func Bootstrap() {
 	}
 
 	if URLs, ok := flagset["u"]; ok {
-		startRecorder(strings.Split(URLs, " "))
+		startRecorder(strings.Split(URLs, " "), flagset["har"])
 		return
 	}
 
@@ -317,7 +338,11 @@ func Bootstrap() {
 		return
 	}
 	interactive, ok := flagset["m"]
-	runWorkflow(request, ok && toolbox.AsBoolean(interactive))
+	runner, err := newRunner(flagset)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runWorkflow(runner, request, ok && toolbox.AsBoolean(interactive))
 }
 
 func runAction(ctx context.Context, run string, flagset map[string]string) error {
@@ -352,12 +377,68 @@ func runAction(ctx context.Context, run string, flagset map[string]string) error
 		return nil
 	}
 	interactive, ok := flagset["m"]
-	runWorkflow(request, ok && toolbox.AsBoolean(interactive))
+	runner, err := newRunner(flagset)
+	if err != nil {
+		return err
+	}
+	runWorkflow(runner, request, ok && toolbox.AsBoolean(interactive))
 	return nil
 }
 
-func runWorkflow(request *workflow.RunRequest, interactive bool) {
+// newRunner creates CLI runner with optional debugger and event output, JSON Lines written to stdout replace the text output
+func newRunner(flagset map[string]string) (*cli.Runner, error) {
 	runner := cli.New()
+	var err error
+	if runner.Debugger, err = newDebugger(flagset); err != nil {
+		return nil, err
+	}
+	switch output := flagset["o"]; output {
+	case "", cli.OutputText:
+	case cli.OutputJSONL:
+		if filename := flagset["ofile"]; filename != "" {
+			file, err := os.Create(filename)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create event output file: %v, %w", filename, err)
+			}
+			runner.EventWriter = file
+			runner.EventCloser = file
+			break
+		}
+		runner.EventWriter = os.Stdout
+		runner.Renderer = cli.NewRenderer(io.Discard, 120)
+	default:
+		return nil, fmt.Errorf("unsupported output: %v, supported: %v, %v", output, cli.OutputText, cli.OutputJSONL)
+	}
+	return runner, nil
+}
+
+// newDebugger creates debugger with console or WebSocket frontend if debug mode was requested
+func newDebugger(flagset map[string]string) (*debug.Debugger, error) {
+	mode, ok := flagset["debug"]
+	if !ok {
+		return nil, nil
+	}
+	debugger := debug.NewDebugger()
+	if breakpoints := flagset["break"]; breakpoints != "" {
+		for _, breakpoint := range strings.Split(breakpoints, ",") {
+			debugger.SetBreakpoint(breakpoint, "")
+		}
+	} else {
+		debugger.EnableStepMode(true)
+	}
+	if mode == "" || mode == "console" || toolbox.AsBoolean(mode) {
+		go debug.NewConsole(debugger, os.Stdin, os.Stdout).Start()
+		return debugger, nil
+	}
+	server := debug.NewServer(debugger, mode)
+	if err := server.Start(); err != nil {
+		return nil, err
+	}
+	log.Printf("debugger is waiting for connection at ws://%v/debug\n", server.Address())
+	return debugger, nil
+}
+
+func runWorkflow(runner *cli.Runner, request *workflow.RunRequest, interactive bool) {
 	request.Interactive = interactive
 	err := runner.Run(request)
 	if err != nil {
@@ -827,10 +908,26 @@ func updateBaseRunWithOptions(request *workflow.RunRequest, flagset map[string]s
 	if value, ok := flagset["e"]; ok {
 		request.FailureCount = toolbox.AsInt(value)
 	}
+	if value, ok := flagset["plan"]; ok {
+		request.DryRun = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["checkpoint"]; ok {
+		request.Checkpoint = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["resume"]; ok {
+		request.ResumeSessionID = value
+	}
+	if (request.Checkpoint || request.ResumeSessionID != "") && request.LogDirectory == "" {
+		request.LogDirectory = flag.Lookup("l").Value.String()
+	}
 	return nil
 }
 
-func startRecorder(URLs []string) {
+func startRecorder(URLs []string, harFile string) {
+	if harFile != "" {
+		rec.StartHARRecorder(harFile, URLs...)
+		return
+	}
 	rec.StartRecorder(URLs...)
 }
 
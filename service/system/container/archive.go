@@ -0,0 +1,90 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// archiveReader represents a file reader backed by tar archive stream
+type archiveReader struct {
+	io.Reader
+	io.Closer
+}
+
+// readHeader returns the first archive entry
+func readHeader(archive io.Reader, location string) (*tar.Reader, *tar.Header, error) {
+	reader := tar.NewReader(archive)
+	header, err := reader.Next()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%v: %w", location, os.ErrNotExist)
+	}
+	return reader, header, err
+}
+
+// openArchiveFile returns content of the first archive entry
+func openArchiveFile(archive io.ReadCloser, location string) (io.ReadCloser, error) {
+	reader, header, err := readHeader(archive, location)
+	if err != nil {
+		_ = archive.Close()
+		return nil, err
+	}
+	if header.Typeflag == tar.TypeDir {
+		_ = archive.Close()
+		return nil, fmt.Errorf("%v is a directory", location)
+	}
+	return &archiveReader{Reader: reader, Closer: archive}, nil
+}
+
+// listArchive returns archive root entry info followed by its direct children
+func listArchive(archive io.Reader, location string) ([]os.FileInfo, error) {
+	reader, header, err := readHeader(archive, location)
+	if err != nil {
+		return nil, err
+	}
+	var result = []os.FileInfo{header.FileInfo()}
+	if header.Typeflag != tar.TypeDir {
+		return result, nil
+	}
+	for {
+		if header, err = reader.Next(); err != nil {
+			if err == io.EOF {
+				return result, nil
+			}
+			return nil, err
+		}
+		relative := strings.Trim(header.Name, "/")
+		index := strings.Index(relative, "/")
+		if index == -1 || strings.Contains(relative[index+1:], "/") {
+			continue
+		}
+		result = append(result, header.FileInfo())
+	}
+}
+
+// newFileArchive returns tar archive with a single file
+func newFileArchive(name string, mode os.FileMode, data []byte) (*bytes.Buffer, error) {
+	buffer := new(bytes.Buffer)
+	writer := tar.NewWriter(buffer)
+	if mode == 0 {
+		mode = 0644
+	}
+	header := &tar.Header{
+		Name:     name,
+		Mode:     int64(mode.Perm()),
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}
+	if err := writer.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	return buffer, writer.Close()
+}
@@ -0,0 +1,136 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/viant/afs/file"
+	"github.com/viant/gosh/runner"
+)
+
+// hijackedStdin represents docker exec stdin, close only half-closes connection
+type hijackedStdin struct {
+	response *types.HijackedResponse
+}
+
+func (s *hijackedStdin) Write(data []byte) (int, error) {
+	return s.response.Conn.Write(data)
+}
+
+func (s *hijackedStdin) Close() error {
+	return s.response.CloseWrite()
+}
+
+// NewDockerRunner creates a shell runner inside docker container using docker exec API
+func NewDockerRunner(cli *client.Client, name string, options ...runner.Option) *Runner {
+	return newRunner(func(ctx context.Context, options *runner.Options) (*session, error) {
+		created, err := cli.ContainerExecCreate(ctx, name, dcontainer.ExecOptions{
+			AttachStdin:  true,
+			AttachStdout: true,
+			AttachStderr: true,
+			Env:          options.Environ(),
+			Cmd:          []string{options.Shell},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create exec in container %v: %w", name, err)
+		}
+		attached, err := cli.ContainerExecAttach(ctx, created.ID, dcontainer.ExecAttachOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach exec in container %v: %w", name, err)
+		}
+		stdout, stdoutWriter := io.Pipe()
+		stderr, stderrWriter := io.Pipe()
+		go func() {
+			_, err := stdcopy.StdCopy(stdoutWriter, stderrWriter, attached.Reader)
+			_ = stdoutWriter.CloseWithError(err)
+			_ = stderrWriter.CloseWithError(err)
+		}()
+		result := &session{
+			stdin:  &hijackedStdin{response: &attached},
+			stdout: stdout,
+			stderr: stderr,
+			close: func() error {
+				attached.Close()
+				return nil
+			},
+		}
+		if inspected, err := cli.ContainerExecInspect(ctx, created.ID); err == nil {
+			result.pid = inspected.Pid
+		}
+		return result, nil
+	}, options...)
+}
+
+// dockerTransport represents docker API container transport
+type dockerTransport struct {
+	client *client.Client
+	name   string
+}
+
+func (t *dockerTransport) Stat(ctx context.Context, location string) (os.FileInfo, error) {
+	stat, err := t.client.ContainerStatPath(ctx, t.name, location)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("%v: %w", location, os.ErrNotExist)
+		}
+		return nil, err
+	}
+	return file.NewInfo(stat.Name, stat.Size, stat.Mode, stat.Mtime, stat.Mode.IsDir()), nil
+}
+
+func (t *dockerTransport) Download(ctx context.Context, location string) (io.ReadCloser, error) {
+	reader, _, err := t.client.CopyFromContainer(ctx, t.name, location)
+	if err != nil && client.IsErrNotFound(err) {
+		return nil, fmt.Errorf("%v: %w", location, os.ErrNotExist)
+	}
+	return reader, err
+}
+
+func (t *dockerTransport) Extract(ctx context.Context, directory string, archive io.Reader) error {
+	return t.client.CopyToContainer(ctx, t.name, directory, archive, dcontainer.CopyToContainerOptions{AllowOverwriteDirWithFile: true})
+}
+
+func (t *dockerTransport) Run(ctx context.Context, command ...string) error {
+	created, err := t.client.ContainerExecCreate(ctx, t.name, dcontainer.ExecOptions{AttachStdout: true, AttachStderr: true, Cmd: command})
+	if err != nil {
+		return err
+	}
+	attached, err := t.client.ContainerExecAttach(ctx, created.ID, dcontainer.ExecAttachOptions{})
+	if err != nil {
+		return err
+	}
+	defer attached.Close()
+	stderr := new(bytes.Buffer)
+	if _, err = stdcopy.StdCopy(io.Discard, stderr, attached.Reader); err != nil {
+		return err
+	}
+	inspected, err := t.client.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	if inspected.ExitCode != 0 {
+		return fmt.Errorf("%v failed with exit code %v: %v", strings.Join(command, " "), inspected.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (t *dockerTransport) Close() error {
+	return t.client.Close()
+}
+
+func newDockerTransport(name string) (*dockerTransport, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, err
+	}
+	return &dockerTransport{client: cli, name: path.Clean(name)}, nil
+}
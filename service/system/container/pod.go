@@ -0,0 +1,155 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/viant/gosh/runner"
+)
+
+// kubectl represents kubectl command used for pod targets
+var kubectl = "kubectl"
+
+// podCommand returns kubectl exec command for supplied pod target
+func podCommand(target *Target, interactive bool, command ...string) *exec.Cmd {
+	var args = []string{"exec"}
+	if interactive {
+		args = append(args, "-i")
+	}
+	if target.Namespace != "" {
+		args = append(args, "-n", target.Namespace)
+	}
+	args = append(args, target.Name)
+	if target.Container != "" {
+		args = append(args, "-c", target.Container)
+	}
+	args = append(args, "--")
+	return exec.Command(kubectl, append(args, command...)...)
+}
+
+// NewPodRunner creates a shell runner inside kubernetes pod using kubectl exec
+func NewPodRunner(target *Target, options ...runner.Option) *Runner {
+	return newRunner(func(ctx context.Context, options *runner.Options) (*session, error) {
+		command := []string{options.Shell}
+		if env := options.Environ(); len(env) > 0 {
+			command = append(append([]string{"env"}, env...), options.Shell)
+		}
+		return startProcess(podCommand(target, true, command...))
+	}, options...)
+}
+
+// startProcess starts process with piped stdin, stdout and stderr
+func startProcess(cmd *exec.Cmd) (*session, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %v: %w", cmd.Path, err)
+	}
+	return &session{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		pid:    cmd.Process.Pid,
+		close: func() error {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return nil
+		},
+	}, nil
+}
+
+// processReader represents process stdout reader, close terminates process
+type processReader struct {
+	io.Reader
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+func (r *processReader) Close() error {
+	_ = r.cmd.Process.Kill()
+	_ = r.cmd.Wait()
+	return nil
+}
+
+// podTransport represents kubectl exec based pod transport
+type podTransport struct {
+	target *Target
+}
+
+func (t *podTransport) Stat(ctx context.Context, location string) (os.FileInfo, error) {
+	reader, err := t.download(location)
+	if err != nil {
+		return nil, err
+	}
+	_, header, err := readHeader(reader, location)
+	_ = reader.Close()
+	if err != nil {
+		if strings.Contains(reader.stderr.String(), "No such file") {
+			return nil, fmt.Errorf("%v: %w", location, os.ErrNotExist)
+		}
+		return nil, err
+	}
+	return header.FileInfo(), nil
+}
+
+func (t *podTransport) Download(ctx context.Context, location string) (io.ReadCloser, error) {
+	return t.download(location)
+}
+
+func (t *podTransport) download(location string) (*processReader, error) {
+	parent, name := path.Split(path.Clean(location))
+	if name == "" {
+		parent, name = "/", "."
+	}
+	cmd := podCommand(t.target, false, "tar", "cf", "-", "-C", parent, name)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %v: %w", kubectl, err)
+	}
+	return &processReader{Reader: stdout, cmd: cmd, stderr: stderr}, nil
+}
+
+func (t *podTransport) Extract(ctx context.Context, directory string, archive io.Reader) error {
+	cmd := podCommand(t.target, true, "tar", "xf", "-", "-C", directory)
+	cmd.Stdin = archive
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to extract archive into %v: %w, %s", directory, err, output)
+	}
+	return nil
+}
+
+func (t *podTransport) Run(ctx context.Context, command ...string) error {
+	if output, err := podCommand(t.target, false, command...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v failed: %w, %s", strings.Join(command, " "), err, output)
+	}
+	return nil
+}
+
+func (t *podTransport) Close() error {
+	return nil
+}
+
+func newPodTransport(target *Target) *podTransport {
+	return &podTransport{target: target}
+}
@@ -0,0 +1,98 @@
+package container
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/gosh/runner"
+)
+
+// useFakeKubectl replaces kubectl with a script running pod command locally
+func useFakeKubectl(t *testing.T) func() {
+	script := path.Join(t.TempDir(), "kubectl")
+	err := os.WriteFile(script, []byte("#!/bin/sh\nwhile [ \"$1\" != \"--\" ]; do shift; done\nshift\nexec \"$@\"\n"), 0755)
+	assert.Nil(t, err)
+	previous := kubectl
+	kubectl = script
+	return func() {
+		kubectl = previous
+	}
+}
+
+func TestNewPodRunner(t *testing.T) {
+	defer useFakeKubectl(t)()
+	directory := t.TempDir()
+	podRunner := NewPodRunner(&Target{Scheme: PodScheme, Name: "web"},
+		runner.WithPath(directory),
+		runner.WithEnvironment(map[string]string{"APP_ENV": "test"}),
+	)
+	defer podRunner.Close()
+	ctx := context.Background()
+
+	output, code, err := podRunner.Run(ctx, "pwd")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, directory, strings.TrimSpace(output))
+
+	output, _, err = podRunner.Run(ctx, "echo $APP_ENV")
+	assert.Nil(t, err)
+	assert.Equal(t, "test", strings.TrimSpace(output))
+
+	_, code, err = podRunner.Run(ctx, "ls /no/such/dir")
+	assert.Nil(t, err)
+	assert.NotEqual(t, 0, code)
+	assert.True(t, podRunner.PID() > 0)
+}
+
+func TestPodStorage(t *testing.T) {
+	defer useFakeKubectl(t)()
+	directory := t.TempDir()
+	fs := afs.New()
+	ctx := context.Background()
+	baseURL := "pod://web" + directory
+
+	err := fs.Upload(ctx, baseURL+"/config/app.yaml", 0644, strings.NewReader("port: 8080"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	data, err := os.ReadFile(path.Join(directory, "config/app.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, "port: 8080", string(data))
+
+	assert.Nil(t, os.WriteFile(path.Join(directory, "config/db.yaml"), []byte("host: db"), 0644))
+	assert.Nil(t, os.MkdirAll(path.Join(directory, "config/nested/deep"), 0755))
+
+	exists, err := fs.Exists(ctx, baseURL+"/config/app.yaml")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = fs.Exists(ctx, baseURL+"/config/missing.yaml")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	objects, err := fs.List(ctx, baseURL+"/config")
+	if assert.Nil(t, err) {
+		var names = make([]string, 0)
+		for _, object := range objects[1:] {
+			names = append(names, object.Name())
+		}
+		assert.True(t, objects[0].IsDir())
+		assert.ElementsMatch(t, []string{"app.yaml", "db.yaml", "nested"}, names)
+	}
+
+	reader, err := fs.OpenURL(ctx, baseURL+"/config/db.yaml")
+	if assert.Nil(t, err) {
+		content, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		assert.Equal(t, "host: db", string(content))
+	}
+
+	assert.Nil(t, fs.Delete(ctx, baseURL+"/config"))
+	_, err = os.Stat(path.Join(directory, "config"))
+	assert.True(t, os.IsNotExist(err))
+}
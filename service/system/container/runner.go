@@ -0,0 +1,118 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/viant/gosh/runner"
+)
+
+// session represents an interactive shell opened inside a container
+type session struct {
+	stdin  io.WriteCloser
+	stdout io.Reader
+	stderr io.Reader
+	pid    int
+	close  func() error
+}
+
+// opener opens a shell session with supplied options
+type opener func(ctx context.Context, options *runner.Options) (*session, error)
+
+// Runner represents a shell runner inside a container
+type Runner struct {
+	inited   uint32
+	open     opener
+	session  *session
+	options  *runner.Options
+	pipeline *runner.Pipeline
+}
+
+// Send sends data to stdin
+func (r *Runner) Send(ctx context.Context, data []byte) (int, error) {
+	if atomic.LoadUint32(&r.inited) == 0 || r.session == nil {
+		return 0, fmt.Errorf("session not started")
+	}
+	return r.session.stdin.Write(data)
+}
+
+// Run runs supplied command
+func (r *Runner) Run(ctx context.Context, command string, options ...runner.Option) (string, int, error) {
+	if err := r.initIfNeeded(ctx); err != nil {
+		return "", 0, err
+	}
+	if !r.pipeline.Running() {
+		return "", 0, r.pipeline.Err()
+	}
+	r.pipeline.Drain(ctx)
+	if r.options.AsPipeline() {
+		if _, err := r.session.stdin.Write([]byte(runner.EnsureLineTermination(command))); err != nil {
+			return "", -1, err
+		}
+		return "", -1, r.pipeline.Listen(ctx, options...)
+	}
+	if _, err := r.session.stdin.Write([]byte(r.pipeline.FormatCmd(command))); err != nil {
+		return "", 0, fmt.Errorf("failed to execute command: %v, err: %v", command, err)
+	}
+	output, _, code, err := r.pipeline.Read(ctx, options...)
+	if r.options.History != nil {
+		r.options.History.Commands = append(r.options.History.Commands, runner.NewCommand(command, output, err))
+	}
+	return output, code, err
+}
+
+// PID returns shell process id
+func (r *Runner) PID() int {
+	if r.session == nil {
+		return 0
+	}
+	return r.session.pid
+}
+
+func (r *Runner) initIfNeeded(ctx context.Context) error {
+	if !atomic.CompareAndSwapUint32(&r.inited, 0, 1) {
+		if r.pipeline == nil {
+			return fmt.Errorf("session failed to start")
+		}
+		return nil
+	}
+	var err error
+	if r.session, err = r.open(ctx, r.options); err != nil {
+		return err
+	}
+	if r.pipeline, err = runner.NewPipeline(ctx, r.session.stdin, r.session.stdout, r.session.stderr, r.options); err != nil {
+		return err
+	}
+	if r.options.Path != "" && r.options.Path != "/" {
+		if _, _, err = r.Run(ctx, "cd "+r.options.Path); err != nil {
+			return err
+		}
+	}
+	if len(r.options.SystemPaths) > 0 {
+		_, _, err = r.Run(ctx, "export PATH=$PATH:"+strings.Join(r.options.SystemPaths, ":"))
+	}
+	return err
+}
+
+// Close closes runner
+func (r *Runner) Close() error {
+	var err error
+	if r.session != nil {
+		_ = r.session.stdin.Close()
+		if r.session.close != nil {
+			err = r.session.close()
+		}
+	}
+	if r.pipeline != nil {
+		_ = r.pipeline.Close()
+	}
+	return err
+}
+
+// newRunner creates a runner for supplied session opener
+func newRunner(open opener, options ...runner.Option) *Runner {
+	return &Runner{open: open, options: runner.NewOptions(options)}
+}
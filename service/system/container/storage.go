@@ -0,0 +1,147 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+
+	"github.com/viant/afs"
+	"github.com/viant/afs/base"
+	"github.com/viant/afs/storage"
+)
+
+// transport represents container file transfer and command execution
+type transport interface {
+	io.Closer
+	//Stat returns location info
+	Stat(ctx context.Context, location string) (os.FileInfo, error)
+	//Download returns tar archive of location
+	Download(ctx context.Context, location string) (io.ReadCloser, error)
+	//Extract extracts tar archive into existing directory
+	Extract(ctx context.Context, directory string, archive io.Reader) error
+	//Run runs command, non zero exit code is reported as an error
+	Run(ctx context.Context, command ...string) error
+}
+
+// storager represents afs storager over container transport
+type storager struct {
+	transport
+}
+
+// Exists returns true if location exists
+func (s *storager) Exists(ctx context.Context, location string, options ...storage.Option) (bool, error) {
+	_, err := s.Stat(ctx, location)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List lists location and its direct children
+func (s *storager) List(ctx context.Context, location string, options ...storage.Option) ([]os.FileInfo, error) {
+	info, err := s.Stat(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []os.FileInfo{info}, nil
+	}
+	archive, err := s.Download(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	return listArchive(archive, location)
+}
+
+// Get returns location info
+func (s *storager) Get(ctx context.Context, location string, options ...storage.Option) (os.FileInfo, error) {
+	return s.Stat(ctx, location)
+}
+
+// Open returns location content reader
+func (s *storager) Open(ctx context.Context, location string, options ...storage.Option) (io.ReadCloser, error) {
+	archive, err := s.Download(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return openArchiveFile(archive, location)
+}
+
+// Upload uploads content into existing parent directory
+func (s *storager) Upload(ctx context.Context, destination string, mode os.FileMode, reader io.Reader, options ...storage.Option) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	parent, name := path.Split(destination)
+	archive, err := newFileArchive(name, mode, data)
+	if err != nil {
+		return err
+	}
+	return s.Extract(ctx, parent, archive)
+}
+
+// Create creates a file or directory with its parents
+func (s *storager) Create(ctx context.Context, destination string, mode os.FileMode, reader io.Reader, isDir bool, options ...storage.Option) error {
+	if isDir {
+		return s.Run(ctx, "mkdir", "-p", destination)
+	}
+	if err := s.Run(ctx, "mkdir", "-p", path.Dir(destination)); err != nil {
+		return err
+	}
+	if reader == nil {
+		reader = &emptyReader{}
+	}
+	return s.Upload(ctx, destination, mode, reader)
+}
+
+// Delete removes location
+func (s *storager) Delete(ctx context.Context, location string, options ...storage.Option) error {
+	return s.Run(ctx, "rm", "-rf", location)
+}
+
+type emptyReader struct{}
+
+func (r *emptyReader) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+// manager represents container storage manager
+type manager struct {
+	*base.Manager
+	scheme string
+}
+
+func (m *manager) provider(ctx context.Context, baseURL string, options ...storage.Option) (storage.Storager, error) {
+	target, err := ParseTarget(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if m.scheme == PodScheme {
+		return &storager{transport: newPodTransport(target)}, nil
+	}
+	dockerTransport, err := newDockerTransport(target.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &storager{transport: dockerTransport}, nil
+}
+
+// newManager creates container storage manager for supplied scheme
+func newManager(scheme string, options ...storage.Option) storage.Manager {
+	result := &manager{scheme: scheme}
+	result.Manager = base.New(result, scheme, result.provider, options)
+	return result
+}
+
+func init() {
+	for _, scheme := range []string{DockerScheme, PodScheme} {
+		scheme := scheme
+		afs.GetRegistry().Register(scheme, func(options ...storage.Option) (storage.Manager, error) {
+			return newManager(scheme, options...), nil
+		})
+	}
+}
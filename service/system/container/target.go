@@ -0,0 +1,73 @@
+// Package container provides docker:// and pod:// targets for exec and storage services,
+// commands run in a shell session opened inside a container, files are transferred as tar archives.
+package container
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	//DockerScheme represents docker container target scheme, i.e. docker://container-name/path
+	DockerScheme = "docker"
+	//PodScheme represents kubernetes pod target scheme, i.e. pod://[container@]pod[.namespace]/path
+	PodScheme = "pod"
+)
+
+// Target represents container target
+type Target struct {
+	Scheme    string
+	Name      string //docker container or pod name
+	Namespace string //pod namespace
+	Container string //pod container
+	Path      string
+}
+
+// IsSupportedScheme returns true if scheme is container scheme
+func IsSupportedScheme(scheme string) bool {
+	return scheme == DockerScheme || scheme == PodScheme
+}
+
+// ParseTarget parses container target URL
+func ParseTarget(URL string) (*Target, error) {
+	parsed, err := url.Parse(URL)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSupportedScheme(parsed.Scheme) {
+		return nil, fmt.Errorf("unsupported container scheme: %v", URL)
+	}
+	result := &Target{Scheme: parsed.Scheme, Name: parsed.Hostname(), Path: parsed.Path}
+	if result.Name == "" {
+		return nil, fmt.Errorf("container name was empty: %v", URL)
+	}
+	if result.Path == "" {
+		result.Path = "/"
+	}
+	result.Path = path.Clean(result.Path)
+	if parsed.Scheme == PodScheme {
+		if parsed.User != nil {
+			result.Container = parsed.User.Username()
+		}
+		if index := strings.Index(result.Name, "."); index != -1 {
+			result.Namespace = result.Name[index+1:]
+			result.Name = result.Name[:index]
+		}
+	}
+	return result, nil
+}
+
+// Address returns target URL without path, it identifies container shell session
+func (t *Target) Address() string {
+	result := t.Scheme + "://"
+	if t.Container != "" {
+		result += t.Container + "@"
+	}
+	result += t.Name
+	if t.Namespace != "" {
+		result += "." + t.Namespace
+	}
+	return result
+}
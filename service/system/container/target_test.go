@@ -0,0 +1,71 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTarget(t *testing.T) {
+	var useCases = []struct {
+		description string
+		URL         string
+		expect      *Target
+		address     string
+		hasError    bool
+	}{
+		{
+			description: "docker container",
+			URL:         "docker://db/var/lib/mysql",
+			expect:      &Target{Scheme: DockerScheme, Name: "db", Path: "/var/lib/mysql"},
+			address:     "docker://db",
+		},
+		{
+			description: "docker container without path",
+			URL:         "docker://db",
+			expect:      &Target{Scheme: DockerScheme, Name: "db", Path: "/"},
+			address:     "docker://db",
+		},
+		{
+			description: "pod",
+			URL:         "pod://web-7d9f/app/",
+			expect:      &Target{Scheme: PodScheme, Name: "web-7d9f", Path: "/app"},
+			address:     "pod://web-7d9f",
+		},
+		{
+			description: "pod with container and namespace",
+			URL:         "pod://api@web-7d9f.dev/app",
+			expect:      &Target{Scheme: PodScheme, Name: "web-7d9f", Namespace: "dev", Container: "api", Path: "/app"},
+			address:     "pod://api@web-7d9f.dev",
+		},
+		{
+			description: "unsupported scheme",
+			URL:         "ssh://127.0.0.1/app",
+			hasError:    true,
+		},
+		{
+			description: "empty container",
+			URL:         "docker:///app",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		actual, err := ParseTarget(useCase.URL)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+		assert.Equal(t, useCase.address, actual.Address(), useCase.description)
+	}
+}
+
+func TestPodCommand(t *testing.T) {
+	cmd := podCommand(&Target{Name: "web", Namespace: "dev", Container: "api"}, true, "tar", "cf", "-")
+	assert.Equal(t, []string{"kubectl", "exec", "-i", "-n", "dev", "web", "-c", "api", "--", "tar", "cf", "-"}, cmd.Args)
+	cmd = podCommand(&Target{Name: "web"}, false, "rm", "-rf", "/tmp/x")
+	assert.Equal(t, []string{"kubectl", "exec", "web", "--", "rm", "-rf", "/tmp/x"}, cmd.Args)
+}
@@ -0,0 +1,421 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/toolbox"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	//ProjectLabel represents compose project container, network and volume label
+	ProjectLabel = "endly.project"
+	//ServiceLabel represents compose service container label
+	ServiceLabel = "endly.service"
+
+	conditionStarted   = "service_started"
+	conditionHealthy   = "service_healthy"
+	conditionCompleted = "service_completed_successfully"
+)
+
+// Compose represents docker-compose v3 file subset
+type Compose struct {
+	Name     string                     `yaml:"name"`
+	Services map[string]*ComposeService `yaml:"services"`
+	Volumes  map[string]interface{}     `yaml:"volumes"`
+}
+
+// ComposeService represents docker-compose service
+type ComposeService struct {
+	Image         string              `yaml:"image"`
+	Build         interface{}         `yaml:"build"`
+	ContainerName string              `yaml:"container_name"`
+	Hostname      string              `yaml:"hostname"`
+	User          string              `yaml:"user"`
+	WorkingDir    string              `yaml:"working_dir"`
+	Command       interface{}         `yaml:"command"`
+	Entrypoint    interface{}         `yaml:"entrypoint"`
+	Environment   interface{}         `yaml:"environment"`
+	Labels        map[string]string   `yaml:"labels"`
+	Ports         []string            `yaml:"ports"`
+	Expose        []string            `yaml:"expose"`
+	Volumes       []string            `yaml:"volumes"`
+	DependsOn     interface{}         `yaml:"depends_on"`
+	Healthcheck   *ComposeHealthcheck `yaml:"healthcheck"`
+}
+
+// ComposeHealthcheck represents docker-compose service health check
+type ComposeHealthcheck struct {
+	Test        interface{} `yaml:"test"`
+	Interval    string      `yaml:"interval"`
+	Timeout     string      `yaml:"timeout"`
+	StartPeriod string      `yaml:"start_period"`
+	Retries     int         `yaml:"retries"`
+	Disable     bool        `yaml:"disable"`
+}
+
+// stack represents loaded compose project
+type stack struct {
+	*Compose
+	Project   string
+	Directory string
+}
+
+// Network returns project network name
+func (s *stack) Network() string {
+	return s.Project + "_default"
+}
+
+// ContainerName returns service container name
+func (s *stack) ContainerName(name string) string {
+	if service := s.Services[name]; service != nil && service.ContainerName != "" {
+		return service.ContainerName
+	}
+	return s.Project + "-" + name
+}
+
+// volumeName returns project prefixed volume name, external volumes are used as is
+func (s *stack) volumeName(name string) string {
+	if options, ok := s.Volumes[name].(map[string]interface{}); ok {
+		if toolbox.AsBoolean(options["external"]) {
+			if external, ok := options["name"]; ok {
+				return toolbox.AsString(external)
+			}
+			return name
+		}
+	}
+	return s.Project + "_" + name
+}
+
+// projectVolumes returns names of project managed (non-external) volumes
+func (s *stack) projectVolumes() []string {
+	var result = make([]string, 0)
+	for name := range s.Volumes {
+		if volume := s.volumeName(name); volume == s.Project+"_"+name {
+			result = append(result, volume)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// loadStack loads compose file or inline spec, project defaults to compose name or file directory name
+func loadStack(context *endly.Context, URL string, spec map[string]interface{}, project string) (*stack, error) {
+	result := &stack{Compose: &Compose{}, Project: project}
+	var content []byte
+	if URL != "" {
+		resource := location.NewResource(context.Expand(URL))
+		text, err := resource.DownloadText()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load compose %v: %w", resource.URL, err)
+		}
+		content = []byte(context.Expand(text))
+		result.Directory, _ = path.Split(resource.Path())
+	} else if len(spec) > 0 {
+		var err error
+		if content, err = yaml.Marshal(spec); err != nil {
+			return nil, err
+		}
+		result.Directory = location.NewResource(".").Path()
+	}
+	if len(content) > 0 {
+		if err := yaml.Unmarshal(content, result.Compose); err != nil {
+			return nil, fmt.Errorf("invalid compose spec: %w", err)
+		}
+	}
+	if result.Project == "" {
+		result.Project = result.Name
+	}
+	if result.Project == "" && URL != "" {
+		result.Project = path.Base(strings.TrimSuffix(result.Directory, "/"))
+	}
+	if result.Project == "" {
+		return nil, fmt.Errorf("project was empty")
+	}
+	result.Project = strings.ToLower(result.Project)
+	return result, nil
+}
+
+// dependencies returns service dependencies with their conditions
+func (s *ComposeService) dependencies() map[string]string {
+	var result = make(map[string]string)
+	switch dependsOn := s.DependsOn.(type) {
+	case []interface{}:
+		for _, name := range dependsOn {
+			result[toolbox.AsString(name)] = conditionStarted
+		}
+	case map[string]interface{}:
+		for name, value := range dependsOn {
+			condition := conditionStarted
+			if options, ok := value.(map[string]interface{}); ok && options["condition"] != nil {
+				condition = toolbox.AsString(options["condition"])
+			}
+			result[name] = condition
+		}
+	}
+	return result
+}
+
+// order returns services in dependency order
+func (c *Compose) order() ([]string, error) {
+	var names = make([]string, 0, len(c.Services))
+	for name := range c.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result = make([]string, 0, len(names))
+	var state = make(map[string]int) //1: visiting, 2: visited
+	var visit func(name string, chain []string) error
+	visit = func(name string, chain []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("circular dependency: %v", strings.Join(append(chain, name), " -> "))
+		case 2:
+			return nil
+		}
+		service, ok := c.Services[name]
+		if !ok {
+			return fmt.Errorf("undefined service: %v, referenced by %v", name, chain[len(chain)-1])
+		}
+		state[name] = 1
+		var dependencies = make([]string, 0)
+		for dependency := range service.dependencies() {
+			dependencies = append(dependencies, dependency)
+		}
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if err := visit(dependency, append(chain, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		result = append(result, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// runRequest returns docker run request for compose service
+func (s *stack) runRequest(name string) (*RunRequest, error) {
+	service := s.Services[name]
+	if service.Image == "" {
+		if service.Build != nil {
+			return nil, fmt.Errorf("%v: build is not supported, use docker:build and image", name)
+		}
+		return nil, fmt.Errorf("%v: image was empty", name)
+	}
+	request := &RunRequest{
+		Name:    s.ContainerName(name),
+		Image:   service.Image,
+		Workdir: service.WorkingDir,
+		Env:     environment(service.Environment),
+	}
+	var err error
+	if request.Cmd, err = commandArgs(service.Command); err != nil {
+		return nil, fmt.Errorf("%v: invalid command: %w", name, err)
+	}
+	if request.Entrypoint, err = commandArgs(service.Entrypoint); err != nil {
+		return nil, fmt.Errorf("%v: invalid entrypoint: %w", name, err)
+	}
+	if err = request.Init(); err != nil {
+		return nil, err
+	}
+	config, hostConfig := request.Config, request.HostConfig
+	config.Hostname = service.Hostname
+	config.User = service.User
+	config.Labels = map[string]string{ProjectLabel: s.Project, ServiceLabel: name}
+	for key, value := range service.Labels {
+		config.Labels[key] = value
+	}
+	if config.ExposedPorts, hostConfig.PortBindings, err = portBindings(service.Ports, service.Expose); err != nil {
+		return nil, fmt.Errorf("%v: %w", name, err)
+	}
+	if hostConfig.Mounts, err = s.mounts(service.Volumes); err != nil {
+		return nil, fmt.Errorf("%v: %w", name, err)
+	}
+	if config.Healthcheck, err = healthConfig(service.Healthcheck); err != nil {
+		return nil, fmt.Errorf("%v: invalid healthcheck: %w", name, err)
+	}
+	hostConfig.NetworkMode = container.NetworkMode(s.Network())
+	request.NetworkingConfig = &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			s.Network(): {Aliases: []string{name}},
+		},
+	}
+	return request, nil
+}
+
+// mounts converts compose volumes: named volume (project prefixed), bind mount relative to compose directory, with optional :ro mode
+func (s *stack) mounts(volumes []string) ([]mount.Mount, error) {
+	var result = make([]mount.Mount, 0)
+	for _, volume := range volumes {
+		parts := strings.Split(volume, ":")
+		if len(parts) == 1 { //anonymous volume
+			result = append(result, mount.Mount{Type: mount.TypeVolume, Target: parts[0]})
+			continue
+		}
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid volume: %v", volume)
+		}
+		item := mount.Mount{Source: parts[0], Target: parts[1], ReadOnly: len(parts) == 3 && parts[2] == "ro"}
+		switch {
+		case strings.HasPrefix(item.Source, "."):
+			item.Type = mount.TypeBind
+			item.Source = path.Join(s.Directory, item.Source)
+		case strings.HasPrefix(item.Source, "/"), strings.HasPrefix(item.Source, "~"):
+			item.Type = mount.TypeBind
+			item.Source = expandHomeDirectory(item.Source)
+		default:
+			item.Type = mount.TypeVolume
+			item.Source = s.volumeName(item.Source)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// portBindings converts compose ports: [[hostIP:]hostPort:]containerPort[/protocol]
+func portBindings(ports, expose []string) (nat.PortSet, nat.PortMap, error) {
+	exposed := nat.PortSet{}
+	bindings := nat.PortMap{}
+	for _, port := range expose {
+		containerPort, err := nat.NewPort(nat.SplitProtoPort(port))
+		if err != nil {
+			return nil, nil, err
+		}
+		exposed[containerPort] = struct{}{}
+	}
+	for _, port := range ports {
+		mappings, err := nat.ParsePortSpec(port)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid port %v: %w", port, err)
+		}
+		for _, mapping := range mappings {
+			exposed[mapping.Port] = struct{}{}
+			bindings[mapping.Port] = append(bindings[mapping.Port], mapping.Binding)
+		}
+	}
+	return exposed, bindings, nil
+}
+
+// healthConfig converts compose health check
+func healthConfig(check *ComposeHealthcheck) (*container.HealthConfig, error) {
+	if check == nil {
+		return nil, nil
+	}
+	if check.Disable {
+		return &container.HealthConfig{Test: []string{"NONE"}}, nil
+	}
+	result := &container.HealthConfig{Retries: check.Retries}
+	switch test := check.Test.(type) {
+	case string:
+		result.Test = []string{"CMD-SHELL", test}
+	case []interface{}:
+		for _, item := range test {
+			result.Test = append(result.Test, toolbox.AsString(item))
+		}
+	}
+	var err error
+	for _, duration := range []struct {
+		value  string
+		target *time.Duration
+	}{
+		{check.Interval, &result.Interval},
+		{check.Timeout, &result.Timeout},
+		{check.StartPeriod, &result.StartPeriod},
+	} {
+		if duration.value == "" {
+			continue
+		}
+		if *duration.target, err = time.ParseDuration(duration.value); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// environment converts compose environment map or KEY=VALUE list, a key without value is taken from host environment
+func environment(env interface{}) map[string]string {
+	var result = make(map[string]string)
+	switch actual := env.(type) {
+	case map[string]interface{}:
+		for key, value := range actual {
+			if value == nil {
+				result[key] = os.Getenv(key)
+				continue
+			}
+			result[key] = toolbox.AsString(value)
+		}
+	case []interface{}:
+		for _, item := range actual {
+			pair := strings.SplitN(toolbox.AsString(item), "=", 2)
+			if len(pair) == 1 {
+				result[pair[0]] = os.Getenv(pair[0])
+				continue
+			}
+			result[pair[0]] = pair[1]
+		}
+	}
+	return result
+}
+
+// commandArgs converts compose command, a string command is split with shell quoting rules
+func commandArgs(command interface{}) ([]string, error) {
+	switch actual := command.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		var result = make([]string, 0, len(actual))
+		for _, item := range actual {
+			result = append(result, toolbox.AsString(item))
+		}
+		return result, nil
+	}
+	var result = make([]string, 0)
+	var arg strings.Builder
+	var quote rune
+	hasArg := false
+	for _, r := range toolbox.AsString(command) {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			hasArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if hasArg {
+				result = append(result, arg.String())
+				arg.Reset()
+				hasArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote: %v", command)
+	}
+	if hasArg {
+		result = append(result, arg.String())
+	}
+	return result, nil
+}
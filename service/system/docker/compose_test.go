@@ -0,0 +1,169 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestLoadStack(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	context := endly.New().NewContext(nil)
+	URL := path.Join(parent, "test/compose/docker-compose.yaml")
+
+	stack, err := loadStack(context, URL, nil, "")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "compose", stack.Project)
+	assert.Equal(t, "compose_default", stack.Network())
+	assert.Equal(t, "compose-db", stack.ContainerName("db"))
+	assert.Equal(t, "app", stack.ContainerName("app"))
+	assert.Equal(t, []string{"compose_data"}, stack.projectVolumes())
+	assert.Equal(t, map[string]string{"db": conditionHealthy, "cache": conditionStarted}, stack.Services["app"].dependencies())
+
+	order, err := stack.order()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cache", "db", "app"}, order)
+
+	stack, err = loadStack(context, URL, nil, "Test")
+	assert.Nil(t, err)
+	assert.Equal(t, "test", stack.Project)
+
+	stack, err = loadStack(context, "", map[string]interface{}{
+		"name": "inline",
+		"services": map[string]interface{}{
+			"web": map[string]interface{}{"image": "nginx", "depends_on": []interface{}{"api"}},
+			"api": map[string]interface{}{"image": "api:latest"},
+		},
+	}, "")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "inline", stack.Project)
+		order, err = stack.order()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"api", "web"}, order)
+	}
+}
+
+func TestCompose_Order(t *testing.T) {
+	var useCases = []struct {
+		description string
+		services    map[string]*ComposeService
+		expect      []string
+		hasError    bool
+	}{
+		{
+			description: "independent services",
+			services: map[string]*ComposeService{
+				"b": {},
+				"a": {},
+			},
+			expect: []string{"a", "b"},
+		},
+		{
+			description: "transitive dependencies",
+			services: map[string]*ComposeService{
+				"a": {DependsOn: []interface{}{"b"}},
+				"b": {DependsOn: []interface{}{"c"}},
+				"c": {},
+			},
+			expect: []string{"c", "b", "a"},
+		},
+		{
+			description: "circular dependency",
+			services: map[string]*ComposeService{
+				"a": {DependsOn: []interface{}{"b"}},
+				"b": {DependsOn: []interface{}{"a"}},
+			},
+			hasError: true,
+		},
+		{
+			description: "undefined dependency",
+			services: map[string]*ComposeService{
+				"a": {DependsOn: []interface{}{"x"}},
+			},
+			hasError: true,
+		},
+	}
+	for _, useCase := range useCases {
+		compose := &Compose{Services: useCase.services}
+		actual, err := compose.order()
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestStack_RunRequest(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	context := endly.New().NewContext(nil)
+	stack, err := loadStack(context, path.Join(parent, "test/compose/docker-compose.yaml"), nil, "")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	db, err := stack.runRequest("db")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "compose-db", db.Name)
+	assert.Equal(t, "mysql:8.0", db.Config.Image)
+	assert.ElementsMatch(t, []string{"MYSQL_ROOT_PASSWORD=dev", "MYSQL_DATABASE=app"}, db.Config.Env)
+	assert.Equal(t, map[string]string{ProjectLabel: "compose", ServiceLabel: "db"}, db.Config.Labels)
+	assert.Equal(t, []mount.Mount{
+		{Type: mount.TypeVolume, Source: "compose_data", Target: "/var/lib/mysql"},
+		{Type: mount.TypeBind, Source: path.Join(parent, "test/compose/schema"), Target: "/docker-entrypoint-initdb.d", ReadOnly: true},
+	}, db.HostConfig.Mounts)
+	assert.Equal(t, []string{"CMD", "mysqladmin", "ping", "-h", "localhost"}, db.Config.Healthcheck.Test)
+	assert.Equal(t, 2*time.Second, db.Config.Healthcheck.Interval)
+	assert.Equal(t, 20, db.Config.Healthcheck.Retries)
+	assert.EqualValues(t, "compose_default", db.HostConfig.NetworkMode)
+	assert.Equal(t, []string{"db"}, db.NetworkingConfig.EndpointsConfig["compose_default"].Aliases)
+
+	cache, err := stack.runRequest("cache")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"redis-server", "--save", "", "--appendonly", "no"}, []string(cache.Config.Cmd))
+
+	app, err := stack.runRequest("app")
+	assert.Nil(t, err)
+	assert.Equal(t, "app", app.Name)
+	assert.ElementsMatch(t, []string{"DB_HOST=db", "CACHE_HOST=cache"}, app.Config.Env)
+	assert.Equal(t, nat.PortMap{"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}}}, app.HostConfig.PortBindings)
+	_, exposed := app.Config.ExposedPorts["80/tcp"]
+	assert.True(t, exposed)
+
+	stack.Services["build"] = &ComposeService{Build: "."}
+	_, err = stack.runRequest("build")
+	assert.NotNil(t, err)
+}
+
+func TestCommandArgs(t *testing.T) {
+	var useCases = []struct {
+		description string
+		command     interface{}
+		expect      []string
+		hasError    bool
+	}{
+		{description: "empty command"},
+		{description: "list command", command: []interface{}{"sh", "-c", "echo 1"}, expect: []string{"sh", "-c", "echo 1"}},
+		{description: "string command", command: "sh -c 'echo 1'", expect: []string{"sh", "-c", "echo 1"}},
+		{description: "unterminated quote", command: "sh -c 'echo 1", hasError: true},
+	}
+	for _, useCase := range useCases {
+		actual, err := commandArgs(useCase.command)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
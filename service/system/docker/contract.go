@@ -158,6 +158,46 @@ type LogsResponse struct {
 	Stdout string
 }
 
+// UpRequest represents compose-style stack up request
+type UpRequest struct {
+	Compose   string                 `description:"docker-compose v3 file location"`
+	Spec      map[string]interface{} `description:"inline docker-compose v3 spec, used when compose location is empty"`
+	Project   string                 `description:"project name, used as network, container and volume prefix, default: compose name or compose file directory"`
+	TimeoutMs int                    `description:"max time to wait for dependencies and health checks, default 120000"`
+}
+
+// UpResponse represents compose-style stack up response
+type UpResponse struct {
+	Project  string
+	Network  string
+	Services []*ServiceStatus
+}
+
+// ServiceStatus represents compose service container status
+type ServiceStatus struct {
+	Service     string
+	Name        string
+	ContainerID string
+	Status      string
+	Health      string
+}
+
+// DownRequest represents compose-style stack down request
+type DownRequest struct {
+	Compose     string                 `description:"docker-compose v3 file location"`
+	Spec        map[string]interface{} `description:"inline docker-compose v3 spec"`
+	Project     string                 `description:"project name, default: compose name or compose file directory"`
+	KeepVolumes bool                   `description:"keep project volumes, removed by default"`
+}
+
+// DownResponse represents compose-style stack down response
+type DownResponse struct {
+	Project    string
+	Containers []string
+	Network    string
+	Volumes    []string
+}
+
 // PullRequest represents pull request
 type PullRequest struct {
 	Credentials       string
@@ -270,6 +310,30 @@ func (r *RunRequest) Init() error {
 	return nil
 }
 
+// Init initialises request
+func (r *UpRequest) Init() error {
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = 120000
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *UpRequest) Validate() error {
+	if r.Compose == "" && len(r.Spec) == 0 {
+		return errors.New("compose and spec were empty")
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *DownRequest) Validate() error {
+	if r.Compose == "" && len(r.Spec) == 0 && r.Project == "" {
+		return errors.New("compose, spec and project were empty")
+	}
+	return nil
+}
+
 func (r *RunRequest) Validate() error {
 	if r.Config.Image == "" {
 		return errors.New("image was empty")
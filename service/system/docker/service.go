@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	imgt "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/go-errors/errors"
 	"github.com/viant/endly"
@@ -16,6 +19,7 @@ import (
 	"io/ioutil"
 	"log"
 	"path"
+	"sort"
 	"strings"
 	"time"
 )
@@ -562,6 +566,206 @@ func (s *service) logs(context *endly.Context, request *LogsRequest) (*LogsRespo
 	return response, nil
 }
 
+func (s *service) up(context *endly.Context, request *UpRequest) (*UpResponse, error) {
+	stack, err := loadStack(context, request.Compose, request.Spec, request.Project)
+	if err != nil {
+		return nil, err
+	}
+	order, err := stack.order()
+	if err != nil {
+		return nil, err
+	}
+	var runRequests = make(map[string]*RunRequest)
+	for _, name := range order {
+		if runRequests[name], err = stack.runRequest(name); err != nil {
+			return nil, err
+		}
+	}
+	response := &UpResponse{Project: stack.Project, Network: stack.Network()}
+	if err = s.createNetwork(context, stack); err != nil {
+		return nil, err
+	}
+	for _, name := range stack.projectVolumes() {
+		volumeRequest := &VolumeCreateRequest{}
+		volumeRequest.Name = name
+		volumeRequest.Labels = map[string]string{ProjectLabel: stack.Project}
+		if err = runAdapter(context, volumeRequest, nil); err != nil {
+			return nil, fmt.Errorf("failed to create volume %v: %w", name, err)
+		}
+	}
+	deadline := time.Now().Add(time.Duration(request.TimeoutMs) * time.Millisecond)
+	for _, name := range order {
+		dependencies := stack.Services[name].dependencies()
+		for _, dependency := range order {
+			condition, ok := dependencies[dependency]
+			if !ok {
+				continue
+			}
+			if _, err = s.waitForContainer(context, stack.ContainerName(dependency), condition, deadline); err != nil {
+				return nil, fmt.Errorf("%v depends on %v: %w", name, dependency, err)
+			}
+		}
+		runResponse, err := s.run(context, runRequests[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to start %v: %w", name, err)
+		}
+		publishEvent(context, "up", runRequests[name])
+		response.Services = append(response.Services, &ServiceStatus{Service: name, Name: stack.ContainerName(name), ContainerID: runResponse.ContainerID})
+	}
+	for _, status := range response.Services {
+		condition := conditionStarted
+		if check := stack.Services[status.Service].Healthcheck; check != nil && !check.Disable {
+			condition = conditionHealthy
+		}
+		info, err := s.waitForContainer(context, status.Name, condition, deadline)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", status.Service, err)
+		}
+		status.Status = info.State.Status
+		if info.State.Health != nil {
+			status.Health = info.State.Health.Status
+		}
+	}
+	return response, nil
+}
+
+// createNetwork creates project network unless it already exists
+func (s *service) createNetwork(context *endly.Context, stack *stack) error {
+	listRequest := &NetworkListRequest{}
+	listRequest.Filters = filters.NewArgs(filters.Arg("name", stack.Network()))
+	var networks []network.Summary
+	if err := runAdapter(context, listRequest, &networks); err != nil {
+		return err
+	}
+	for _, candidate := range networks {
+		if candidate.Name == stack.Network() {
+			return nil
+		}
+	}
+	createRequest := &NetworkCreateRequest{Name: stack.Network()}
+	createRequest.Driver = "bridge"
+	createRequest.Labels = map[string]string{ProjectLabel: stack.Project}
+	if err := runAdapter(context, createRequest, nil); err != nil {
+		return fmt.Errorf("failed to create network %v: %w", stack.Network(), err)
+	}
+	return nil
+}
+
+// waitForContainer waits till container satisfies compose depends_on condition
+func (s *service) waitForContainer(context *endly.Context, name, condition string, deadline time.Time) (*types.ContainerJSON, error) {
+	for {
+		info := types.ContainerJSON{}
+		if err := runAdapter(context, &ContainerInspectRequest{ContainerID: name}, &info); err != nil {
+			return nil, err
+		}
+		if info.ContainerJSONBase == nil || info.State == nil {
+			return nil, fmt.Errorf("failed to inspect %v state", name)
+		}
+		state := info.State
+		switch condition {
+		case conditionStarted:
+			if state.Running || state.Status == "exited" {
+				return &info, nil
+			}
+		case conditionHealthy:
+			if state.Health == nil {
+				return nil, fmt.Errorf("%v has no healthcheck", name)
+			}
+			switch state.Health.Status {
+			case container.Healthy:
+				return &info, nil
+			case container.Unhealthy:
+				return nil, fmt.Errorf("%v is unhealthy", name)
+			}
+			if !state.Running {
+				return nil, fmt.Errorf("%v exited with code %v", name, state.ExitCode)
+			}
+		case conditionCompleted:
+			if state.Status == "exited" {
+				if state.ExitCode != 0 {
+					return nil, fmt.Errorf("%v exited with code %v", name, state.ExitCode)
+				}
+				return &info, nil
+			}
+		default:
+			return nil, fmt.Errorf("unsupported depends_on condition: %v", condition)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %v: %v", name, condition)
+		}
+		if context.IsClosed() {
+			return nil, fmt.Errorf("context closed while waiting for %v", name)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func (s *service) down(context *endly.Context, request *DownRequest) (*DownResponse, error) {
+	stack, err := loadStack(context, request.Compose, request.Spec, request.Project)
+	if err != nil {
+		return nil, err
+	}
+	response := &DownResponse{Project: stack.Project, Containers: make([]string, 0), Volumes: make([]string, 0)}
+	projectFilter := filters.NewArgs(filters.Arg("label", ProjectLabel+"="+stack.Project))
+	listRequest := &ContainerListRequest{}
+	listRequest.All = true
+	listRequest.Filters = projectFilter
+	var containers []types.Container
+	if err = runAdapter(context, listRequest, &containers); err != nil {
+		return nil, err
+	}
+	var rank = make(map[string]int)
+	if order, err := stack.order(); err == nil {
+		for i, name := range order {
+			rank[name] = i + 1
+		}
+	}
+	sort.SliceStable(containers, func(i, j int) bool { //dependants first
+		return rank[containers[i].Labels[ServiceLabel]] > rank[containers[j].Labels[ServiceLabel]]
+	})
+	for _, candidate := range containers {
+		removeRequest := &ContainerRemoveRequest{ContainerID: candidate.ID}
+		removeRequest.Force = true
+		removeRequest.RemoveVolumes = !request.KeepVolumes
+		if err = runAdapter(context, removeRequest, nil); err != nil {
+			return nil, err
+		}
+		name := candidate.ID
+		if len(candidate.Names) > 0 {
+			name = strings.TrimPrefix(candidate.Names[0], "/")
+		}
+		response.Containers = append(response.Containers, name)
+	}
+	networkRequest := &NetworkListRequest{}
+	networkRequest.Filters = projectFilter
+	var networks []network.Summary
+	if err = runAdapter(context, networkRequest, &networks); err != nil {
+		return nil, err
+	}
+	for _, candidate := range networks {
+		if err = runAdapter(context, &NetworkRemoveRequest{NetworkID: candidate.ID}, nil); err != nil {
+			return nil, err
+		}
+		response.Network = candidate.Name
+	}
+	if request.KeepVolumes {
+		return response, nil
+	}
+	volumeRequest := &VolumeListRequest{}
+	volumeRequest.Filters = projectFilter
+	volumes := volume.ListResponse{}
+	if err = runAdapter(context, volumeRequest, &volumes); err != nil {
+		return nil, err
+	}
+	for _, candidate := range volumes.Volumes {
+		if err = runAdapter(context, &VolumeRemoveRequest{VolumeID: candidate.Name, Force: true}, nil); err != nil {
+			return nil, err
+		}
+		response.Volumes = append(response.Volumes, candidate.Name)
+	}
+	return response, nil
+}
+
 func (s *service) registerRoutes() {
 	dockerClient := &client.Client{}
 	routes, err := BuildRoutes(dockerClient, GetCtxClient)
@@ -872,6 +1076,48 @@ func (s *service) registerRoutes() {
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
 	})
+	s.Register(&endly.Route{
+		Action:       "up",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "start compose-style stack services in dependency order",
+		},
+		RequestProvider: func() interface{} {
+			return &UpRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &UpResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*UpRequest); ok {
+				return s.up(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action:       "down",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "remove compose-style stack containers, network and volumes",
+		},
+		RequestProvider: func() interface{} {
+			return &DownRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &DownResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*DownRequest); ok {
+				response, err := s.down(context, req)
+				if err == nil {
+					publishEvent(context, "down", response)
+				}
+				return response, err
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
 }
 
 // New creates a new Docker service.
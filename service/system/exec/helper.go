@@ -4,6 +4,7 @@ import (
 	"github.com/viant/endly"
 	"github.com/viant/endly/model"
 	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/service/system/container"
 )
 
 var sessionsKey = (*model.Sessions)(nil)
@@ -25,6 +26,9 @@ func TerminalSessions(context *endly.Context) model.Sessions {
 
 // SessionID returns session I
 func SessionID(context *endly.Context, target *location.Resource) string {
+	if containerTarget, err := container.ParseTarget(target.URL); err == nil {
+		return containerTarget.Address()
+	}
 	username := ""
 	if cred, _ := context.Secrets.GetCredentials(context.Background(), target.Credentials); cred != nil {
 		username = cred.Username
@@ -7,6 +7,8 @@ import (
 	"github.com/viant/endly/model"
 	"github.com/viant/endly/model/criteria"
 	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/service/system/container"
+	"github.com/viant/endly/service/system/docker"
 	"github.com/viant/gosh"
 	"github.com/viant/gosh/runner"
 	"github.com/viant/gosh/runner/local"
@@ -51,6 +53,24 @@ func (s *execService) openExecService(context *endly.Context, request *OpenSessi
 	if err != nil {
 		return nil, err
 	}
+	switch target.Scheme() {
+	case container.DockerScheme:
+		ctxClient, err := docker.GetCtxClient(context)
+		if err != nil {
+			return nil, err
+		}
+		containerTarget, err := container.ParseTarget(target.URL)
+		if err != nil {
+			return nil, err
+		}
+		return gosh.New(context.Background(), container.NewDockerRunner(ctxClient.Client, containerTarget.Name, runner.WithEnvironment(request.Env), runner.WithSystemPaths(request.SystemPaths), runner.WithPath(containerTarget.Path)))
+	case container.PodScheme:
+		containerTarget, err := container.ParseTarget(target.URL)
+		if err != nil {
+			return nil, err
+		}
+		return gosh.New(context.Background(), container.NewPodRunner(containerTarget, runner.WithEnvironment(request.Env), runner.WithSystemPaths(request.SystemPaths), runner.WithPath(containerTarget.Path)))
+	}
 	if target.Hostname() == "localhost" {
 		return gosh.New(context.Background(), local.New(runner.WithEnvironment(request.Env), runner.WithSystemPaths(request.SystemPaths), runner.WithPath(target.Path())))
 	}
@@ -73,7 +93,7 @@ func (s *execService) openExecService(context *endly.Context, request *OpenSessi
 }
 
 func (s *execService) isSupportedScheme(target *location.Resource) bool {
-	return target.Scheme() == "ssh" || target.Scheme() == "scp" || target.Scheme() == "file"
+	return target.Scheme() == "ssh" || target.Scheme() == "scp" || target.Scheme() == "file" || container.IsSupportedScheme(target.Scheme())
 }
 
 func (s *execService) initSession(context *endly.Context, target *location.Resource, session *model.Session, env map[string]string) error {
@@ -203,7 +223,7 @@ func (s *execService) changeDirectory(context *endly.Context, session *model.Ses
 }
 
 func (s *execService) run(context *endly.Context, session *model.Session, command string, listener runner.Listener, timeoutMs int, terminators ...string) (stdout string, code int, err error) {
-	return session.Run(context.Background(), command, runner.WithListener(listener), runner.WithTimeout(timeoutMs), runner.WithTerminators(terminators))
+	return session.Run(context.Background(), command, runner.WithListener(listener), runner.WithTimeout(context.TimeoutMs(timeoutMs)), runner.WithTerminators(terminators))
 }
 
 func (s *execService) rumCommandTemplate(context *endly.Context, session *model.Session, commandTemplate string, arguments ...interface{}) (string, error) {
@@ -8,6 +8,7 @@ import (
 	"github.com/viant/afs/option"
 	"github.com/viant/afsc/auth"
 	"github.com/viant/endly/model/location"
+	_ "github.com/viant/endly/service/system/container" //docker:// and pod:// storage
 	"github.com/viant/scy/cred"
 	"github.com/viant/scy/cred/secret"
 	"sync/atomic"
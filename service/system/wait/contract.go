@@ -0,0 +1,298 @@
+package wait
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeoutMs   = 60000
+	defaultFrequencyMs = 500
+	defaultSQL         = "SELECT 1"
+)
+
+// Timing represents readiness polling settings
+type Timing struct {
+	TimeoutMs   int `description:"max time to wait for all probes, default 60000"`
+	FrequencyMs int `description:"time between probe attempts, default 500"`
+}
+
+// Init initialises timing defaults
+func (t *Timing) Init() {
+	if t.TimeoutMs == 0 {
+		t.TimeoutMs = defaultTimeoutMs
+	}
+	if t.FrequencyMs == 0 {
+		t.FrequencyMs = defaultFrequencyMs
+	}
+}
+
+// Timeout returns timeout duration
+func (t *Timing) Timeout() time.Duration {
+	return time.Duration(t.TimeoutMs) * time.Millisecond
+}
+
+// Frequency returns frequency duration
+func (t *Timing) Frequency() time.Duration {
+	return time.Duration(t.FrequencyMs) * time.Millisecond
+}
+
+// Probe represents a readiness probe, exactly one of TCP, HTTP, Container, Log or SQL has to be specified
+type Probe struct {
+	Name      string     `description:"probe name used in failure report, default probe target"`
+	TCP       string     `description:"host:port, ready when connection is accepted"`
+	HTTP      *HTTPProbe `description:"HTTP endpoint, ready when responded with expected status and body"`
+	Container string     `description:"docker container name, ready when healthy, or running when no healthcheck is defined"`
+	Log       *LogProbe  `description:"docker container logs, ready when pattern is matched"`
+	SQL       *SQLProbe  `description:"dsunit datastore, ready when SQL succeeds"`
+}
+
+// HTTPProbe represents HTTP endpoint probe
+type HTTPProbe struct {
+	URL      string `required:"true"`
+	Method   string `description:"HTTP method, default GET"`
+	Header   map[string]string
+	Code     int    `description:"expected status code, default 200"`
+	Contains string `description:"expected body fragment"`
+	Match    string `description:"expected body regular expression"`
+	expr     *regexp.Regexp
+}
+
+// LogProbe represents docker container log probe
+type LogProbe struct {
+	Container string `required:"true" description:"docker container name"`
+	Pattern   string `required:"true" description:"log line regular expression"`
+	expr      *regexp.Regexp
+}
+
+// SQLProbe represents datastore probe, datastore has to be registered with dsunit:register
+type SQLProbe struct {
+	Datastore string `required:"true" description:"registered dsunit datastore"`
+	SQL       string `description:"probe SQL, default SELECT 1"`
+}
+
+// Kind returns probe kind
+func (p *Probe) Kind() string {
+	switch {
+	case p.TCP != "":
+		return "tcp"
+	case p.HTTP != nil:
+		return "http"
+	case p.Container != "":
+		return "container"
+	case p.Log != nil:
+		return "log"
+	case p.SQL != nil:
+		return "sql"
+	}
+	return ""
+}
+
+// Init initialises probe defaults
+func (p *Probe) Init() (err error) {
+	if p.HTTP != nil {
+		if p.HTTP.Method == "" {
+			p.HTTP.Method = "GET"
+		}
+		if p.HTTP.Code == 0 {
+			p.HTTP.Code = 200
+		}
+		if p.HTTP.Match != "" {
+			if p.HTTP.expr, err = regexp.Compile(p.HTTP.Match); err != nil {
+				return fmt.Errorf("invalid http match: %w", err)
+			}
+		}
+	}
+	if p.Log != nil && p.Log.Pattern != "" {
+		if p.Log.expr, err = regexp.Compile(p.Log.Pattern); err != nil {
+			return fmt.Errorf("invalid log pattern: %w", err)
+		}
+	}
+	if p.SQL != nil && p.SQL.SQL == "" {
+		p.SQL.SQL = defaultSQL
+	}
+	if p.Name == "" {
+		p.Name = p.target()
+	}
+	return nil
+}
+
+func (p *Probe) target() string {
+	switch p.Kind() {
+	case "tcp":
+		return p.TCP
+	case "http":
+		return p.HTTP.URL
+	case "container":
+		return p.Container
+	case "log":
+		return p.Log.Container + " /" + p.Log.Pattern + "/"
+	case "sql":
+		return p.SQL.Datastore
+	}
+	return ""
+}
+
+// Validate checks if probe is valid
+func (p *Probe) Validate() error {
+	count := 0
+	for _, defined := range []bool{p.TCP != "", p.HTTP != nil, p.Container != "", p.Log != nil, p.SQL != nil} {
+		if defined {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("probe %v: expected exactly one of tcp, http, container, log or sql, but had %v", p.Name, count)
+	}
+	switch {
+	case p.HTTP != nil && p.HTTP.URL == "":
+		return fmt.Errorf("probe %v: http URL was empty", p.Name)
+	case p.Log != nil && (p.Log.Container == "" || p.Log.Pattern == ""):
+		return fmt.Errorf("probe %v: log container and pattern are required", p.Name)
+	case p.SQL != nil && p.SQL.Datastore == "":
+		return fmt.Errorf("probe %v: sql datastore was empty", p.Name)
+	}
+	return nil
+}
+
+// ReadyRequest represents a request to wait until all probes are ready
+type ReadyRequest struct {
+	Probes []*Probe `required:"true"`
+	Timing
+}
+
+// Init initialises request
+func (r *ReadyRequest) Init() error {
+	r.Timing.Init()
+	for _, probe := range r.Probes {
+		if probe == nil {
+			continue
+		}
+		if err := probe.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *ReadyRequest) Validate() error {
+	if len(r.Probes) == 0 {
+		return fmt.Errorf("probes were empty")
+	}
+	for i, probe := range r.Probes {
+		if probe == nil {
+			return fmt.Errorf("probe[%v] was empty", i)
+		}
+		if err := probe.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadyResponse represents readiness response
+type ReadyResponse struct {
+	Ready     bool
+	ElapsedMs int
+	Probes    []*ProbeStatus
+}
+
+// ProbeStatus represents probe outcome
+type ProbeStatus struct {
+	Name      string
+	Kind      string
+	Ready     bool
+	Attempts  int
+	ElapsedMs int
+	Error     string `json:",omitempty"`
+}
+
+// NotReady returns probes that never became ready
+func (r *ReadyResponse) NotReady() []*ProbeStatus {
+	var result = make([]*ProbeStatus, 0)
+	for _, probe := range r.Probes {
+		if !probe.Ready {
+			result = append(result, probe)
+		}
+	}
+	return result
+}
+
+// Error returns an error listing not ready probes with their last failure
+func (r *ReadyResponse) Error(timeoutMs int) error {
+	notReady := r.NotReady()
+	if len(notReady) == 0 {
+		return nil
+	}
+	var details = make([]string, 0, len(notReady))
+	for _, probe := range notReady {
+		details = append(details, fmt.Sprintf("%v %v (%v attempts): %v", probe.Kind, probe.Name, probe.Attempts, probe.Error))
+	}
+	return fmt.Errorf("probes not ready after %v ms: %v", timeoutMs, strings.Join(details, "; "))
+}
+
+// NewReadyRequest creates a ready request for supplied probes
+func NewReadyRequest(timing Timing, probes ...*Probe) *ReadyRequest {
+	return &ReadyRequest{Probes: probes, Timing: timing}
+}
+
+// TCPRequest represents a request to wait until TCP port accepts connections
+type TCPRequest struct {
+	Address string `required:"true" description:"host:port"`
+	Timing
+}
+
+// AsReadyRequest converts request to ready request
+func (r *TCPRequest) AsReadyRequest() *ReadyRequest {
+	return NewReadyRequest(r.Timing, &Probe{TCP: r.Address})
+}
+
+// HTTPRequest represents a request to wait until HTTP endpoint responds with expected status and body
+type HTTPRequest struct {
+	HTTPProbe
+	Timing
+}
+
+// AsReadyRequest converts request to ready request
+func (r *HTTPRequest) AsReadyRequest() *ReadyRequest {
+	probe := r.HTTPProbe
+	return NewReadyRequest(r.Timing, &Probe{HTTP: &probe})
+}
+
+// ContainerRequest represents a request to wait until docker container is healthy
+type ContainerRequest struct {
+	Name string `required:"true" description:"docker container name"`
+	Timing
+}
+
+// AsReadyRequest converts request to ready request
+func (r *ContainerRequest) AsReadyRequest() *ReadyRequest {
+	return NewReadyRequest(r.Timing, &Probe{Container: r.Name})
+}
+
+// LogRequest represents a request to wait until docker container logs match pattern
+type LogRequest struct {
+	LogProbe
+	Timing
+}
+
+// AsReadyRequest converts request to ready request
+func (r *LogRequest) AsReadyRequest() *ReadyRequest {
+	probe := r.LogProbe
+	return NewReadyRequest(r.Timing, &Probe{Log: &probe})
+}
+
+// SQLRequest represents a request to wait until datastore accepts queries
+type SQLRequest struct {
+	SQLProbe
+	Timing
+}
+
+// AsReadyRequest converts request to ready request
+func (r *SQLRequest) AsReadyRequest() *ReadyRequest {
+	probe := r.SQLProbe
+	return NewReadyRequest(r.Timing, &Probe{SQL: &probe})
+}
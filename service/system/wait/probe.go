@@ -0,0 +1,107 @@
+package wait
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/viant/endly"
+	"github.com/viant/endly/service/system/docker"
+	"github.com/viant/endly/service/testing/dsunit"
+)
+
+// maxAttemptTimeout represents max time of a single TCP or HTTP probe attempt
+const maxAttemptTimeout = 5 * time.Second
+
+// check runs a single probe attempt, returns nil when probe is ready
+func (s *service) check(context *endly.Context, probe *Probe, timeout time.Duration) error {
+	switch probe.Kind() {
+	case "tcp":
+		return checkTCP(probe.TCP, timeout)
+	case "http":
+		return checkHTTP(probe.HTTP, timeout)
+	case "container":
+		return checkContainer(context, probe.Container)
+	case "log":
+		return checkLog(context, probe.Log)
+	case "sql":
+		return checkSQL(context, probe.SQL)
+	}
+	return fmt.Errorf("unsupported probe: %v", probe.Name)
+}
+
+func checkTCP(address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func checkHTTP(probe *HTTPProbe, timeout time.Duration) error {
+	request, err := http.NewRequest(probe.Method, probe.URL, nil)
+	if err != nil {
+		return err
+	}
+	for key, value := range probe.Header {
+		request.Header.Set(key, value)
+	}
+	client := &http.Client{Timeout: timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != probe.Code {
+		return fmt.Errorf("expected status %v, but had %v", probe.Code, response.StatusCode)
+	}
+	if probe.Contains != "" && !strings.Contains(string(body), probe.Contains) {
+		return fmt.Errorf("body does not contain %q", probe.Contains)
+	}
+	if probe.expr != nil && !probe.expr.Match(body) {
+		return fmt.Errorf("body does not match /%v/", probe.Match)
+	}
+	return nil
+}
+
+func checkContainer(context *endly.Context, name string) error {
+	response := &docker.InspectResponse{}
+	if err := endly.Run(context, &docker.InspectRequest{Name: name}, response); err != nil {
+		return err
+	}
+	if len(response.Info) == 0 || response.Info[0].ContainerJSONBase == nil || response.Info[0].State == nil {
+		return fmt.Errorf("container %v not found", name)
+	}
+	state := response.Info[0].State
+	if !state.Running {
+		return fmt.Errorf("container %v is %v", name, state.Status)
+	}
+	if state.Health != nil && state.Health.Status != container.Healthy {
+		return fmt.Errorf("container %v is %v", name, state.Health.Status)
+	}
+	return nil
+}
+
+func checkLog(context *endly.Context, probe *LogProbe) error {
+	response := &docker.LogsResponse{}
+	request := &docker.LogsRequest{StatusRequest: docker.StatusRequest{Name: probe.Container}}
+	if err := endly.Run(context, request, response); err != nil {
+		return err
+	}
+	if !probe.expr.MatchString(response.Stdout) {
+		return fmt.Errorf("container %v logs do not match /%v/", probe.Container, probe.Pattern)
+	}
+	return nil
+}
+
+func checkSQL(context *endly.Context, probe *SQLProbe) error {
+	return endly.Run(context, &dsunit.QueryRequest{Datastore: probe.Datastore, SQL: probe.SQL}, nil)
+}
@@ -0,0 +1,134 @@
+package wait
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/viant/endly"
+)
+
+// ServiceID represents readiness wait service id
+const ServiceID = "wait"
+
+type service struct {
+	*endly.AbstractService
+}
+
+// readyRequestProvider represents a single probe request
+type readyRequestProvider interface {
+	AsReadyRequest() *ReadyRequest
+}
+
+// ready polls not ready probes in rounds till all are ready or timeout is exceeded
+func (s *service) ready(context *endly.Context, request *ReadyRequest) (*ReadyResponse, error) {
+	started := time.Now()
+	deadline := started.Add(request.Timeout())
+	response := &ReadyResponse{Probes: make([]*ProbeStatus, 0, len(request.Probes))}
+	for _, probe := range request.Probes {
+		response.Probes = append(response.Probes, &ProbeStatus{Name: probe.Name, Kind: probe.Kind()})
+	}
+	for {
+		pending := 0
+		for i, probe := range request.Probes {
+			status := response.Probes[i]
+			if status.Ready {
+				continue
+			}
+			status.Attempts++
+			err := s.check(context, probe, attemptTimeout(deadline))
+			status.ElapsedMs = int(time.Since(started) / time.Millisecond)
+			if err != nil {
+				status.Error = err.Error()
+				pending++
+				continue
+			}
+			status.Ready = true
+			status.Error = ""
+		}
+		if pending == 0 {
+			response.Ready = true
+			break
+		}
+		if context.IsClosed() || time.Now().Add(request.Frequency()).After(deadline) {
+			break
+		}
+		time.Sleep(request.Frequency())
+	}
+	response.ElapsedMs = int(time.Since(started) / time.Millisecond)
+	return response, response.Error(request.TimeoutMs)
+}
+
+// attemptTimeout returns single attempt timeout bounded by deadline
+func attemptTimeout(deadline time.Time) time.Duration {
+	result := time.Until(deadline)
+	if result > maxAttemptTimeout {
+		return maxAttemptTimeout
+	}
+	if result < 100*time.Millisecond {
+		return 100 * time.Millisecond
+	}
+	return result
+}
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "ready",
+		RequestInfo: &endly.ActionInfo{
+			Description: "wait until all tcp, http, container, log and sql probes are ready",
+		},
+		RequestProvider: func() interface{} {
+			return &ReadyRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ReadyResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ReadyRequest); ok {
+				return s.ready(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.registerProbeRoute("tcp", "wait until TCP port accepts connections", func() interface{} { return &TCPRequest{} })
+	s.registerProbeRoute("http", "wait until HTTP endpoint responds with expected status and body", func() interface{} { return &HTTPRequest{} })
+	s.registerProbeRoute("container", "wait until docker container is healthy", func() interface{} { return &ContainerRequest{} })
+	s.registerProbeRoute("log", "wait until docker container logs match pattern", func() interface{} { return &LogRequest{} })
+	s.registerProbeRoute("sql", "wait until dsunit datastore accepts queries", func() interface{} { return &SQLRequest{} })
+}
+
+// registerProbeRoute registers single probe action
+func (s *service) registerProbeRoute(action, description string, provider func() interface{}) {
+	s.Register(&endly.Route{
+		Action: action,
+		RequestInfo: &endly.ActionInfo{
+			Description: description,
+		},
+		RequestProvider: provider,
+		ResponseProvider: func() interface{} {
+			return &ReadyResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(readyRequestProvider); ok {
+				readyRequest := req.AsReadyRequest()
+				if err := readyRequest.Init(); err != nil {
+					return nil, err
+				}
+				if err := readyRequest.Validate(); err != nil {
+					return nil, err
+				}
+				return s.ready(context, readyRequest)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+// New creates a new readiness wait service.
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,127 @@
+package wait_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/service/system/wait"
+)
+
+func TestService_Ready(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer listener.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/health" {
+			_, _ = writer.Write([]byte(`{"status":"UP"}`))
+			return
+		}
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddress := closed.Addr().String()
+	_ = closed.Close()
+
+	var useCases = []struct {
+		description string
+		request     interface{}
+		expectError string
+		expectReady bool
+	}{
+		{
+			description: "tcp port ready",
+			request:     &wait.TCPRequest{Address: listener.Addr().String()},
+			expectReady: true,
+		},
+		{
+			description: "tcp port never ready",
+			request:     &wait.TCPRequest{Address: closedAddress, Timing: wait.Timing{TimeoutMs: 300, FrequencyMs: 50}},
+			expectError: "tcp " + closedAddress,
+		},
+		{
+			description: "http status and body ready",
+			request:     &wait.HTTPRequest{HTTPProbe: wait.HTTPProbe{URL: server.URL + "/health", Contains: "UP", Match: `"status":\s*"UP"`}},
+			expectReady: true,
+		},
+		{
+			description: "http unexpected status",
+			request:     &wait.HTTPRequest{HTTPProbe: wait.HTTPProbe{URL: server.URL + "/"}, Timing: wait.Timing{TimeoutMs: 200, FrequencyMs: 50}},
+			expectError: "expected status 200, but had 503",
+		},
+		{
+			description: "http unexpected body",
+			request:     &wait.HTTPRequest{HTTPProbe: wait.HTTPProbe{URL: server.URL + "/health", Contains: "DOWN"}, Timing: wait.Timing{TimeoutMs: 200, FrequencyMs: 50}},
+			expectError: `body does not contain "DOWN"`,
+		},
+		{
+			description: "multiple probes report only not ready",
+			request: &wait.ReadyRequest{
+				Probes: []*wait.Probe{
+					{TCP: listener.Addr().String()},
+					{Name: "db", TCP: closedAddress},
+				},
+				Timing: wait.Timing{TimeoutMs: 200, FrequencyMs: 50},
+			},
+			expectError: "tcp db",
+		},
+		{
+			description: "invalid probe",
+			request: &wait.ReadyRequest{
+				Probes: []*wait.Probe{{TCP: listener.Addr().String(), Container: "db"}},
+			},
+			expectError: "expected exactly one of",
+		},
+	}
+
+	service := wait.New()
+	for _, useCase := range useCases {
+		context := endly.New().NewContext(nil)
+		serviceResponse := service.Run(context, useCase.request)
+		if useCase.expectError != "" {
+			if assert.NotNil(t, serviceResponse.Err, useCase.description) {
+				assert.Contains(t, serviceResponse.Err.Error(), useCase.expectError, useCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, serviceResponse.Err, useCase.description) {
+			continue
+		}
+		response, ok := serviceResponse.Response.(*wait.ReadyResponse)
+		if assert.True(t, ok, useCase.description) {
+			assert.Equal(t, useCase.expectReady, response.Ready, useCase.description)
+		}
+	}
+}
+
+func TestService_Ready_Delayed(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		if listener, err := net.Listen("tcp", address); err == nil {
+			time.Sleep(time.Second)
+			_ = listener.Close()
+		}
+	}()
+	service := wait.New()
+	context := endly.New().NewContext(nil)
+	serviceResponse := service.Run(context, &wait.TCPRequest{Address: address, Timing: wait.Timing{TimeoutMs: 1000, FrequencyMs: 50}})
+	if !assert.Nil(t, serviceResponse.Err) {
+		return
+	}
+	response := serviceResponse.Response.(*wait.ReadyResponse)
+	assert.True(t, response.Ready)
+	assert.True(t, response.Probes[0].Attempts > 1)
+}
@@ -1,8 +1,12 @@
 package dsunit
 
 import (
+	"errors"
+	"fmt"
 	"github.com/viant/assertly"
 	"github.com/viant/dsunit"
+	"github.com/viant/endly/model/location"
+	"strings"
 )
 
 // InitRequest represents an init request
@@ -133,3 +137,235 @@ func (r *CheckSchemaResponse) Assertion() []*assertly.Validation {
 	}
 	return result
 }
+
+// GenerateRequest represents a request to generate related tables data, generated rows are inserted and/or written as dataset files
+type GenerateRequest struct {
+	Datastore string             `description:"registered datastore used for schema introspection, reference lookup and insert"`
+	Tables    []*TableGenerator  `required:"true" description:"tables to generate, referenced tables are generated first"`
+	Seed      int64              `description:"random seed, the same seed generates the same data, default 1"`
+	Insert    bool               `description:"insert generated rows into datastore"`
+	Dest      *location.Resource `description:"dataset location, rows are written as <prefix><table>.json files for later prepare"`
+	Prefix    string             `description:"dataset file prefix"`
+}
+
+// TableGenerator represents a table data generator
+type TableGenerator struct {
+	Table   string                      `required:"true"`
+	Rows    int                         `required:"true" description:"number of rows to generate"`
+	Columns map[string]*ColumnGenerator `description:"column generators, columns without generator are inferred from introspected schema"`
+	Omit    []string                    `description:"columns excluded from generation, i.e. autoincrement or defaulted columns"`
+}
+
+// ColumnGenerator represents a column value generator
+type ColumnGenerator struct {
+	Kind         string        `description:"sequence,uuid,firstName,lastName,name,email,phone,company,street,city,country,word,text,int,float,bool,date,oneOf,ref,value"`
+	Min          float64       `description:"int and float lower bound"`
+	Max          float64       `description:"int and float upper bound, default 1000"`
+	Distribution string        `description:"uniform (default), normal or exponential, applies to int, float, date and ref"`
+	From         string        `description:"date lower bound, yyyy-MM-dd, default a year before to"`
+	To           string        `description:"date upper bound, yyyy-MM-dd, default today"`
+	Format       string        `description:"date format i.e. yyyy-MM-dd HH:mm:ss or sequence fmt format i.e. ORD-%05d"`
+	Start        int           `description:"sequence start, default 1"`
+	Values       []interface{} `description:"oneOf values"`
+	Weights      []float64     `description:"oneOf values weights"`
+	Ref          string        `description:"foreign key reference table.column, values are picked from generated or existing referenced table rows"`
+	Value        interface{}   `description:"constant value"`
+	NullRatio    float64       `description:"fraction of null values, between 0 and 1"`
+}
+
+// GenerateResponse represents a generate response
+type GenerateResponse struct {
+	Tables []*GeneratedTable
+}
+
+// GeneratedTable represents generated table info
+type GeneratedTable struct {
+	Table string
+	Rows  int
+	URL   string `json:",omitempty"`
+}
+
+// Init initialises request
+func (r *GenerateRequest) Init() error {
+	if r.Seed == 0 {
+		r.Seed = 1
+	}
+	for _, table := range r.Tables {
+		if table == nil {
+			continue
+		}
+		for _, column := range table.Columns {
+			if column != nil {
+				column.Init()
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *GenerateRequest) Validate() error {
+	if len(r.Tables) == 0 {
+		return errors.New("tables were empty")
+	}
+	if !r.Insert && r.Dest == nil {
+		return errors.New("either insert or dest is required")
+	}
+	if r.Insert && r.Datastore == "" {
+		return errors.New("datastore was empty")
+	}
+	for _, table := range r.Tables {
+		if table == nil || table.Table == "" {
+			return errors.New("table was empty")
+		}
+		if table.Rows <= 0 {
+			return fmt.Errorf("%v: rows was empty", table.Table)
+		}
+		for name, column := range table.Columns {
+			if column == nil {
+				return fmt.Errorf("%v.%v: generator was empty", table.Table, name)
+			}
+			if err := column.Validate(); err != nil {
+				return fmt.Errorf("%v.%v: %w", table.Table, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Init initialises generator
+func (g *ColumnGenerator) Init() {
+	if g.Kind == "" {
+		switch {
+		case g.Ref != "":
+			g.Kind = KindRef
+		case len(g.Values) > 0:
+			g.Kind = KindOneOf
+		case g.Value != nil:
+			g.Kind = KindValue
+		}
+	}
+	if g.Start == 0 {
+		g.Start = 1
+	}
+	if g.Min == 0 && g.Max == 0 {
+		g.Max = 1000
+	}
+}
+
+// Validate checks if generator is valid
+func (g *ColumnGenerator) Validate() error {
+	if !isKindSupported(g.Kind) {
+		return fmt.Errorf("unsupported generator kind: '%v'", g.Kind)
+	}
+	if g.Max < g.Min {
+		return fmt.Errorf("invalid range: %v..%v", g.Min, g.Max)
+	}
+	if g.NullRatio < 0 || g.NullRatio > 1 {
+		return fmt.Errorf("invalid null ratio: %v", g.NullRatio)
+	}
+	switch g.Distribution {
+	case "", DistributionUniform, DistributionNormal, DistributionExponential:
+	default:
+		return fmt.Errorf("unsupported distribution: %v", g.Distribution)
+	}
+	switch g.Kind {
+	case KindOneOf:
+		if len(g.Values) == 0 {
+			return errors.New("values were empty")
+		}
+		if len(g.Weights) > 0 && len(g.Weights) != len(g.Values) {
+			return fmt.Errorf("weights count %v does not match values count %v", len(g.Weights), len(g.Values))
+		}
+	case KindRef:
+		table, column := g.refTable()
+		if table == "" || column == "" {
+			return fmt.Errorf("invalid ref: '%v', expected table.column", g.Ref)
+		}
+		for _, name := range append(strings.Split(table, "."), column) {
+			if err := validateIdentifier("ref", name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *ColumnGenerator) refTable() (string, string) {
+	index := strings.LastIndex(g.Ref, ".")
+	if index == -1 {
+		return "", ""
+	}
+	return g.Ref[:index], g.Ref[index+1:]
+}
+
+// SnapshotRequest represents a request to capture datastore tables state under a snapshot name
+type SnapshotRequest struct {
+	Datastore string   `required:"true" description:"registered datastore"`
+	Name      string   `description:"snapshot name, default baseline"`
+	Tables    []string `description:"tables to capture, default all datastore tables except endly_snap_ prefixed snapshot tables, tables are ordered by foreign keys on sqlite3, mysql and postgres, otherwise list parent tables first"`
+	Method    string   `description:"file (sqlite3 only), table (server side table copy) or dump (in memory records), default is driver specific"`
+}
+
+// SnapshotResponse represents a snapshot response
+type SnapshotResponse struct {
+	Name   string
+	Method string
+	Tables []string
+}
+
+// RestoreRequest represents a request to restore datastore tables state from a snapshot
+type RestoreRequest struct {
+	Datastore string `required:"true" description:"registered datastore"`
+	Name      string `description:"snapshot name, default baseline"`
+	Discard   bool   `description:"remove snapshot after restore"`
+}
+
+// RestoreResponse represents a restore response
+type RestoreResponse struct {
+	Name   string
+	Method string
+	Tables []string
+}
+
+// Init initialises request
+func (r *SnapshotRequest) Init() error {
+	if r.Name == "" {
+		r.Name = defaultSnapshotName
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *SnapshotRequest) Validate() error {
+	if r.Datastore == "" {
+		return errors.New("datastore was empty")
+	}
+	switch r.Method {
+	case "", SnapshotMethodFile, SnapshotMethodTable, SnapshotMethodDump:
+	default:
+		return fmt.Errorf("unsupported snapshot method: %v", r.Method)
+	}
+	for _, table := range r.Tables {
+		if err := validateIdentifier("table", table); err != nil {
+			return err
+		}
+	}
+	return validateSnapshotName(r.Name)
+}
+
+// Init initialises request
+func (r *RestoreRequest) Init() error {
+	if r.Name == "" {
+		r.Name = defaultSnapshotName
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *RestoreRequest) Validate() error {
+	if r.Datastore == "" {
+		return errors.New("datastore was empty")
+	}
+	return validateSnapshotName(r.Name)
+}
@@ -0,0 +1,87 @@
+package dsunit
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var firstNames = []string{
+	"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "William", "Elizabeth",
+	"David", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen",
+	"Daniel", "Nancy", "Matthew", "Lisa", "Anthony", "Betty", "Mark", "Margaret", "Paul", "Sandra",
+	"Steven", "Ashley", "Andrew", "Emily", "Kenneth", "Donna", "Joshua", "Michelle", "Kevin", "Carol",
+}
+
+var lastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+	"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+	"Lee", "Perez", "Thompson", "White", "Harris", "Sanchez", "Clark", "Ramirez", "Lewis", "Robinson",
+	"Walker", "Young", "Allen", "King", "Wright", "Scott", "Torres", "Nguyen", "Hill", "Flores",
+}
+
+var companies = []string{
+	"Acme", "Globex", "Initech", "Umbrella", "Stark Industries", "Wayne Enterprises", "Hooli", "Vandelay",
+	"Soylent", "Cyberdyne", "Tyrell", "Wonka", "Aperture", "Gringotts", "Oscorp", "Pied Piper",
+}
+
+var cities = []string{
+	"New York", "Los Angeles", "Chicago", "Houston", "Phoenix", "Philadelphia", "San Antonio", "San Diego",
+	"Dallas", "Austin", "Seattle", "Denver", "Boston", "Portland", "Atlanta", "Miami",
+}
+
+var countries = []string{
+	"United States", "Canada", "Mexico", "Brazil", "United Kingdom", "France", "Germany", "Spain",
+	"Italy", "Poland", "India", "Japan", "Australia", "South Africa", "Egypt", "Argentina",
+}
+
+var streets = []string{
+	"Main", "Oak", "Pine", "Maple", "Cedar", "Elm", "Washington", "Lake", "Hill", "Park", "Sunset", "River",
+}
+
+var streetSuffixes = []string{"St", "Ave", "Rd", "Blvd", "Ln", "Dr", "Way", "Ct"}
+
+var domains = []string{"example.com", "example.org", "example.net", "test.com", "mail.test"}
+
+var words = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+	"minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "commodo",
+}
+
+func pick(random *rand.Rand, values []string) string {
+	return values[random.Intn(len(values))]
+}
+
+func fakeEmail(random *rand.Rand, index int) string {
+	first := strings.ToLower(pick(random, firstNames))
+	last := strings.ToLower(pick(random, lastNames))
+	return fmt.Sprintf("%v.%v%d@%v", first, last, index+1, pick(random, domains))
+}
+
+func fakePhone(random *rand.Rand) string {
+	return fmt.Sprintf("+1-%03d-%03d-%04d", 200+random.Intn(800), random.Intn(1000), random.Intn(10000))
+}
+
+func fakeStreet(random *rand.Rand) string {
+	return fmt.Sprintf("%d %v %v", 1+random.Intn(9999), pick(random, streets), pick(random, streetSuffixes))
+}
+
+func fakeText(random *rand.Rand) string {
+	count := 3 + random.Intn(10)
+	var result = make([]string, count)
+	for i := range result {
+		result[i] = pick(random, words)
+	}
+	text := strings.Join(result, " ")
+	return strings.ToUpper(text[:1]) + text[1:] + "."
+}
+
+// fakeUUID returns random version 4 UUID, generated with supplied (seeded) random source
+func fakeUUID(random *rand.Rand) string {
+	var data = make([]byte, 16)
+	_, _ = random.Read(data)
+	data[6] = (data[6] & 0x0f) | 0x40
+	data[8] = (data[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:])
+}
@@ -0,0 +1,508 @@
+package dsunit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs/url"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	durl "github.com/viant/dsunit/url"
+	"github.com/viant/endly"
+	estorage "github.com/viant/endly/service/system/storage"
+	"github.com/viant/toolbox"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Column generator kinds
+const (
+	KindSequence  = "sequence"
+	KindUUID      = "uuid"
+	KindFirstName = "firstName"
+	KindLastName  = "lastName"
+	KindName      = "name"
+	KindEmail     = "email"
+	KindPhone     = "phone"
+	KindCompany   = "company"
+	KindStreet    = "street"
+	KindCity      = "city"
+	KindCountry   = "country"
+	KindWord      = "word"
+	KindText      = "text"
+	KindInt       = "int"
+	KindFloat     = "float"
+	KindBool      = "bool"
+	KindDate      = "date"
+	KindOneOf     = "oneOf"
+	KindRef       = "ref"
+	KindValue     = "value"
+)
+
+// Generated values distributions
+const (
+	DistributionUniform     = "uniform"
+	DistributionNormal      = "normal"
+	DistributionExponential = "exponential"
+)
+
+const defaultGeneratedDateFormat = "yyyy-MM-dd HH:mm:ss"
+
+var identifierExpr = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// validateIdentifier checks if name can be safely used as SQL identifier
+func validateIdentifier(kind, name string) error {
+	if !identifierExpr.MatchString(name) {
+		return fmt.Errorf("invalid %v: '%v', only letters, digits and underscore are allowed", kind, name)
+	}
+	return nil
+}
+
+// isKindSupported returns true if generator kind is supported
+func isKindSupported(kind string) bool {
+	switch kind {
+	case KindSequence, KindUUID, KindFirstName, KindLastName, KindName, KindEmail, KindPhone, KindCompany,
+		KindStreet, KindCity, KindCountry, KindWord, KindText, KindInt, KindFloat, KindBool, KindDate,
+		KindOneOf, KindRef, KindValue:
+		return true
+	}
+	return false
+}
+
+// generatedColumn represents a column with its generator
+type generatedColumn struct {
+	name      string
+	generator *ColumnGenerator
+	maxLength int
+}
+
+// generator generates table rows with seeded random source, so the same request produces the same data
+type generator struct {
+	random     *rand.Rand
+	today      time.Time
+	rows       map[string][]map[string]interface{}
+	references map[string][]interface{}
+}
+
+func newGenerator(seed int64) *generator {
+	return &generator{
+		random:     rand.New(rand.NewSource(seed)),
+		today:      time.Now().UTC().Truncate(24 * time.Hour),
+		rows:       make(map[string][]map[string]interface{}),
+		references: make(map[string][]interface{}),
+	}
+}
+
+// generateTable generates table rows
+func (g *generator) generateTable(table *TableGenerator, columns []*generatedColumn) ([]map[string]interface{}, error) {
+	var records = make([]map[string]interface{}, table.Rows)
+	for i := range records {
+		var record = make(map[string]interface{})
+		for _, column := range columns {
+			value, err := g.value(column.generator, i)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate %v.%v: %w", table.Table, column.name, err)
+			}
+			if text, ok := value.(string); ok && column.maxLength > 0 && len(text) > column.maxLength {
+				value = text[:column.maxLength]
+			}
+			record[column.name] = value
+		}
+		records[i] = record
+	}
+	g.rows[table.Table] = records
+	return records, nil
+}
+
+// value returns generated value for index-th row
+func (g *generator) value(column *ColumnGenerator, index int) (interface{}, error) {
+	if column.NullRatio > 0 && g.random.Float64() < column.NullRatio {
+		return nil, nil
+	}
+	switch column.Kind {
+	case KindSequence:
+		value := column.Start + index
+		if column.Format != "" {
+			return fmt.Sprintf(column.Format, value), nil
+		}
+		return value, nil
+	case KindUUID:
+		return fakeUUID(g.random), nil
+	case KindFirstName:
+		return pick(g.random, firstNames), nil
+	case KindLastName:
+		return pick(g.random, lastNames), nil
+	case KindName:
+		return pick(g.random, firstNames) + " " + pick(g.random, lastNames), nil
+	case KindEmail:
+		return fakeEmail(g.random, index), nil
+	case KindPhone:
+		return fakePhone(g.random), nil
+	case KindCompany:
+		return pick(g.random, companies), nil
+	case KindStreet:
+		return fakeStreet(g.random), nil
+	case KindCity:
+		return pick(g.random, cities), nil
+	case KindCountry:
+		return pick(g.random, countries), nil
+	case KindWord:
+		return pick(g.random, words), nil
+	case KindText:
+		return fakeText(g.random), nil
+	case KindInt:
+		return int(math.Round(column.Min + g.fraction(column.Distribution)*(column.Max-column.Min))), nil
+	case KindFloat:
+		value := column.Min + g.fraction(column.Distribution)*(column.Max-column.Min)
+		return math.Round(value*100) / 100, nil
+	case KindBool:
+		return g.random.Intn(2) == 1, nil
+	case KindDate:
+		return g.date(column)
+	case KindOneOf:
+		return column.Values[g.weightedIndex(column.Weights, len(column.Values))], nil
+	case KindRef:
+		values, ok := g.references[column.Ref]
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("no values for reference: %v", column.Ref)
+		}
+		index := int(g.fraction(column.Distribution) * float64(len(values)))
+		if index >= len(values) {
+			index = len(values) - 1
+		}
+		return values[index], nil
+	case KindValue:
+		return column.Value, nil
+	}
+	return nil, fmt.Errorf("unsupported generator kind: %v", column.Kind)
+}
+
+// fraction returns value in [0, 1) range for supplied distribution, normal is centered at 0.5, exponential is skewed towards 0
+func (g *generator) fraction(distribution string) float64 {
+	var result float64
+	switch distribution {
+	case DistributionNormal:
+		result = 0.5 + g.random.NormFloat64()/6
+	case DistributionExponential:
+		result = g.random.ExpFloat64() / 5
+	default:
+		return g.random.Float64()
+	}
+	return math.Max(0, math.Min(result, math.Nextafter(1, 0)))
+}
+
+func (g *generator) weightedIndex(weights []float64, count int) int {
+	if len(weights) == 0 {
+		return g.random.Intn(count)
+	}
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	point := g.random.Float64() * total
+	for i, weight := range weights {
+		if point < weight {
+			return i
+		}
+		point -= weight
+	}
+	return count - 1
+}
+
+func (g *generator) date(column *ColumnGenerator) (interface{}, error) {
+	var err error
+	to := g.today
+	if column.To != "" {
+		if to, err = time.Parse("2006-01-02", column.To); err != nil {
+			return nil, fmt.Errorf("invalid to date: %w", err)
+		}
+	}
+	from := to.AddDate(-1, 0, 0)
+	if column.From != "" {
+		if from, err = time.Parse("2006-01-02", column.From); err != nil {
+			return nil, fmt.Errorf("invalid from date: %w", err)
+		}
+	}
+	seconds := int64(g.fraction(column.Distribution) * float64(to.Unix()-from.Unix()))
+	value := from.Add(time.Duration(seconds) * time.Second)
+	format := column.Format
+	if format == "" {
+		format = defaultGeneratedDateFormat
+	}
+	return value.Format(toolbox.DateFormatToLayout(format)), nil
+}
+
+// orderTables returns tables with referenced tables first
+func orderTables(tables []*TableGenerator) ([]*TableGenerator, error) {
+	var byName = make(map[string]*TableGenerator)
+	for _, table := range tables {
+		if _, ok := byName[table.Table]; ok {
+			return nil, fmt.Errorf("duplicate table: %v", table.Table)
+		}
+		byName[table.Table] = table
+	}
+	var result = make([]*TableGenerator, 0, len(tables))
+	var state = make(map[string]int) //1: visiting, 2: visited
+	var visit func(table *TableGenerator, chain []string) error
+	visit = func(table *TableGenerator, chain []string) error {
+		switch state[table.Table] {
+		case 1:
+			return fmt.Errorf("circular reference: %v", strings.Join(append(chain, table.Table), " -> "))
+		case 2:
+			return nil
+		}
+		state[table.Table] = 1
+		for _, name := range sortedColumnNames(table.Columns) {
+			column := table.Columns[name]
+			if column.Kind != KindRef {
+				continue
+			}
+			refTable, _ := column.refTable()
+			if refTable == table.Table {
+				return fmt.Errorf("self reference is not supported: %v.%v", table.Table, name)
+			}
+			if referenced, ok := byName[refTable]; ok {
+				if err := visit(referenced, append(chain, table.Table)); err != nil {
+					return err
+				}
+			}
+		}
+		state[table.Table] = 2
+		result = append(result, table)
+		return nil
+	}
+	for _, table := range tables {
+		if err := visit(table, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func sortedColumnNames(columns map[string]*ColumnGenerator) []string {
+	var result = make([]string, 0, len(columns))
+	for name := range columns {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// inferGenerator returns generator for introspected column based on its name and type
+func inferGenerator(name, dataType string, isKey bool) *ColumnGenerator {
+	var result = &ColumnGenerator{}
+	lowerName := strings.ToLower(strings.Replace(name, "_", "", -1))
+	dataType = strings.ToUpper(dataType)
+	isNumeric := strings.Contains(dataType, "INT") || strings.Contains(dataType, "NUMERIC") || strings.Contains(dataType, "DECIMAL") ||
+		strings.Contains(dataType, "FLOAT") || strings.Contains(dataType, "DOUBLE") || strings.Contains(dataType, "REAL")
+	switch {
+	case isKey && strings.Contains(dataType, "INT"):
+		result.Kind = KindSequence
+	case isKey && !isNumeric:
+		result.Kind = KindUUID
+	case strings.Contains(dataType, "BOOL"):
+		result.Kind = KindBool
+	case strings.Contains(dataType, "DATE") || strings.Contains(dataType, "TIME"):
+		result.Kind = KindDate
+	case strings.Contains(dataType, "INT"):
+		result.Kind = KindInt
+	case isNumeric:
+		result.Kind = KindFloat
+	case strings.Contains(lowerName, "email"):
+		result.Kind = KindEmail
+	case strings.Contains(lowerName, "firstname"):
+		result.Kind = KindFirstName
+	case strings.Contains(lowerName, "lastname") || strings.Contains(lowerName, "surname"):
+		result.Kind = KindLastName
+	case strings.Contains(lowerName, "phone"):
+		result.Kind = KindPhone
+	case strings.Contains(lowerName, "company"):
+		result.Kind = KindCompany
+	case strings.Contains(lowerName, "street") || strings.Contains(lowerName, "address"):
+		result.Kind = KindStreet
+	case strings.Contains(lowerName, "city"):
+		result.Kind = KindCity
+	case strings.Contains(lowerName, "country"):
+		result.Kind = KindCountry
+	case strings.Contains(lowerName, "uuid") || strings.Contains(lowerName, "guid"):
+		result.Kind = KindUUID
+	case strings.HasSuffix(lowerName, "name"):
+		result.Kind = KindName
+	case strings.Contains(lowerName, "desc") || strings.Contains(lowerName, "comment") || strings.Contains(dataType, "TEXT"):
+		result.Kind = KindText
+	default:
+		result.Kind = KindWord
+	}
+	result.Init()
+	return result
+}
+
+// tableColumns returns explicit and (if datastore was specified) introspected table columns generators
+func (s *service) tableColumns(manager dsc.Manager, table *TableGenerator) ([]*generatedColumn, error) {
+	var omit = make(map[string]bool)
+	for _, name := range table.Omit {
+		omit[strings.ToLower(name)] = true
+	}
+	var byName = make(map[string]*generatedColumn)
+	for name, generator := range table.Columns {
+		if !omit[strings.ToLower(name)] {
+			byName[strings.ToLower(name)] = &generatedColumn{name: name, generator: generator}
+		}
+	}
+	if manager != nil {
+		dialect := dsc.GetDatastoreDialect(manager.Config().DriverName)
+		datastore, err := dialect.GetCurrentDatastore(manager)
+		if err != nil {
+			return nil, err
+		}
+		columns, err := dialect.GetColumns(manager, datastore, table.Table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %v columns: %w", table.Table, err)
+		}
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("table %v was not found in %v", table.Table, datastore)
+		}
+		var keys = make(map[string]bool)
+		for _, key := range strings.Split(dialect.GetKeyName(manager, datastore, table.Table), ",") {
+			keys[strings.ToLower(strings.TrimSpace(key))] = true
+		}
+		for _, column := range columns {
+			key := strings.ToLower(column.Name())
+			if omit[key] {
+				continue
+			}
+			generated, ok := byName[key]
+			if !ok {
+				generated = &generatedColumn{name: column.Name(), generator: inferGenerator(column.Name(), column.DatabaseTypeName(), keys[key])}
+				byName[key] = generated
+			}
+			if length, ok := column.Length(); ok && length > 0 && length < math.MaxInt32 {
+				generated.maxLength = int(length)
+			}
+		}
+	}
+	var result = make([]*generatedColumn, 0, len(byName))
+	for _, column := range byName {
+		result = append(result, column)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+	return result, nil
+}
+
+// loadReferences loads referenced column values from generated rows or existing datastore table
+func (s *service) loadReferences(request *GenerateRequest, generator *generator, columns []*generatedColumn) error {
+	for _, column := range columns {
+		if column.generator.Kind != KindRef {
+			continue
+		}
+		if _, ok := generator.references[column.generator.Ref]; ok {
+			continue
+		}
+		table, name := column.generator.refTable()
+		var values = make([]interface{}, 0)
+		if rows, ok := generator.rows[table]; ok {
+			for _, row := range rows {
+				if value := row[name]; value != nil {
+					values = append(values, value)
+				}
+			}
+		} else {
+			if request.Datastore == "" {
+				return fmt.Errorf("unknown reference: %v, table was neither generated nor datastore specified", column.generator.Ref)
+			}
+			response := s.Service.Query(&dsunit.QueryRequest{Datastore: request.Datastore, SQL: fmt.Sprintf("SELECT %v FROM %v ORDER BY %v", name, table, name)})
+			if err := response.Error(); err != nil {
+				return fmt.Errorf("failed to load reference %v: %w", column.generator.Ref, err)
+			}
+			for _, record := range response.Records {
+				for _, value := range record {
+					if value != nil {
+						values = append(values, value)
+					}
+				}
+			}
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("no values for reference: %v", column.generator.Ref)
+		}
+		generator.references[column.generator.Ref] = values
+	}
+	return nil
+}
+
+// generate generates related tables data, inserts it and/or writes it as dataset files
+func (s *service) generate(context *endly.Context, request *GenerateRequest) (*GenerateResponse, error) {
+	tables, err := orderTables(request.Tables)
+	if err != nil {
+		return nil, err
+	}
+	var manager dsc.Manager
+	if request.Datastore != "" {
+		if manager = s.Service.Registry().Get(request.Datastore); manager == nil {
+			return nil, fmt.Errorf("datastore %v was not registered", request.Datastore)
+		}
+	}
+	var response = &GenerateResponse{Tables: make([]*GeneratedTable, 0)}
+	var datasets = make([]*dsunit.Dataset, 0)
+	generator := newGenerator(request.Seed)
+	for _, table := range tables {
+		columns, err := s.tableColumns(manager, table)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.loadReferences(request, generator, columns); err != nil {
+			return nil, err
+		}
+		records, err := generator.generateTable(table, columns)
+		if err != nil {
+			return nil, err
+		}
+		datasets = append(datasets, dsunit.NewDataset(table.Table, records...))
+		response.Tables = append(response.Tables, &GeneratedTable{Table: table.Table, Rows: len(records)})
+	}
+	if request.Dest != nil {
+		if err = s.writeDatasets(context, request, datasets, response.Tables); err != nil {
+			return nil, err
+		}
+	}
+	if request.Insert {
+		prepareResponse := s.Service.Prepare(dsunit.NewPrepareRequest(&dsunit.DatasetResource{
+			Resource:          &durl.Resource{}, //datasets are already in memory
+			DatastoreDatasets: &dsunit.DatastoreDatasets{Datastore: request.Datastore, Datasets: datasets},
+		}))
+		if err = prepareResponse.Error(); err != nil {
+			return nil, fmt.Errorf("failed to insert generated data: %w", err)
+		}
+	}
+	return response, nil
+}
+
+// writeDatasets writes each dataset as <prefix><table>.json file
+func (s *service) writeDatasets(context *endly.Context, request *GenerateRequest, datasets []*dsunit.Dataset, tables []*GeneratedTable) error {
+	dest, storageOptions, err := estorage.GetResourceWithOptions(context, request.Dest)
+	if err != nil {
+		return err
+	}
+	fs, err := estorage.StorageService(context, dest)
+	if err != nil {
+		return err
+	}
+	for i, dataset := range datasets {
+		data, err := json.MarshalIndent(dataset.Records, "", "  ")
+		if err != nil {
+			return err
+		}
+		URL := url.Join(dest.URL, request.Prefix+dataset.Table+".json")
+		if err = fs.Upload(context.Background(), URL, 0644, bytes.NewReader(data), storageOptions...); err != nil {
+			return fmt.Errorf("failed to write %v: %w", URL, err)
+		}
+		tables[i].URL = URL
+	}
+	return nil
+}
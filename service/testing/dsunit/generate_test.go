@@ -0,0 +1,208 @@
+package dsunit
+
+import (
+	"encoding/json"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestService_Generate(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	var newRequest = func(dest string) *GenerateRequest {
+		return &GenerateRequest{
+			Seed:   7,
+			Dest:   location.NewResource(dest),
+			Prefix: "gen_",
+			Tables: []*TableGenerator{
+				{
+					Table: "orders",
+					Rows:  20,
+					Columns: map[string]*ColumnGenerator{
+						"id":      {Kind: KindSequence, Format: "ORD-%03d"},
+						"user_id": {Ref: "users.id", Distribution: DistributionExponential},
+						"amount":  {Kind: KindFloat, Min: 10, Max: 20, Distribution: DistributionNormal},
+						"status":  {Values: []interface{}{"new", "paid"}, Weights: []float64{0, 1}},
+						"created": {Kind: KindDate, From: "2024-01-01", To: "2024-01-31", Format: "yyyy-MM-dd"},
+					},
+				},
+				{
+					Table: "users",
+					Rows:  5,
+					Columns: map[string]*ColumnGenerator{
+						"id":    {Kind: KindSequence, Start: 100},
+						"email": {Kind: KindEmail},
+						"name":  {Kind: KindName},
+						"note":  {Kind: KindText, NullRatio: 1},
+					},
+				},
+			},
+		}
+	}
+
+	var load = func(t *testing.T, URL string) []map[string]interface{} {
+		data, err := os.ReadFile(URL)
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		var records = make([]map[string]interface{}, 0)
+		assert.Nil(t, json.Unmarshal(data, &records))
+		return records
+	}
+
+	var outputs = make([][]map[string]interface{}, 0)
+	for _, dir := range []string{t.TempDir(), t.TempDir()} {
+		var response = &GenerateResponse{}
+		err := endly.Run(context, newRequest(dir), response)
+		if !assert.Nil(t, err) {
+			return
+		}
+		if !assert.Len(t, response.Tables, 2) {
+			return
+		}
+		assert.EqualValues(t, "users", response.Tables[0].Table, "referenced table should be generated first")
+		assert.EqualValues(t, path.Join(dir, "gen_users.json"), response.Tables[0].URL)
+		users := load(t, path.Join(dir, "gen_users.json"))
+		orders := load(t, path.Join(dir, "gen_orders.json"))
+		if !assert.Len(t, users, 5) || !assert.Len(t, orders, 20) {
+			return
+		}
+		var userIDs = make(map[float64]bool)
+		for i, user := range users {
+			assert.EqualValues(t, 100+i, user["id"])
+			assert.Contains(t, user["email"], "@")
+			assert.Nil(t, user["note"])
+			userIDs[user["id"].(float64)] = true
+		}
+		assert.EqualValues(t, "ORD-001", orders[0]["id"])
+		assert.EqualValues(t, "ORD-020", orders[19]["id"])
+		for _, order := range orders {
+			assert.True(t, userIDs[order["user_id"].(float64)], "order should reference generated user")
+			assert.EqualValues(t, "paid", order["status"])
+			amount := order["amount"].(float64)
+			assert.True(t, amount >= 10 && amount <= 20)
+			assert.Regexp(t, `^2024-01-[0-3]\d$`, order["created"])
+		}
+		outputs = append(outputs, orders)
+	}
+	assert.EqualValues(t, outputs[0], outputs[1], "the same seed should generate the same data")
+}
+
+func TestService_GenerateInsert(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	service, err := manager.Service(ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{
+		"url": path.Join(t.TempDir(), "gen.db"),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	response := service.Run(context, &dsunit.RegisterRequest{Datastore: "gendb", Config: config})
+	if !assert.EqualValues(t, "", response.Error) {
+		return
+	}
+	response = service.Run(context, &RunSQLRequest{Datastore: "gendb", SQL: []string{
+		"CREATE TABLE account (id INTEGER PRIMARY KEY, name VARCHAR(8), balance DECIMAL(7,2), created DATETIME)",
+		"CREATE TABLE contact (id INTEGER PRIMARY KEY, account_id INTEGER, email VARCHAR(255))",
+		"INSERT INTO account (id, name) VALUES (42, 'existing')",
+	}})
+	if !assert.EqualValues(t, "", response.Error) {
+		return
+	}
+	response = service.Run(context, &GenerateRequest{
+		Datastore: "gendb",
+		Insert:    true,
+		Tables: []*TableGenerator{
+			{
+				Table: "contact",
+				Rows:  10,
+				Columns: map[string]*ColumnGenerator{
+					"account_id": {Ref: "account.id"},
+				},
+			},
+		},
+	})
+	if !assert.EqualValues(t, "", response.Error) {
+		return
+	}
+	response = service.Run(context, &QueryRequest{Datastore: "gendb", SQL: "SELECT COUNT(*) AS cnt, MIN(account_id) AS min_id, MAX(account_id) AS max_id, MIN(id) AS first_id FROM contact"})
+	if !assert.EqualValues(t, "", response.Error) {
+		return
+	}
+	records := response.Response.(*QueryResponse).Records
+	if assert.Len(t, records, 1) {
+		assert.EqualValues(t, 10, records[0]["cnt"])
+		assert.EqualValues(t, 42, records[0]["min_id"], "contact should reference existing account")
+		assert.EqualValues(t, 42, records[0]["max_id"])
+		assert.EqualValues(t, 1, records[0]["first_id"], "integer key should be generated as sequence")
+	}
+}
+
+func TestGenerateRequest_Validate(t *testing.T) {
+	var useCases = []struct {
+		description string
+		request     *GenerateRequest
+		hasError    bool
+	}{
+		{
+			description: "valid request",
+			request:     &GenerateRequest{Insert: true, Datastore: "db", Tables: []*TableGenerator{{Table: "t", Rows: 1, Columns: map[string]*ColumnGenerator{"id": {Kind: KindSequence}}}}},
+		},
+		{
+			description: "missing output",
+			request:     &GenerateRequest{Tables: []*TableGenerator{{Table: "t", Rows: 1}}},
+			hasError:    true,
+		},
+		{
+			description: "unsupported kind",
+			request:     &GenerateRequest{Insert: true, Datastore: "db", Tables: []*TableGenerator{{Table: "t", Rows: 1, Columns: map[string]*ColumnGenerator{"id": {Kind: "abc"}}}}},
+			hasError:    true,
+		},
+		{
+			description: "invalid ref",
+			request:     &GenerateRequest{Insert: true, Datastore: "db", Tables: []*TableGenerator{{Table: "t", Rows: 1, Columns: map[string]*ColumnGenerator{"id": {Ref: "abc"}}}}},
+			hasError:    true,
+		},
+		{
+			description: "unsafe ref",
+			request:     &GenerateRequest{Insert: true, Datastore: "db", Tables: []*TableGenerator{{Table: "t", Rows: 1, Columns: map[string]*ColumnGenerator{"id": {Ref: "users;DROP TABLE users.id"}}}}},
+			hasError:    true,
+		},
+		{
+			description: "schema ref",
+			request:     &GenerateRequest{Insert: true, Datastore: "db", Tables: []*TableGenerator{{Table: "t", Rows: 1, Columns: map[string]*ColumnGenerator{"id": {Ref: "app.users.id"}}}}},
+		},
+		{
+			description: "weights mismatch",
+			request:     &GenerateRequest{Insert: true, Datastore: "db", Tables: []*TableGenerator{{Table: "t", Rows: 1, Columns: map[string]*ColumnGenerator{"id": {Values: []interface{}{1, 2}, Weights: []float64{1}}}}}},
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		assert.Nil(t, useCase.request.Init(), useCase.description)
+		err := useCase.request.Validate()
+		assert.EqualValues(t, useCase.hasError, err != nil, useCase.description)
+	}
+}
+
+func TestOrderTables(t *testing.T) {
+	_, err := orderTables([]*TableGenerator{
+		{Table: "a", Columns: map[string]*ColumnGenerator{"b_id": {Kind: KindRef, Ref: "b.id"}}},
+		{Table: "b", Columns: map[string]*ColumnGenerator{"a_id": {Kind: KindRef, Ref: "a.id"}}},
+	})
+	assert.NotNil(t, err)
+}
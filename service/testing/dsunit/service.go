@@ -34,7 +34,8 @@ type RunSQLcriptEvent struct {
 
 type service struct {
 	*endly.AbstractService
-	Service dsunit.Service
+	Service   dsunit.Service
+	snapshots *snapshots
 }
 
 const (
@@ -138,6 +139,41 @@ const (
 		]
 	}`
 
+	dsunitGenerateExample = `{
+		"Datastore": "db1",
+		"Seed": 7,
+		"Insert": true,
+		"Tables": [
+			{
+				"Table": "users",
+				"Rows": 100,
+				"Columns": {
+					"id": {"Kind": "sequence"},
+					"email": {"Kind": "email"},
+					"status": {"Values": ["active", "blocked"], "Weights": [9, 1]}
+				}
+			},
+			{
+				"Table": "orders",
+				"Rows": 1000,
+				"Columns": {
+					"user_id": {"Ref": "users.id", "Distribution": "exponential"},
+					"amount": {"Kind": "float", "Min": 5, "Max": 500, "Distribution": "normal"}
+				}
+			}
+		]
+	}`
+
+	dsunitSnapshotExample = `{
+		"Datastore": "db1",
+		"Name": "baseline"
+	}`
+
+	dsunitRestoreExample = `{
+		"Datastore": "db1",
+		"Name": "baseline"
+	}`
+
 	dsunitServiceStaticDataPrepareExample = `{
     "Datastore": "db1",
     "URL": "datastore/db1/dictionary"
@@ -636,6 +672,81 @@ func (s *service) registerRoutes() {
 		},
 	})
 
+	s.Register(&endly.Route{
+		Action: "generate",
+		RequestInfo: &endly.ActionInfo{
+			Description: "generate related tables data, insert it or write it as dataset files",
+			Examples: []*endly.UseCase{
+				{
+					Description: "generate",
+					Data:        dsunitGenerateExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &GenerateRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &GenerateResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*GenerateRequest); ok {
+				return s.generate(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "snapshot",
+		RequestInfo: &endly.ActionInfo{
+			Description: "capture datastore tables state under a snapshot name",
+			Examples: []*endly.UseCase{
+				{
+					Description: "snapshot",
+					Data:        dsunitSnapshotExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &SnapshotRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &SnapshotResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*SnapshotRequest); ok {
+				return s.snapshot(req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "restore",
+		RequestInfo: &endly.ActionInfo{
+			Description: "restore datastore tables state from a snapshot",
+			Examples: []*endly.UseCase{
+				{
+					Description: "restore",
+					Data:        dsunitRestoreExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &RestoreRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RestoreResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RestoreRequest); ok {
+				return s.restore(req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.Register(&endly.Route{
 		Action: "sequence",
 		RequestInfo: &endly.ActionInfo{
@@ -702,6 +813,7 @@ func New() endly.Service {
 	var result = &service{
 		AbstractService: endly.NewAbstractService(ServiceID),
 		Service:         dsunit.New(),
+		snapshots:       &snapshots{registry: make(map[string]*snapshot)},
 	}
 	result.AbstractService.Service = result
 	result.registerRoutes()
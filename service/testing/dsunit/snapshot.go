@@ -0,0 +1,393 @@
+package dsunit
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	durl "github.com/viant/dsunit/url"
+	"github.com/viant/toolbox"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Snapshot methods
+const (
+	//SnapshotMethodFile copies sqlite3 database file
+	SnapshotMethodFile = "file"
+	//SnapshotMethodTable copies each table into endly_snap_<name>_<table> table on the datastore side
+	SnapshotMethodTable = "table"
+	//SnapshotMethodDump keeps tables records in memory, restores them with prepare
+	SnapshotMethodDump = "dump"
+)
+
+const (
+	defaultSnapshotName = "baseline"
+	snapshotTablePrefix = "endly_snap_"
+)
+
+// sqlDrivers lists drivers supporting DROP TABLE IF EXISTS, CREATE TABLE AS SELECT and INSERT INTO ... SELECT
+var sqlDrivers = map[string]bool{
+	"sqlite3":  true,
+	"mysql":    true,
+	"postgres": true,
+	"pgx":      true,
+	"vertica":  true,
+}
+
+const postgresForeignKeySQL = `SELECT tc.table_name AS child_table, ccu.table_name AS parent_table
+FROM information_schema.table_constraints tc
+JOIN information_schema.constraint_column_usage ccu ON ccu.constraint_name = tc.constraint_name AND ccu.constraint_schema = tc.constraint_schema
+WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = current_schema()`
+
+// foreignKeySQLs lists driver specific queries returning child_table and parent_table of current datastore foreign keys
+var foreignKeySQLs = map[string]string{
+	"postgres": postgresForeignKeySQL,
+	"pgx":      postgresForeignKeySQL,
+	"mysql": `SELECT TABLE_NAME AS child_table, REFERENCED_TABLE_NAME AS parent_table
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IS NOT NULL`,
+}
+
+func validateSnapshotName(name string) error {
+	return validateIdentifier("snapshot name", name)
+}
+
+// snapshot represents captured datastore state
+type snapshot struct {
+	method  string
+	tables  []string
+	file    string
+	records map[string][]map[string]interface{}
+}
+
+// snapshots represents datastore snapshots keyed by datastore and snapshot name
+type snapshots struct {
+	mux      sync.Mutex
+	registry map[string]*snapshot
+}
+
+func (s *snapshots) get(datastore, name string) *snapshot {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.registry[datastore+"/"+name]
+}
+
+func (s *snapshots) put(datastore, name string, value *snapshot) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if value == nil {
+		delete(s.registry, datastore+"/"+name)
+		return
+	}
+	s.registry[datastore+"/"+name] = value
+}
+
+func snapshotTable(name, table string) string {
+	return snapshotTablePrefix + name + "_" + table
+}
+
+// sqliteFile returns sqlite3 database file, or empty string for in memory database
+func sqliteFile(config *dsc.Config) (string, error) {
+	dsn, err := config.DsnDescriptor()
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory") {
+		return "", nil
+	}
+	dsn = strings.TrimPrefix(dsn, "file:")
+	if index := strings.Index(dsn, "?"); index != -1 {
+		dsn = dsn[:index]
+	}
+	return dsn, nil
+}
+
+func copyFile(source, dest string) error {
+	reader, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	writer, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(writer, reader); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// snapshotMethod returns requested or driver specific snapshot method
+func snapshotMethod(request *SnapshotRequest, config *dsc.Config, file string) (string, error) {
+	if request.Method == SnapshotMethodFile && (config.DriverName != "sqlite3" || file == "") {
+		return "", fmt.Errorf("file snapshot is only supported by file based sqlite3 datastore")
+	}
+	if request.Method != "" {
+		return request.Method, nil
+	}
+	switch {
+	case config.DriverName == "sqlite3" && file != "" && len(request.Tables) == 0:
+		return SnapshotMethodFile, nil
+	case sqlDrivers[config.DriverName]:
+		return SnapshotMethodTable, nil
+	}
+	return SnapshotMethodDump, nil
+}
+
+// snapshotTables returns requested or all datastore tables, tables with snapshot table prefix are excluded from all datastore tables
+func snapshotTables(manager dsc.Manager, tables []string) ([]string, error) {
+	if len(tables) > 0 {
+		return tables, nil
+	}
+	dialect := dsc.GetDatastoreDialect(manager.Config().DriverName)
+	datastore, err := dialect.GetCurrentDatastore(manager)
+	if err != nil {
+		return nil, err
+	}
+	if tables, err = dialect.GetTables(manager, datastore); err != nil {
+		return nil, err
+	}
+	var result = make([]string, 0, len(tables))
+	for _, table := range tables {
+		if !strings.HasPrefix(table, snapshotTablePrefix) {
+			result = append(result, table)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// foreignKeys returns tables referenced by foreign keys of supplied tables, keyed by referencing table
+func foreignKeys(manager dsc.Manager, tables []string) (map[string][]string, error) {
+	var result = make(map[string][]string)
+	var records = make([]map[string]interface{}, 0)
+	driver := manager.Config().DriverName
+	if SQL, ok := foreignKeySQLs[driver]; ok {
+		if err := manager.ReadAll(&records, SQL, nil, nil); err != nil {
+			return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+		}
+	} else if driver == "sqlite3" {
+		for _, table := range tables {
+			var tableRecords = make([]map[string]interface{}, 0)
+			SQL := fmt.Sprintf(`SELECT '%v' AS child_table, "table" AS parent_table FROM pragma_foreign_key_list('%v')`, table, table)
+			if err := manager.ReadAll(&tableRecords, SQL, nil, nil); err != nil {
+				return nil, fmt.Errorf("failed to read %v foreign keys: %w", table, err)
+			}
+			records = append(records, tableRecords...)
+		}
+	}
+	for _, record := range records {
+		child, parent := toolbox.AsString(record["child_table"]), toolbox.AsString(record["parent_table"])
+		result[child] = append(result[child], parent)
+	}
+	return result, nil
+}
+
+// orderByForeignKeys orders tables so that referenced tables precede referencing ones, supplied order is kept otherwise,
+// thus records can be deleted in reverse and inserted in the returned order
+func orderByForeignKeys(tables []string, references map[string][]string) []string {
+	var listed = make(map[string]bool)
+	for _, table := range tables {
+		listed[table] = true
+	}
+	var result = make([]string, 0, len(tables))
+	var visited = make(map[string]bool)
+	var visit func(table string)
+	visit = func(table string) {
+		if visited[table] {
+			return
+		}
+		visited[table] = true //marked before references are visited, so that cyclic references do not loop
+		for _, parent := range references[table] {
+			if listed[parent] {
+				visit(parent)
+			}
+		}
+		result = append(result, table)
+	}
+	for _, table := range tables {
+		visit(table)
+	}
+	return result
+}
+
+// executeOnConnection runs SQLs on the same connection, mysql foreign key checks are disabled for the time of execution
+func executeOnConnection(manager dsc.Manager, SQLs []string) error {
+	if manager.Config().DriverName == "mysql" {
+		SQLs = append(append([]string{"SET FOREIGN_KEY_CHECKS = 0"}, SQLs...), "SET FOREIGN_KEY_CHECKS = 1")
+	}
+	connection, err := manager.ConnectionProvider().Get()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	_, err = manager.ExecuteAllOnConnection(connection, SQLs)
+	return err
+}
+
+func (s *service) manager(datastore string) (dsc.Manager, error) {
+	manager := s.Service.Registry().Get(datastore)
+	if manager == nil {
+		return nil, fmt.Errorf("datastore %v was not registered", datastore)
+	}
+	return manager, nil
+}
+
+// snapshot captures datastore tables state
+func (s *service) snapshot(request *SnapshotRequest) (*SnapshotResponse, error) {
+	manager, err := s.manager(request.Datastore)
+	if err != nil {
+		return nil, err
+	}
+	config := manager.Config()
+	var file string
+	if config.DriverName == "sqlite3" {
+		if file, err = sqliteFile(config); err != nil {
+			return nil, err
+		}
+	}
+	method, err := snapshotMethod(request, config, file)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := snapshotTables(manager, request.Tables)
+	if err != nil {
+		return nil, err
+	}
+	references, err := foreignKeys(manager, tables)
+	if err != nil {
+		return nil, err
+	}
+	tables = orderByForeignKeys(tables, references)
+	var result = &snapshot{method: method, tables: tables}
+	switch method {
+	case SnapshotMethodFile:
+		result.file = file + "." + request.Name + ".snapshot"
+		//closing pooled connections flushes journal, so that database file is consistent
+		if err = manager.ConnectionProvider().Close(); err != nil {
+			return nil, err
+		}
+		if err = copyFile(file, result.file); err != nil {
+			return nil, fmt.Errorf("failed to copy %v: %w", file, err)
+		}
+	case SnapshotMethodTable:
+		var SQLs = make([]string, 0)
+		for _, table := range tables {
+			target := snapshotTable(request.Name, table)
+			SQLs = append(SQLs, "DROP TABLE IF EXISTS "+target, fmt.Sprintf("CREATE TABLE %v AS SELECT * FROM %v", target, table))
+		}
+		if err = executeOnConnection(manager, SQLs); err != nil {
+			return nil, fmt.Errorf("failed to create snapshot tables: %w", err)
+		}
+	case SnapshotMethodDump:
+		result.records = make(map[string][]map[string]interface{})
+		for _, table := range tables {
+			var records = make([]map[string]interface{}, 0)
+			if err = manager.ReadAll(&records, "SELECT * FROM "+table, nil, nil); err != nil {
+				return nil, fmt.Errorf("failed to read %v: %w", table, err)
+			}
+			result.records[table] = records
+		}
+	}
+	s.snapshots.put(request.Datastore, request.Name, result)
+	return &SnapshotResponse{Name: request.Name, Method: method, Tables: tables}, nil
+}
+
+// restore restores datastore tables state from a snapshot
+func (s *service) restore(request *RestoreRequest) (*RestoreResponse, error) {
+	manager, err := s.manager(request.Datastore)
+	if err != nil {
+		return nil, err
+	}
+	captured := s.snapshots.get(request.Datastore, request.Name)
+	if captured == nil {
+		return nil, fmt.Errorf("snapshot %v was not found for %v", request.Name, request.Datastore)
+	}
+	switch captured.method {
+	case SnapshotMethodFile:
+		err = s.restoreFile(manager, captured, request.Discard)
+	case SnapshotMethodTable:
+		err = s.restoreTables(manager, captured, request.Name, request.Discard)
+	case SnapshotMethodDump:
+		err = s.restoreRecords(request.Datastore, captured)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if request.Discard {
+		s.snapshots.put(request.Datastore, request.Name, nil)
+	}
+	return &RestoreResponse{Name: request.Name, Method: captured.method, Tables: captured.tables}, nil
+}
+
+func (s *service) restoreFile(manager dsc.Manager, captured *snapshot, discard bool) error {
+	file, err := sqliteFile(manager.Config())
+	if err != nil {
+		return err
+	}
+	if err = manager.ConnectionProvider().Close(); err != nil {
+		return err
+	}
+	for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+		if err = os.Remove(file + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err = copyFile(captured.file, file); err != nil {
+		return fmt.Errorf("failed to restore %v: %w", file, err)
+	}
+	if discard {
+		return os.Remove(captured.file)
+	}
+	return nil
+}
+
+func (s *service) restoreTables(manager dsc.Manager, captured *snapshot, name string, discard bool) error {
+	var SQLs = make([]string, 0)
+	for i := len(captured.tables) - 1; i >= 0; i-- {
+		SQLs = append(SQLs, "DELETE FROM "+captured.tables[i])
+	}
+	for _, table := range captured.tables {
+		SQLs = append(SQLs, fmt.Sprintf("INSERT INTO %v SELECT * FROM %v", table, snapshotTable(name, table)))
+	}
+	if discard {
+		for _, table := range captured.tables {
+			SQLs = append(SQLs, "DROP TABLE "+snapshotTable(name, table))
+		}
+	}
+	if err := executeOnConnection(manager, SQLs); err != nil {
+		return fmt.Errorf("failed to restore snapshot tables: %w", err)
+	}
+	return nil
+}
+
+// restoreRecords removes all tables records, then inserts captured records, dsunit prepare removes all records for an empty dataset
+func (s *service) restoreRecords(datastore string, captured *snapshot) error {
+	var empty = make([]*dsunit.Dataset, 0)
+	var datasets = make([]*dsunit.Dataset, 0)
+	for _, table := range captured.tables {
+		empty = append(empty, dsunit.NewDataset(table))
+		if records := captured.records[table]; len(records) > 0 {
+			datasets = append(datasets, dsunit.NewDataset(table, records...))
+		}
+	}
+	for _, batch := range [][]*dsunit.Dataset{empty, datasets} {
+		if len(batch) == 0 {
+			continue
+		}
+		response := s.Service.Prepare(dsunit.NewPrepareRequest(&dsunit.DatasetResource{
+			Resource:          &durl.Resource{}, //datasets are already in memory
+			DatastoreDatasets: &dsunit.DatastoreDatasets{Datastore: datastore, Datasets: batch},
+		}))
+		if err := response.Error(); err != nil {
+			return fmt.Errorf("failed to restore records: %w", err)
+		}
+	}
+	return nil
+}
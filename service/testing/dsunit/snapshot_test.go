@@ -0,0 +1,171 @@
+package dsunit
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestService_SnapshotRestore(t *testing.T) {
+	for _, method := range []string{"", SnapshotMethodTable, SnapshotMethodDump} {
+		t.Run(fmt.Sprintf("method '%v'", method), func(t *testing.T) {
+			manager := endly.New()
+			context := manager.NewContext(toolbox.NewContext())
+			defer context.Close()
+			service, err := manager.Service(ServiceID)
+			if !assert.Nil(t, err) {
+				return
+			}
+			dbFile := path.Join(t.TempDir(), "snap.db")
+			config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{"url": dbFile})
+			if !assert.Nil(t, err) {
+				return
+			}
+			var run = func(request interface{}) *endly.ServiceResponse {
+				response := service.Run(context, request)
+				assert.EqualValues(t, "", response.Error)
+				return response
+			}
+			var count = func(table string) int {
+				response := run(&QueryRequest{Datastore: "snapdb", SQL: "SELECT COUNT(*) AS cnt FROM " + table})
+				return toolbox.AsInt(response.Response.(*QueryResponse).Records[0]["cnt"])
+			}
+			run(&dsunit.RegisterRequest{Datastore: "snapdb", Config: config})
+			run(&RunSQLRequest{Datastore: "snapdb", SQL: []string{
+				"CREATE TABLE account (id INTEGER PRIMARY KEY, name VARCHAR(32))",
+				"CREATE TABLE contact (id INTEGER PRIMARY KEY, account_id INTEGER, email VARCHAR(255))",
+				"INSERT INTO account (id, name) VALUES (1, 'a1'), (2, 'a2')",
+				"INSERT INTO contact (id, account_id, email) VALUES (1, 1, 'c1@example.com')",
+			}})
+
+			response := run(&SnapshotRequest{Datastore: "snapdb", Method: method})
+			snapshotResponse, ok := response.Response.(*SnapshotResponse)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.EqualValues(t, defaultSnapshotName, snapshotResponse.Name)
+			assert.EqualValues(t, []string{"account", "contact"}, snapshotResponse.Tables)
+			if method == "" {
+				assert.EqualValues(t, SnapshotMethodFile, snapshotResponse.Method)
+			}
+
+			for i := 0; i < 2; i++ { //use case modifications followed by restore
+				run(&RunSQLRequest{Datastore: "snapdb", SQL: []string{
+					"INSERT INTO account (id, name) VALUES (3, 'a3')",
+					"DELETE FROM contact",
+					"UPDATE account SET name = 'changed' WHERE id = 1",
+				}})
+				assert.EqualValues(t, 3, count("account"))
+				assert.EqualValues(t, 0, count("contact"))
+
+				run(&RestoreRequest{Datastore: "snapdb"})
+				assert.EqualValues(t, 2, count("account"))
+				assert.EqualValues(t, 1, count("contact"))
+				response = run(&QueryRequest{Datastore: "snapdb", SQL: "SELECT name FROM account WHERE id = 1"})
+				assert.EqualValues(t, "a1", response.Response.(*QueryResponse).Records[0]["name"])
+			}
+
+			run(&RestoreRequest{Datastore: "snapdb", Discard: true})
+			if method == "" {
+				_, err = os.Stat(dbFile + "." + defaultSnapshotName + ".snapshot")
+				assert.True(t, os.IsNotExist(err))
+			}
+			response = service.Run(context, &RestoreRequest{Datastore: "snapdb"})
+			assert.NotEqual(t, "", response.Error, "discarded snapshot should not be restored")
+		})
+	}
+}
+
+func TestSnapshotRequest_Validate(t *testing.T) {
+	request := &SnapshotRequest{Datastore: "db"}
+	assert.Nil(t, request.Init())
+	assert.Nil(t, request.Validate())
+	assert.EqualValues(t, defaultSnapshotName, request.Name)
+	assert.NotNil(t, (&SnapshotRequest{Datastore: "db", Name: "a-b"}).Validate())
+	assert.NotNil(t, (&SnapshotRequest{Datastore: "db", Name: "a", Method: "abc"}).Validate())
+	assert.NotNil(t, (&SnapshotRequest{Datastore: "db", Name: "a", Tables: []string{"users; DROP TABLE users"}}).Validate())
+	assert.NotNil(t, (&RestoreRequest{Name: "a"}).Validate())
+}
+
+func TestService_SnapshotRestoreForeignKeys(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	service, err := manager.Service(ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{"url": path.Join(t.TempDir(), "fk.db") + "?_foreign_keys=1"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var run = func(request interface{}) *endly.ServiceResponse {
+		response := service.Run(context, request)
+		assert.EqualValues(t, "", response.Error)
+		return response
+	}
+	run(&dsunit.RegisterRequest{Datastore: "fkdb", Config: config})
+	run(&RunSQLRequest{Datastore: "fkdb", SQL: []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(32))",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users(id))",
+		"INSERT INTO users (id, name) VALUES (1, 'u1')",
+		"INSERT INTO orders (id, user_id) VALUES (1, 1)",
+	}})
+	response := service.Run(context, &RunSQLRequest{Datastore: "fkdb", SQL: []string{"INSERT INTO orders (id, user_id) VALUES (2, 100)"}})
+	if !assert.NotEqual(t, "", response.Error, "foreign keys should be enforced") {
+		return
+	}
+
+	response = run(&SnapshotRequest{Datastore: "fkdb", Method: SnapshotMethodTable})
+	snapshotResponse, ok := response.Response.(*SnapshotResponse)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.EqualValues(t, []string{"users", "orders"}, snapshotResponse.Tables, "referenced table should precede referencing one")
+	run(&RunSQLRequest{Datastore: "fkdb", SQL: []string{
+		"INSERT INTO users (id, name) VALUES (2, 'u2')",
+		"INSERT INTO orders (id, user_id) VALUES (2, 2)",
+	}})
+	run(&RestoreRequest{Datastore: "fkdb", Discard: true})
+	response = run(&QueryRequest{Datastore: "fkdb", SQL: "SELECT (SELECT COUNT(*) FROM users) AS users, (SELECT COUNT(*) FROM orders) AS orders"})
+	if records := response.Response.(*QueryResponse).Records; assert.Len(t, records, 1) {
+		assert.EqualValues(t, 1, records[0]["users"])
+		assert.EqualValues(t, 1, records[0]["orders"])
+	}
+}
+
+func TestOrderByForeignKeys(t *testing.T) {
+	var useCases = []struct {
+		description string
+		tables      []string
+		references  map[string][]string
+		expect      []string
+	}{
+		{
+			description: "no references",
+			tables:      []string{"a", "b"},
+			expect:      []string{"a", "b"},
+		},
+		{
+			description: "parent listed after child",
+			tables:      []string{"contact", "orders", "users"},
+			references:  map[string][]string{"orders": {"users"}, "contact": {"users"}},
+			expect:      []string{"users", "contact", "orders"},
+		},
+		{
+			description: "unlisted and cyclic references",
+			tables:      []string{"a", "b"},
+			references:  map[string][]string{"a": {"b", "x"}, "b": {"a", "b"}},
+			expect:      []string{"b", "a"},
+		},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, orderByForeignKeys(useCase.tables, useCase.references), useCase.description)
+	}
+}
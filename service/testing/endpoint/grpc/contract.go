@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/endly/internal/descriptor"
+)
+
+// ListenRequest represents gRPC endpoint listen request
+type ListenRequest struct {
+	Port              int `required:"true"`
+	descriptor.Source `description:"proto file or descriptor set defining served services"`
+	Stubs             []*Stub `description:"canned method responses, the first matching stub is used"`
+}
+
+// Validate checks if request is valid
+func (r *ListenRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	if r.Source.IsEmpty() {
+		return errors.New("protoFile and descriptorSet were empty")
+	}
+	for i, stub := range r.Stubs {
+		if stub.Method == "" {
+			return fmt.Errorf("stubs[%d].method was empty", i)
+		}
+	}
+	return nil
+}
+
+// ListenResponse represents gRPC endpoint listen response
+type ListenResponse struct {
+	Services []string
+	Stubs    []string
+}
+
+// ShutdownRequest represents gRPC endpoint shutdown request
+type ShutdownRequest struct {
+	Port int
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/viant/endly/internal/descriptor"
+	"github.com/viant/toolbox/data"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// Server represents gRPC endpoint serving stubbed methods
+type Server struct {
+	*grpc.Server
+	methods  map[string]*desc.MethodDescriptor
+	services map[string]grpc.ServiceInfo
+	stubs    *Stubs
+}
+
+// GetServiceInfo returns served services, it is used by server reflection
+func (s *Server) GetServiceInfo() map[string]grpc.ServiceInfo {
+	return s.services
+}
+
+// Services returns served service names
+func (s *Server) Services() []string {
+	var result = make([]string, 0, len(s.services))
+	for name := range s.services {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// handle serves unary and server streaming stubbed methods
+func (s *Server) handle(_ interface{}, stream grpc.ServerStream) error {
+	methodName, _ := grpc.MethodFromServerStream(stream)
+	method, ok := s.methods[methodName]
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %v", methodName)
+	}
+	if method.IsClientStreaming() {
+		return status.Errorf(codes.Unimplemented, "client streaming method %v is not supported", methodName)
+	}
+	message, _ := descriptor.NewMessage(method.GetInputType(), nil)
+	if err := stream.RecvMsg(message); err != nil && err != io.EOF {
+		return err
+	}
+	requestData, err := descriptor.AsData(message)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to decode request: %v", err)
+	}
+	request := data.Map{}
+	if aMap, ok := requestData.(map[string]interface{}); ok {
+		request = aMap
+	}
+	incoming, _ := metadata.FromIncomingContext(stream.Context())
+	stub := s.stubs.Match(methodName, request, incoming)
+	if stub == nil {
+		return status.Errorf(codes.Unimplemented, "no stub matched %v", methodName)
+	}
+	if stub.DelayMs > 0 {
+		time.Sleep(time.Duration(stub.DelayMs) * time.Millisecond)
+	}
+	templateState := data.Map{StubRequestKey: request}
+	var responses = stub.Responses
+	if !method.IsServerStreaming() || len(responses) == 0 {
+		responses = nil
+		if stub.Response != nil || (stub.status == codes.OK && !method.IsServerStreaming()) {
+			responses = []interface{}{stub.Response}
+		}
+	}
+	for _, item := range responses {
+		response, err := descriptor.NewMessage(method.GetOutputType(), templateState.Expand(item))
+		if err != nil {
+			return status.Errorf(codes.Internal, "invalid stub %v response: %v", stub.ID, err)
+		}
+		if err = stream.SendMsg(response); err != nil {
+			return err
+		}
+	}
+	if stub.status != codes.OK {
+		return status.Error(stub.status, templateState.ExpandAsText(stub.Message))
+	}
+	return nil
+}
+
+// NewServer creates gRPC server for supplied descriptors
+func NewServer(files []*desc.FileDescriptor, stubs *Stubs) (*Server, error) {
+	resolver, err := descriptor.NewResolver(files)
+	if err != nil {
+		return nil, err
+	}
+	result := &Server{
+		methods:  make(map[string]*desc.MethodDescriptor),
+		services: make(map[string]grpc.ServiceInfo),
+		stubs:    stubs,
+	}
+	for _, file := range files {
+		for _, service := range file.GetServices() {
+			info := grpc.ServiceInfo{Metadata: file.GetName()}
+			for _, method := range service.GetMethods() {
+				result.methods[fmt.Sprintf("/%v/%v", service.GetFullyQualifiedName(), method.GetName())] = method
+				info.Methods = append(info.Methods, grpc.MethodInfo{Name: method.GetName(), IsClientStream: method.IsClientStreaming(), IsServerStream: method.IsServerStreaming()})
+			}
+			result.services[service.GetFullyQualifiedName()] = info
+		}
+	}
+	result.Server = grpc.NewServer(grpc.UnknownServiceHandler(result.handle))
+	options := reflection.ServerOptions{Services: result, DescriptorResolver: resolver}
+	reflectionv1.RegisterServerReflectionServer(result.Server, reflection.NewServerV1(options))
+	reflectionv1alpha.RegisterServerReflectionServer(result.Server, reflection.NewServer(options))
+	return result, nil
+}
+
+// StartServer starts gRPC server on supplied port
+func StartServer(port int, files []*desc.FileDescriptor, stubs *Stubs) (*Server, error) {
+	server, err := NewServer(files, stubs)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %v: %w", port, err)
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return server, nil
+}
@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"strconv"
+)
+
+const (
+	//ServiceID represents gRPC endpoint service id.
+	ServiceID = "grpc/endpoint"
+)
+
+// service represents gRPC endpoint service, that serves canned method responses
+type service struct {
+	*endly.AbstractService
+	servers map[int]*Server
+}
+
+func (s *service) listen(context *endly.Context, request *ListenRequest) (*ListenResponse, error) {
+	if request.ProtoFile != "" && len(request.ImportPaths) == 0 {
+		request.ProtoFile = location.NewResource(request.ProtoFile).Path()
+	}
+	if request.DescriptorSet != "" {
+		request.DescriptorSet = location.NewResource(request.DescriptorSet).Path()
+	}
+	key := ServiceID + ":" + strconv.Itoa(request.Port)
+	s.Mutex().Lock()
+	defer s.Mutex().Unlock()
+	var serviceState = s.State()
+	if value := serviceState.Get(key); value != nil {
+		if response, ok := value.(*ListenResponse); ok && response != nil {
+			return response, nil
+		}
+	}
+	files, err := request.Source.Load()
+	if err != nil {
+		return nil, err
+	}
+	stubs, err := NewStubs(request.Stubs...)
+	if err != nil {
+		return nil, err
+	}
+	server, err := StartServer(request.Port, files, stubs)
+	if err != nil {
+		return nil, err
+	}
+	s.servers[request.Port] = server
+	response := &ListenResponse{
+		Services: server.Services(),
+		Stubs:    stubs.IDs(),
+	}
+	serviceState.Put(key, response)
+	return response, nil
+}
+
+func (s *service) shutdown(context *endly.Context, request *ShutdownRequest) (interface{}, error) {
+	s.Mutex().Lock()
+	defer s.Mutex().Unlock()
+	server, ok := s.servers[request.Port]
+	if !ok {
+		return nil, fmt.Errorf("endpoint at %v, not found", request.Port)
+	}
+	server.GracefulStop()
+	delete(s.servers, request.Port)
+	serviceState := s.State()
+	serviceState.Delete(ServiceID + ":" + strconv.Itoa(request.Port))
+	return &struct{}{}, nil
+}
+
+const listenExample = `{
+  "Port": 50051,
+  "ProtoFile": "proto/user.proto",
+  "Stubs": [
+    {
+      "Method": "user.UserService/GetUser",
+      "Match": {
+        "id": "1"
+      },
+      "Response": {
+        "id": "1",
+        "name": "Bob"
+      }
+    },
+    {
+      "Method": "user.UserService/GetUser",
+      "Code": "NotFound",
+      "Message": "user $request.id not found"
+    }
+  ]
+}`
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "listen",
+		RequestInfo: &endly.ActionInfo{
+			Description: "start gRPC endpoint",
+			Examples: []*endly.UseCase{
+				{
+					Description: "listen",
+					Data:        listenExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ListenRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ListenResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ListenRequest); ok {
+				return s.listen(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	},
+		&endly.Route{
+			Action: "shutdown",
+			RequestInfo: &endly.ActionInfo{
+				Description: "stop gRPC endpoint",
+			},
+			RequestProvider: func() interface{} {
+				return &ShutdownRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &struct{}{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*ShutdownRequest); ok {
+					return s.shutdown(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
+		})
+}
+
+// New creates a new gRPC endpoint service
+func New() endly.Service {
+	var result = &service{
+		servers:         make(map[int]*Server),
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
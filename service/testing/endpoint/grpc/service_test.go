@@ -0,0 +1,93 @@
+package grpc_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/descriptor"
+	endpoint "github.com/viant/endly/service/testing/endpoint/grpc"
+	runner "github.com/viant/endly/service/testing/runner/grpc"
+	"github.com/viant/toolbox"
+	"path"
+	"testing"
+)
+
+func TestService_Listen(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	protoFile := path.Join(toolbox.CallerDirectory(3), "test/user.proto")
+	serviceResponse := endly.Run(context, &endpoint.ListenRequest{
+		Port:   7731,
+		Source: descriptor.Source{ProtoFile: protoFile},
+		Stubs: []*endpoint.Stub{
+			{Method: "user.UserService/GetUser", Match: map[string]string{"id": "1"}, Metadata: map[string]string{"authorization": "~/Bearer .+/"}, Response: map[string]interface{}{"id": "$request.id", "name": "Bob"}},
+			{ID: "missing", Method: "user.UserService/GetUser", Code: "NotFound", Message: "user $request.id not found"},
+			{Method: "user.UserService/ListUsers", Responses: []interface{}{map[string]interface{}{"id": 1, "name": "Bob", "role": "$request.role"}, map[string]interface{}{"id": 2, "name": "Alice", "role": "$request.role"}}},
+		},
+	}, nil)
+	if !assert.Nil(t, serviceResponse) {
+		return
+	}
+	defer endly.Run(context, &endpoint.ShutdownRequest{Port: 7731}, nil)
+
+	var useCases = []struct {
+		description string
+		request     *runner.CallRequest
+		expectCode  string
+		expect      *runner.CallResponse
+		hasError    bool
+	}{
+		{
+			description: "unary stub with matchers",
+			request: &runner.CallRequest{
+				Method:   "user.UserService/GetUser",
+				Metadata: map[string]string{"authorization": "Bearer abc"},
+				Payload:  map[string]interface{}{"id": 1},
+			},
+			expect: &runner.CallResponse{Code: "OK", Response: map[string]interface{}{"id": float64(1), "name": "Bob"}},
+		},
+		{
+			description: "status code stub",
+			request: &runner.CallRequest{
+				Method:  "user.UserService/GetUser",
+				Payload: `{"id": 3}`,
+			},
+			hasError: true,
+		},
+		{
+			description: "status code stub with expect",
+			request: &runner.CallRequest{
+				Method:  "user.UserService.GetUser",
+				Payload: `{"id": 3}`,
+				Expect:  map[string]interface{}{"Code": "NotFound"},
+			},
+			expect: &runner.CallResponse{Code: "NotFound", Message: "user 3 not found"},
+		},
+		{
+			description: "server streaming stub",
+			request: &runner.CallRequest{
+				Method:  "user.UserService/ListUsers",
+				Payload: map[string]interface{}{"role": "admin"},
+			},
+			expect: &runner.CallResponse{Code: "OK", Responses: []interface{}{
+				map[string]interface{}{"id": float64(1), "name": "Bob", "role": "admin"},
+				map[string]interface{}{"id": float64(2), "name": "Alice", "role": "admin"},
+			}},
+		},
+	}
+	for _, useCase := range useCases {
+		useCase.request.Target = "127.0.0.1:7731"
+		response := &runner.CallResponse{}
+		err := endly.Run(context, useCase.request, response)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.expect.Code, response.Code, useCase.description)
+		assert.Equal(t, useCase.expect.Message, response.Message, useCase.description)
+		assert.EqualValues(t, useCase.expect.Response, response.Response, useCase.description)
+		assert.EqualValues(t, useCase.expect.Responses, response.Responses, useCase.description)
+	}
+}
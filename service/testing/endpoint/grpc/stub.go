@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	//StubRequestKey represents stub response template key with the received request message
+	StubRequestKey     = "request"
+	regexMatcherPrefix = "~/"
+)
+
+// Stub represents canned method response
+type Stub struct {
+	ID        string            `description:"stub ID, default: <method>"`
+	Method    string            `required:"true" description:"fully qualified method name, i.e. package.Service/Method"`
+	Match     map[string]string `description:"request message field matchers keyed by path, i.e. user.id, value is either exact or ~/regexp/"`
+	Metadata  map[string]string `description:"request metadata matchers, value is either exact or ~/regexp/"`
+	DelayMs   int               `description:"response latency"`
+	Response  interface{}       `description:"unary method response message, it can reference $request fields"`
+	Responses []interface{}     `description:"server streaming method response messages, they can reference $request fields"`
+	Code      string            `description:"status code name, i.e. NotFound, default OK"`
+	Message   string            `description:"status message for non OK code, it can reference $request fields"`
+	status    codes.Code
+	matchers  map[string]*regexp.Regexp
+	hits      int
+}
+
+func (s *Stub) init() error {
+	if s.ID == "" {
+		s.ID = s.Method
+	}
+	s.Method = "/" + strings.TrimPrefix(s.Method, "/")
+	s.status = codes.OK
+	if s.Code != "" {
+		if err := s.status.UnmarshalJSON([]byte(`"` + toCodeName(s.Code) + `"`)); err != nil {
+			return fmt.Errorf("invalid stub %v code: %v", s.ID, s.Code)
+		}
+	}
+	s.matchers = make(map[string]*regexp.Regexp)
+	for _, matchers := range []map[string]string{s.Match, s.Metadata} {
+		for key, expect := range matchers {
+			if !strings.HasPrefix(expect, regexMatcherPrefix) {
+				continue
+			}
+			expr, err := regexp.Compile(strings.Trim(expect[1:], "/"))
+			if err != nil {
+				return fmt.Errorf("invalid stub %v %v matcher: %v, %w", s.ID, key, expect, err)
+			}
+			s.matchers[expect] = expr
+		}
+	}
+	return nil
+}
+
+func (s *Stub) matchValue(expect, actual string) bool {
+	if expr, ok := s.matchers[expect]; ok {
+		return expr.MatchString(actual)
+	}
+	return expect == actual
+}
+
+// matches returns true if stub matches method, request message and metadata
+func (s *Stub) matches(method string, request data.Map, metadata map[string][]string) bool {
+	if s.Method != method {
+		return false
+	}
+	for key, expect := range s.Match {
+		value, ok := request.GetValue(key)
+		if !ok || !s.matchValue(expect, toolbox.AsString(value)) {
+			return false
+		}
+	}
+	for key, expect := range s.Metadata {
+		values := metadata[strings.ToLower(key)]
+		if len(values) == 0 || !s.matchValue(expect, values[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Stubs represents method stubs
+type Stubs struct {
+	stubs []*Stub
+	mux   sync.Mutex
+}
+
+// Match returns the first matching stub
+func (s *Stubs) Match(method string, request data.Map, metadata map[string][]string) *Stub {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, stub := range s.stubs {
+		if stub.matches(method, request, metadata) {
+			stub.hits++
+			return stub
+		}
+	}
+	return nil
+}
+
+// IDs returns stub IDs
+func (s *Stubs) IDs() []string {
+	var result = make([]string, 0, len(s.stubs))
+	for _, stub := range s.stubs {
+		result = append(result, stub.ID)
+	}
+	return result
+}
+
+// Hits returns stub hit counts keyed by stub ID
+func (s *Stubs) Hits() map[string]int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result = make(map[string]int)
+	for _, stub := range s.stubs {
+		result[stub.ID] += stub.hits
+	}
+	return result
+}
+
+// NewStubs creates method stubs
+func NewStubs(stubs ...*Stub) (*Stubs, error) {
+	for _, stub := range stubs {
+		if err := stub.init(); err != nil {
+			return nil, err
+		}
+	}
+	return &Stubs{stubs: stubs}, nil
+}
+
+// toCodeName converts code name to canonical upper snake case form, i.e. NotFound to NOT_FOUND
+func toCodeName(name string) string {
+	if strings.ToUpper(name) == name {
+		return name
+	}
+	return strings.ToUpper(toolbox.ToCaseFormat(name, toolbox.CaseUpperCamel, toolbox.CaseUpperUnderscore))
+}
@@ -16,6 +16,9 @@ func (s *service) append(context *endly.Context, req *AppendRequest) (*AppendRes
 	if req.BaseDirectory != "" {
 		req.BaseDirectory = location.NewResource(state.ExpandAsText(req.BaseDirectory)).Path()
 	}
+	if req.HAR != "" {
+		req.HAR = state.ExpandAsText(req.HAR)
+	}
 
 	trips := req.AsHTTPServerTrips(server.rotate, server.indexKeys)
 	err := trips.Init(server.requestTemplate, server.responseTemplate)
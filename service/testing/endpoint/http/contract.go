@@ -2,6 +2,7 @@ package http
 
 import (
 	"errors"
+	"github.com/viant/assertly"
 	"sync"
 )
 
@@ -12,12 +13,15 @@ type ListenRequest struct {
 	RequestTemplate  string   `description:"request file loading template, default: %02d-req.json"`
 	ResponseTemplate string   `description:"response file loading template, default: %02d-resp.json"`
 	BaseDirectory    string   `required:"true" description:"location with replay files (could be generate by https://github.com/viant/toolbox/blob/master/bridge/http_bridge_recording_util.go#L81"`
+	HAR              string   `description:"HTTP Archive (HAR) file location with trips to replay, i.e. exported by browser dev tools or endly -u -har"`
 	IndexKeys        []string `description:"recorded requests matching keys, by default: Method,URL,Body,Cookie,Content-Type"`
+	Stubs            []*Stub  `description:"inline declared responses, matched in order before recorded trips"`
 }
 
 // ListenResponse represents HTTP endpoint listen response with indexed trips
 type ListenResponse struct {
 	Trips map[string]*HTTPResponses
+	Stubs []string `json:",omitempty"`
 }
 
 func (r *ListenRequest) Init() error {
@@ -27,6 +31,11 @@ func (r *ListenRequest) Init() error {
 	if r.ResponseTemplate == "" {
 		r.ResponseTemplate = DefaultResponseTemplate
 	}
+	for _, stub := range r.Stubs {
+		if err := stub.Init(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -35,6 +44,11 @@ func (r ListenRequest) Validate() error {
 	if r.Port == 0 {
 		return errors.New("port was empty")
 	}
+	for _, stub := range r.Stubs {
+		if err := stub.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -46,12 +60,45 @@ func (r ListenRequest) AsHTTPServerTrips() *HTTPServerTrips {
 	return &HTTPServerTrips{
 		Rotate:        r.Rotate,
 		BaseDirectory: r.BaseDirectory,
+		HAR:           r.HAR,
 		Trips:         make(map[string]*HTTPResponses),
 		IndexKeys:     r.IndexKeys,
 		Mutex:         &sync.Mutex{},
 	}
 }
 
+// AssertRequest represents stub hits assert request
+type AssertRequest struct {
+	Port   int
+	Expect map[string]interface{} `required:"true" description:"expected hit count keyed by stub ID, i.e. 'GET /users/{id}': 2, or '/[1..3]/' range"`
+	Reset  bool                   `description:"flag to reset hit counters after assertion"`
+}
+
+// Validate checks if request is valid.
+func (r AssertRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	if len(r.Expect) == 0 {
+		return errors.New("expect was empty")
+	}
+	return nil
+}
+
+// AssertResponse represents stub hits assert response
+type AssertResponse struct {
+	Hits       map[string]int
+	Validation *assertly.Validation
+}
+
+// Assertion returns validation slice
+func (r *AssertResponse) Assertion() []*assertly.Validation {
+	if r == nil || r.Validation == nil {
+		return []*assertly.Validation{}
+	}
+	return []*assertly.Validation{r.Validation}
+}
+
 // ShutdownRequest represent http endpoint shutdown request
 type ShutdownRequest struct {
 	Port int
@@ -60,12 +107,13 @@ type ShutdownRequest struct {
 type AppendRequest struct {
 	Port          int
 	BaseDirectory string `required:"true" description:"location with replay files (could be generate by https://github.com/viant/toolbox/blob/master/bridge/http_bridge_recording_util.go#L81"`
+	HAR           string `description:"HTTP Archive (HAR) file location with trips to replay"`
 }
 
 // Validate checks if request is valid.
 func (r AppendRequest) Validate() error {
-	if r.BaseDirectory == "" {
-		return errors.New("baseDirectory was empty")
+	if r.BaseDirectory == "" && r.HAR == "" {
+		return errors.New("baseDirectory and HAR were empty")
 	}
 	if r.Port == 0 {
 		return errors.New("port was empty")
@@ -84,6 +132,7 @@ func (r AppendRequest) AsHTTPServerTrips(rotate bool, indexKeys []string) *HTTPS
 	return &HTTPServerTrips{
 		Rotate:        rotate,
 		BaseDirectory: r.BaseDirectory,
+		HAR:           r.HAR,
 		Trips:         make(map[string]*HTTPResponses),
 		IndexKeys:     indexKeys,
 		Mutex:         &sync.Mutex{},
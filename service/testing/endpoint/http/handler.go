@@ -15,6 +15,8 @@ type httpHandler struct {
 	running   int32
 	handler   func(writer http.ResponseWriter, request *http.Request)
 	thinkTime time.Duration
+	stubs     *Stubs
+	replay    bool //flag to fallback to recorded trips if no stub matched
 }
 
 const (
@@ -26,6 +28,15 @@ func (h *httpHandler) ServeHTTP(writer http.ResponseWriter, request *http.Reques
 		h.thinkTime = time.Duration(toolbox.AsInt(thinkTime)) * time.Millisecond
 		fmt.Printf("Updated think time: %s\n", h.thinkTime)
 	}
+	if atomic.LoadInt32(&h.running) == 1 && h.stubs.Serve(writer, request) {
+		return
+	}
+	if !h.replay {
+		var errorMessage = fmt.Sprintf("no stub matched: %v %v, available: \n%v", request.Method, request.URL, strings.Join(h.stubs.IDs(), ",\n"))
+		fmt.Println(errorMessage)
+		http.Error(writer, errorMessage, http.StatusNotFound)
+		return
+	}
 	h.handler(writer, request)
 }
 
@@ -3,17 +3,72 @@ package http
 import (
 	"fmt"
 	"github.com/satori/go.uuid"
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/har"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/bridge"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
 )
 
 // StartRecorder starts HTTP recorded for supplied URLs
 func StartRecorder(targetURLs ...string) error {
+	UUID, err := uuid.NewV1()
+	if err != nil {
+		return err
+	}
+	currentDirectory, _ := os.Getwd()
+	var outputDirectory = path.Join(currentDirectory, fmt.Sprintf("http_recording-%v", UUID.String()))
+	return startRecorder(targetURLs, func(port string, routes []*bridge.HttpBridgeProxyRoute) (*bridge.HttpBridge, error) {
+		log.Printf("capturing HTTP trafic to %v", outputDirectory)
+		return bridge.StartRecordingBridge(port, outputDirectory, routes...)
+	})
+}
+
+// StartHARRecorder starts HTTP recorder for supplied URLs capturing trips into HTTP Archive (HAR) file
+func StartHARRecorder(filename string, targetURLs ...string) error {
+	recorder := har.NewRecorder(filename, endly.AppName, endly.GetVersion())
+	defer closeHARRecorder(recorder)
+	go func() { //recording runs until the process is interrupted
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		closeHARRecorder(recorder)
+		os.Exit(0)
+	}()
+	return startRecorder(targetURLs, func(port string, routes []*bridge.HttpBridgeProxyRoute) (*bridge.HttpBridge, error) {
+		log.Printf("capturing HTTP trafic to %v", filename)
+		for _, route := range routes {
+			route.Listener = recorder.Record
+		}
+		config := &bridge.HttpBridgeConfig{
+			Endpoint: &bridge.HttpBridgeEndpointConfig{Port: port},
+			Proxy:    &bridge.HttpBridgeProxyConfig{BufferPoolSize: 2, BufferSize: 8 * 1024},
+			Routes:   routes,
+		}
+		return bridge.NewHttpBridge(config, func(proxyConfig *bridge.HttpBridgeProxyConfig, route *bridge.HttpBridgeProxyRoute) (http.Handler, error) {
+			handler, err := bridge.NewProxyRecordingHandler(proxyConfig, route)
+			if err != nil {
+				return nil, err
+			}
+			return recorder.Handler(handler), nil
+		})
+	})
+}
+
+func closeHARRecorder(recorder *har.Recorder) {
+	if err := recorder.Close(); err != nil {
+		log.Printf("failed to write HAR: %v", err)
+	}
+}
+
+func startRecorder(targetURLs []string, newBridge func(port string, routes []*bridge.HttpBridgeProxyRoute) (*bridge.HttpBridge, error)) error {
 	if len(targetURLs) == 0 {
 		return fmt.Errorf("target URLs were empty")
 	}
@@ -24,15 +79,6 @@ func StartRecorder(targetURLs ...string) error {
 	}
 	port := URL.Port()
 	isSecure := strings.HasPrefix(targetURL, "https:")
-
-	UUID, err := uuid.NewV1()
-	if err != nil {
-		return err
-	}
-	currentDirectory, _ := os.Getwd()
-
-	var outputDirectory = path.Join(currentDirectory, fmt.Sprintf("http_recording-%v", UUID.String()))
-	log.Printf("capturing HTTP trafic to %v", outputDirectory)
 	if port == "" {
 		if isSecure {
 			port = "443"
@@ -58,7 +104,10 @@ func StartRecorder(targetURLs ...string) error {
 				TargetURL: URL,
 			})
 	}
-	recorderBridge, err := bridge.StartRecordingBridge(port, outputDirectory, routes...)
+	recorderBridge, err := newBridge(port, routes)
+	if err != nil {
+		return err
+	}
 	if isSecure {
 		var serverCert = "server.crt"
 		var serverKey = "server.key"
@@ -41,6 +41,11 @@ func (s *Server) Append(trips *HTTPServerTrips) {
 
 // StartServer starts http request, the server has ability to replay recorded  HTTP trips with https://github.com/viant/toolbox/blob/master/bridge/http_bridge_recording_util.go#L82
 func StartServer(port int, trips *HTTPServerTrips, reqTemplate, respTemplate string) (*Server, error) {
+	return startServer(port, trips, NewStubs(), reqTemplate, respTemplate)
+}
+
+// startServer starts http server responding with matching stubs first, recorded trips are replayed only if base directory or HAR was specified or there are no stubs
+func startServer(port int, trips *HTTPServerTrips, stubs *Stubs, reqTemplate, respTemplate string) (*Server, error) {
 	err := trips.Init(reqTemplate, respTemplate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start http server :%v, %v", port, err)
@@ -48,6 +53,8 @@ func StartServer(port int, trips *HTTPServerTrips, reqTemplate, respTemplate str
 
 	var httpHandler = &httpHandler{
 		running: 1,
+		stubs:   stubs,
+		replay:  trips.BaseDirectory != "" || trips.HAR != "" || stubs.Len() == 0,
 	}
 
 	server := &Server{
@@ -3,6 +3,7 @@ package http
 import (
 	"fmt"
 	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
 	"github.com/viant/endly/model/location"
 	"strconv"
 )
@@ -34,6 +35,9 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 	if request.BaseDirectory != "" {
 		request.BaseDirectory = location.NewResource(state.ExpandAsText(request.BaseDirectory)).Path()
 	}
+	if request.HAR != "" {
+		request.HAR = state.ExpandAsText(request.HAR)
+	}
 	key := ServiceID + ":" + strconv.Itoa(request.Port)
 	s.Mutex().Lock()
 	defer s.Mutex().Unlock()
@@ -46,8 +50,8 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 		}
 	}
 	trips := request.AsHTTPServerTrips()
-
-	server, err := StartServer(request.Port, trips, request.RequestTemplate, request.ResponseTemplate)
+	stubs := NewStubs(request.Stubs...)
+	server, err := startServer(request.Port, trips, stubs, request.RequestTemplate, request.ResponseTemplate)
 	if err != nil {
 		return nil, err
 	}
@@ -55,11 +59,35 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 	s.servers[request.Port] = server
 	response = &ListenResponse{
 		Trips: trips.Trips,
+		Stubs: stubs.IDs(),
 	}
 	serviceState.Put(key, response)
 	return response, nil
 }
 
+func (s *service) assert(context *endly.Context, request *AssertRequest) (*AssertResponse, error) {
+	s.Mutex().Lock()
+	server, ok := s.servers[request.Port]
+	s.Mutex().Unlock()
+	if !ok {
+		return nil, fmt.Errorf("ednpoint at %v, not found", request.Port)
+	}
+	stubs := server.httpHandler.stubs
+	var response = &AssertResponse{Hits: stubs.Hits()}
+	var actual = make(map[string]interface{})
+	for ID := range request.Expect {
+		actual[ID] = response.Hits[ID]
+	}
+	var err error
+	if response.Validation, err = criteria.Assert(context, fmt.Sprintf("stubs(%v)", request.Port), request.Expect, actual); err != nil {
+		return nil, err
+	}
+	if request.Reset {
+		stubs.Reset()
+	}
+	return response, nil
+}
+
 func (s *service) registerRoutes() {
 	s.Register(&endly.Route{
 		Action: "listen",
@@ -97,6 +125,24 @@ func (s *service) registerRoutes() {
 				return nil, fmt.Errorf("unsupported request type: %T", request)
 			},
 		},
+		&endly.Route{
+			Action: "assert",
+			RequestInfo: &endly.ActionInfo{
+				Description: "verify stub hit counts",
+			},
+			RequestProvider: func() interface{} {
+				return &AssertRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &AssertResponse{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*AssertRequest); ok {
+					return s.assert(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
+		},
 		&endly.Route{
 			Action: "shutdown",
 			RequestInfo: &endly.ActionInfo{
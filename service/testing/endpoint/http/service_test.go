@@ -5,6 +5,7 @@ import (
 	"github.com/viant/endly"
 	endpoint "github.com/viant/endly/service/testing/endpoint/http"
 	"github.com/viant/toolbox"
+	"io"
 	"net/http"
 	"path"
 	"strings"
@@ -68,3 +69,144 @@ func TestHTTPEndpointService_Run_WithError(t *testing.T) {
 	}
 
 }
+
+func TestHTTPEndpointService_Stubs(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	service, _ := context.Service(endpoint.ServiceID)
+
+	response := service.Run(context, &endpoint.ListenRequest{
+		Port: 7719,
+		Stubs: []*endpoint.Stub{
+			{
+				ID:     "user",
+				Method: "get",
+				Path:   "/users/{id}",
+				Header: map[string]string{"Authorization": "~/^Bearer .+/"},
+				Response: &endpoint.StubResponse{
+					Header: map[string]string{"Content-Type": "application/json"},
+					Body:   map[string]interface{}{"id": "$request.params.id", "page": "$request.query.page"},
+				},
+			},
+			{
+				ID:       "order",
+				Method:   "POST",
+				Path:     "/orders/**",
+				JSONPath: map[string]string{"$.items[0].sku": "~/^A/"},
+				Response: &endpoint.StubResponse{Code: 201, Body: "created ${request.json.items[0].sku}"},
+			},
+			{
+				ID:    "unavailable",
+				Path:  "/slow",
+				Fault: &endpoint.StubFault{Code: 503},
+			},
+			{
+				ID:    "dropped",
+				Path:  "/drop",
+				Fault: &endpoint.StubFault{Drop: true},
+			},
+		},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	var useCases = []struct {
+		description string
+		method      string
+		URL         string
+		header      map[string]string
+		body        string
+		expectCode  int
+		expectBody  string
+		hasError    bool
+	}{
+		{description: "path param and query template", method: "GET", URL: "/users/12?page=3", header: map[string]string{"Authorization": "Bearer x"}, expectCode: 200, expectBody: `{"id":"12","page":"3"}`},
+		{description: "header mismatch", method: "GET", URL: "/users/12", expectCode: 404},
+		{description: "json path matcher", method: "POST", URL: "/orders/v1/new", body: `{"items":[{"sku":"A1"}]}`, expectCode: 201, expectBody: "created A1"},
+		{description: "json path mismatch", method: "POST", URL: "/orders/v1/new", body: `{"items":[{"sku":"B1"}]}`, expectCode: 404},
+		{description: "fault status", method: "GET", URL: "/slow", expectCode: 503},
+		{description: "dropped connection", method: "GET", URL: "/drop", hasError: true},
+	}
+	for _, useCase := range useCases {
+		request, _ := http.NewRequest(useCase.method, "http://127.0.0.1:7719"+useCase.URL, strings.NewReader(useCase.body))
+		for k, v := range useCase.header {
+			request.Header.Set(k, v)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		body, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		assert.Equal(t, useCase.expectCode, response.StatusCode, useCase.description)
+		if useCase.expectBody != "" {
+			assert.Equal(t, useCase.expectBody, string(body), useCase.description)
+		}
+	}
+
+	response = service.Run(context, &endpoint.AssertRequest{
+		Port:   7719,
+		Expect: map[string]interface{}{"user": 1, "order": 1, "unavailable": "/[1..3]/"},
+		Reset:  true,
+	})
+	if assert.Equal(t, "", response.Error) {
+		assertResponse := response.Response.(*endpoint.AssertResponse)
+		assert.Equal(t, 0, assertResponse.Validation.FailedCount, assertResponse.Validation.Report())
+		assert.True(t, assertResponse.Hits["dropped"] >= 1) //client retries idempotent request on dropped connection
+	}
+	response = service.Run(context, &endpoint.AssertRequest{Port: 7719, Expect: map[string]interface{}{"user": 1}})
+	if assert.Equal(t, "", response.Error) {
+		assert.Equal(t, 1, response.Response.(*endpoint.AssertResponse).Validation.FailedCount)
+	}
+}
+
+func TestHTTPEndpointService_HAR(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	service, _ := context.Service(endpoint.ServiceID)
+
+	response := service.Run(context, &endpoint.ListenRequest{
+		Port:      7720,
+		HAR:       path.Join(parent, "test", "har", "trips.har"),
+		IndexKeys: []string{endpoint.MethodKey, endpoint.URLKey, endpoint.BodyKey},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	listenResponse, ok := response.Response.(*endpoint.ListenResponse)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, 2, len(listenResponse.Trips))
+
+	var useCases = []struct {
+		description string
+		method      string
+		URL         string
+		body        string
+		expectCode  int
+		expectBody  string
+	}{
+		{description: "get replay", method: "GET", URL: "http://127.0.0.1:7720/users/1", expectCode: 200, expectBody: `{"id":1,"name":"Alice"}`},
+		{description: "post with base64 content", method: "POST", URL: "http://127.0.0.1:7720/users", body: `{"name":"Bob"}`, expectCode: 201, expectBody: "created"},
+	}
+	for _, useCase := range useCases {
+		request, err := http.NewRequest(useCase.method, useCase.URL, strings.NewReader(useCase.body))
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		response, err := http.DefaultClient.Do(request)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		body, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		assert.Equal(t, useCase.expectCode, response.StatusCode, useCase.description)
+		assert.Equal(t, useCase.expectBody, string(body), useCase.description)
+	}
+}
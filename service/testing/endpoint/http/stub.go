@@ -0,0 +1,458 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+const (
+	//StubRequestKey represents stub response template key with the matched request
+	StubRequestKey     = "request"
+	regexMatcherPrefix = "~/"
+	anySegment         = "*"
+	anySuffix          = "**"
+)
+
+// Stub represents inline declared endpoint response
+type Stub struct {
+	ID       string            `description:"stub ID used by assert action, default: <method> <path>"`
+	Method   string            `description:"HTTP method, any if empty"`
+	Path     string            `required:"true" description:"path pattern, * matches a segment, ** the remaining path, {name} captures a segment as request.params.name, ~/regexp/ matches the whole path"`
+	Header   map[string]string `description:"header matchers, value is either exact or ~/regexp/"`
+	Query    map[string]string `description:"query parameter matchers, value is either exact or ~/regexp/"`
+	Body     string            `description:"body matcher, either exact or ~/regexp/"`
+	JSONPath map[string]string `description:"JSON body matchers keyed by path, i.e. $.user.id or $.items[0].name, value is either exact or ~/regexp/"`
+	DelayMs  int               `description:"response latency"`
+	Fault    *StubFault        `description:"optional fault injection"`
+	Response *StubResponse     `description:"response, body can reference $request.method, path, query, header, params, body and json fields"`
+	matchers []*stubMatcher
+	segments []string
+	expr     *regexp.Regexp
+	hits     int
+}
+
+// StubResponse represents stub response
+type StubResponse struct {
+	Code   int               `description:"status code, default 200"`
+	Header map[string]string `description:"response headers"`
+	Body   interface{}       `description:"response body template, non text body is JSON encoded"`
+}
+
+// StubFault represents stub fault injection
+type StubFault struct {
+	Code        int     `description:"status code returned instead of stub response"`
+	Drop        bool    `description:"flag to close connection without response"`
+	Probability float64 `description:"fault probability between 0 and 1, default 1"`
+}
+
+type stubMatcher struct {
+	source string //header, query, body or json
+	key    string
+	expect string
+	expr   *regexp.Regexp
+}
+
+func (m *stubMatcher) match(actual string) bool {
+	if m.expr != nil {
+		return m.expr.MatchString(actual)
+	}
+	return m.expect == actual
+}
+
+func newStubMatcher(source, key, expect string) (*stubMatcher, error) {
+	result := &stubMatcher{source: source, key: key, expect: expect}
+	var err error
+	if result.expr, err = compileMatcher(expect); err != nil {
+		return nil, fmt.Errorf("invalid %v %v matcher: %v, %w", source, key, expect, err)
+	}
+	return result, nil
+}
+
+// compileMatcher compiles ~/regexp/ matcher, returns nil for exact matcher
+func compileMatcher(expect string) (*regexp.Regexp, error) {
+	if !(strings.HasPrefix(expect, regexMatcherPrefix) && strings.HasSuffix(expect, "/") && len(expect) > 2) {
+		return nil, nil
+	}
+	return regexp.Compile(expect[2 : len(expect)-1])
+}
+
+// Init initialises stub matchers
+func (s *Stub) Init() error {
+	s.Method = strings.ToUpper(s.Method)
+	if s.ID == "" {
+		s.ID = strings.TrimSpace(s.Method + " " + s.Path)
+	}
+	if s.Response == nil {
+		s.Response = &StubResponse{}
+	}
+	if s.Response.Code == 0 {
+		s.Response.Code = http.StatusOK
+	}
+	if s.Fault != nil && s.Fault.Probability == 0 {
+		s.Fault.Probability = 1
+	}
+	var err error
+	if s.expr, err = compileMatcher(s.Path); err != nil {
+		return fmt.Errorf("invalid stub %v path: %w", s.ID, err)
+	}
+	if s.expr == nil {
+		s.segments = strings.Split(strings.Trim(s.Path, "/"), "/")
+	}
+	s.matchers = nil
+	for _, source := range []struct {
+		name     string
+		matchers map[string]string
+	}{{"header", s.Header}, {"query", s.Query}, {"json", s.JSONPath}} {
+		for key, expect := range source.matchers {
+			matcher, err := newStubMatcher(source.name, key, expect)
+			if err != nil {
+				return err
+			}
+			s.matchers = append(s.matchers, matcher)
+		}
+	}
+	if s.Body != "" {
+		matcher, err := newStubMatcher("body", "", s.Body)
+		if err != nil {
+			return err
+		}
+		s.matchers = append(s.matchers, matcher)
+	}
+	return nil
+}
+
+// Validate checks if stub is valid
+func (s *Stub) Validate() error {
+	if s.Path == "" {
+		return fmt.Errorf("stub %v path was empty", s.ID)
+	}
+	if s.Fault != nil && (s.Fault.Probability < 0 || s.Fault.Probability > 1) {
+		return fmt.Errorf("stub %v fault probability has to be between 0 and 1: %v", s.ID, s.Fault.Probability)
+	}
+	return nil
+}
+
+// matchPath returns path params if path matches stub pattern
+func (s *Stub) matchPath(URLPath string) (map[string]interface{}, bool) {
+	var params = make(map[string]interface{})
+	if s.expr != nil {
+		return params, s.expr.MatchString(URLPath)
+	}
+	segments := strings.Split(strings.Trim(URLPath, "/"), "/")
+	for i, pattern := range s.segments {
+		if pattern == anySuffix {
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		switch {
+		case pattern == anySegment:
+		case strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}"):
+			params[pattern[1:len(pattern)-1]] = segments[i]
+		case pattern != segments[i]:
+			return nil, false
+		}
+	}
+	return params, len(segments) == len(s.segments)
+}
+
+// match returns template request map if request matches the stub
+func (s *Stub) match(request *stubRequest) (map[string]interface{}, bool) {
+	if s.Method != "" && s.Method != request.method {
+		return nil, false
+	}
+	params, ok := s.matchPath(request.path)
+	if !ok {
+		return nil, false
+	}
+	for _, matcher := range s.matchers {
+		var actual string
+		switch matcher.source {
+		case "header":
+			actual = request.header.Get(matcher.key)
+		case "query":
+			actual = request.query.Get(matcher.key)
+		case "body":
+			actual = request.body
+		case "json":
+			value, has := jsonPathValue(request.json, matcher.key)
+			if !has {
+				return nil, false
+			}
+			actual = toolbox.AsString(value)
+		}
+		if !matcher.match(actual) {
+			return nil, false
+		}
+	}
+	return request.asMap(params), true
+}
+
+// stubRequest represents request fields used for matching and templating
+type stubRequest struct {
+	method string
+	path   string
+	URL    string
+	header http.Header
+	query  url.Values
+	body   string
+	json   interface{}
+}
+
+func (r *stubRequest) asMap(params map[string]interface{}) map[string]interface{} {
+	var query = make(map[string]interface{})
+	for key, values := range r.query {
+		query[key] = strings.Join(values, ",")
+	}
+	var header = make(map[string]interface{})
+	for key, values := range r.header {
+		header[key] = strings.Join(values, ",")
+	}
+	return map[string]interface{}{
+		"method": r.method,
+		"path":   r.path,
+		"url":    r.URL,
+		"query":  query,
+		"header": header,
+		"params": params,
+		"body":   r.body,
+		"json":   r.json,
+	}
+}
+
+func newStubRequest(request *http.Request) (*stubRequest, error) {
+	var result = &stubRequest{
+		method: request.Method,
+		path:   request.URL.Path,
+		URL:    request.URL.String(),
+		header: request.Header,
+		query:  request.URL.Query(),
+	}
+	if request.Body != nil {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body %v, %w", request.URL, err)
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body)) //body is still available for recorded trips matching
+		result.body = string(body)
+		_ = json.Unmarshal(body, &result.json)
+	}
+	return result, nil
+}
+
+// jsonPathValue returns value for simple JSON path i.e. $.items[0].name
+func jsonPathValue(source interface{}, jsonPath string) (interface{}, bool) {
+	jsonPath = strings.TrimPrefix(strings.TrimPrefix(jsonPath, "$"), ".")
+	var value = source
+	if jsonPath == "" {
+		return value, value != nil
+	}
+	for _, field := range strings.Split(jsonPath, ".") {
+		var indexes []int
+		if index := strings.Index(field, "["); index != -1 {
+			for _, item := range strings.Split(strings.TrimSuffix(field[index+1:], "]"), "][") {
+				i, err := strconv.Atoi(item)
+				if err != nil {
+					return nil, false
+				}
+				indexes = append(indexes, i)
+			}
+			field = field[:index]
+		}
+		if field != "" {
+			aMap, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = aMap[field]; !ok {
+				return nil, false
+			}
+		}
+		for _, i := range indexes {
+			slice, ok := value.([]interface{})
+			if !ok || i < 0 || i >= len(slice) {
+				return nil, false
+			}
+			value = slice[i]
+		}
+	}
+	return value, true
+}
+
+// Stubs represents endpoint stubs with hit counters
+type Stubs struct {
+	stubs []*Stub
+	mux   sync.Mutex
+}
+
+// Hits returns hit count by stub ID
+func (s *Stubs) Hits() map[string]int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result = make(map[string]int)
+	for _, stub := range s.stubs {
+		result[stub.ID] += stub.hits
+	}
+	return result
+}
+
+// Reset resets hit counters
+func (s *Stubs) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, stub := range s.stubs {
+		stub.hits = 0
+	}
+}
+
+// Add adds stubs, stubs are matched in the order they were added
+func (s *Stubs) Add(stubs ...*Stub) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.stubs = append(s.stubs, stubs...)
+}
+
+// Len returns number of stubs
+func (s *Stubs) Len() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return len(s.stubs)
+}
+
+// IDs returns sorted stub IDs
+func (s *Stubs) IDs() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result = make([]string, 0, len(s.stubs))
+	for _, stub := range s.stubs {
+		result = append(result, stub.ID)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Match returns the first stub matching request with template request map
+func (s *Stubs) Match(request *http.Request) (*Stub, map[string]interface{}, error) {
+	stubRequest, err := newStubRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, stub := range s.stubs {
+		if requestMap, ok := stub.match(stubRequest); ok {
+			stub.hits++
+			return stub, requestMap, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// Serve writes stub response, returns false if no stub matched request
+func (s *Stubs) Serve(writer http.ResponseWriter, request *http.Request) bool {
+	if s.Len() == 0 {
+		return false
+	}
+	stub, requestMap, err := s.Match(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+	if stub == nil {
+		return false
+	}
+	if stub.DelayMs > 0 {
+		time.Sleep(time.Duration(stub.DelayMs) * time.Millisecond)
+	}
+	if fault := stub.Fault; fault != nil && rand.Float64() < fault.Probability {
+		if fault.Drop {
+			dropConnection(writer)
+			return true
+		}
+		if fault.Code != 0 {
+			writer.WriteHeader(fault.Code)
+			return true
+		}
+	}
+	state := data.NewMap()
+	state.Put(StubRequestKey, requestMap)
+	for key, value := range stub.Response.Header {
+		writer.Header().Set(key, state.ExpandAsText(value))
+	}
+	body, err := stub.Response.body(state)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+	writer.WriteHeader(stub.Response.Code)
+	_, _ = writer.Write(body)
+	return true
+}
+
+func (r *StubResponse) body(state data.Map) ([]byte, error) {
+	switch body := r.Body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(state.ExpandAsText(body)), nil
+	case []byte:
+		return body, nil
+	}
+	result, err := json.Marshal(normalizeBody(state.Expand(r.Body)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode stub body: %w", err)
+	}
+	return result, nil
+}
+
+// normalizeBody converts YAML decoded maps to JSON compatible ones
+func normalizeBody(value interface{}) interface{} {
+	switch {
+	case toolbox.IsMap(value):
+		var result = make(map[string]interface{})
+		for key, item := range toolbox.AsMap(value) {
+			result[key] = normalizeBody(item)
+		}
+		return result
+	case toolbox.IsSlice(value):
+		var result = make([]interface{}, 0)
+		for _, item := range toolbox.AsSlice(value) {
+			result = append(result, normalizeBody(item))
+		}
+		return result
+	}
+	return value
+}
+
+// dropConnection closes client connection without response
+func dropConnection(writer http.ResponseWriter) {
+	hijacker, ok := writer.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	_ = conn.Close()
+}
+
+// NewStubs creates stubs
+func NewStubs(stubs ...*Stub) *Stubs {
+	return &Stubs{stubs: stubs}
+}
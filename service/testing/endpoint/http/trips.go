@@ -2,6 +2,7 @@ package http
 
 import (
 	"fmt"
+	"github.com/viant/endly/internal/har"
 	"github.com/viant/toolbox/bridge"
 	"sync"
 )
@@ -9,6 +10,7 @@ import (
 // HTTPServerTrips represents http trips
 type HTTPServerTrips struct {
 	BaseDirectory string
+	HAR           string
 	Rotate        bool
 	Trips         map[string]*HTTPResponses
 	IndexKeys     []string
@@ -16,8 +18,11 @@ type HTTPServerTrips struct {
 }
 
 func (t *HTTPServerTrips) loadTripsIfNeeded(reqTemplate string, respTemplate string) error {
+	if t.BaseDirectory == "" && t.HAR == "" {
+		return nil
+	}
+	t.Trips = make(map[string]*HTTPResponses)
 	if t.BaseDirectory != "" {
-		t.Trips = make(map[string]*HTTPResponses)
 		httpTrips, err := bridge.ReadRecordedHttpTripsWithTemplate(t.BaseDirectory, reqTemplate, respTemplate)
 		if err != nil {
 			return err
@@ -25,20 +30,43 @@ func (t *HTTPServerTrips) loadTripsIfNeeded(reqTemplate string, respTemplate str
 		if len(httpTrips) == 0 {
 			return fmt.Errorf("http capautre directory was empty %v", t.BaseDirectory)
 		}
-		for _, trip := range httpTrips {
-			key, err := buildKeyValue(t.IndexKeys, trip.Request)
-			if err != nil {
-				return fmt.Errorf("failed to build request key: %v, %v", trip.Request.URL, err)
-			}
+		if err = t.index(httpTrips); err != nil {
+			return err
+		}
+	}
+	if t.HAR != "" {
+		document, err := har.Load(t.HAR)
+		if err != nil {
+			return err
+		}
+		httpTrips, err := document.Trips()
+		if err != nil {
+			return err
+		}
+		if len(httpTrips) == 0 {
+			return fmt.Errorf("HAR entries were empty %v", t.HAR)
+		}
+		if err = t.index(httpTrips); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *HTTPServerTrips) index(httpTrips []*bridge.RecordedHttpTrip) error {
+	for _, trip := range httpTrips {
+		key, err := buildKeyValue(t.IndexKeys, trip.Request)
+		if err != nil {
+			return fmt.Errorf("failed to build request key: %v, %v", trip.Request.URL, err)
+		}
 
-			if _, has := t.Trips[key]; !has {
-				t.Trips[key] = &HTTPResponses{
-					Request:   trip.Request,
-					Responses: make([]*bridge.HttpResponse, 0),
-				}
+		if _, has := t.Trips[key]; !has {
+			t.Trips[key] = &HTTPResponses{
+				Request:   trip.Request,
+				Responses: make([]*bridge.HttpResponse, 0),
 			}
-			t.Trips[key].Responses = append(t.Trips[key].Responses, trip.Response)
 		}
+		t.Trips[key].Responses = append(t.Trips[key].Responses, trip.Response)
 	}
 	return nil
 }
@@ -23,6 +23,11 @@ func (r *AssertRequest) Init() error {
 		return nil
 	}
 	for _, expecRecords := range r.Expect {
+		if expecRecords.Match != nil && toolbox.IsSlice(expecRecords.Match) {
+			if aMap, err := toolbox.ToMap(expecRecords.Match); err == nil {
+				expecRecords.Match = aMap
+			}
+		}
 		if len(expecRecords.Records) == 0 {
 			continue
 		}
@@ -61,11 +66,18 @@ func (r *AssertRequest) Validate() error {
 
 // TypedRecord represents an expected log record.
 type TypedRecord struct {
-	TagID   string `description:"case tag id for reporting and selection"`
-	Type    string `required:"true" description:"log type register with listener"`
+	TagID   string      `description:"case tag id for reporting and selection"`
+	Type    string      `required:"true" description:"log type register with listener"`
+	Match   interface{} `description:"if specified, only pending records matching it are counted and validated in order, other records stay queued"`
+	Count   *int        `description:"expected exact number of (matching) pending records"`
 	Records []interface{}
 }
 
+// IsFiltered returns true if records are selected with match filter or counted
+func (r *TypedRecord) IsFiltered() bool {
+	return r.Match != nil || r.Count != nil
+}
+
 // AssertResponse represents a log assert response
 type AssertResponse struct {
 	Validations []*assertly.Validation
@@ -87,15 +99,53 @@ type Type struct {
 	indexExpr    *regexp.Regexp
 	UDF          string `description:"registered user defined function to transform content file before applying validation"`
 	Debug        bool   `description:"if set, every record appended to validation queue will be listed"`
+	MultiLine    string `description:"regular expression matching the first line of a record, i.e. ^\\d{4}-, other lines (stack traces) are appended to the previous record"`
+	startExpr    *regexp.Regexp
 }
 
 // ListenRequest represents listen for a logs request.
 type ListenRequest struct {
 	FrequencyMs int
-	Source      *location.Resource `required:"true" description:"log location"`
+	Tail        bool               `description:"tail local log files, only appended content is read once source directory change is notified"`
+	Source      *location.Resource `description:"log location"`
+	Journal     string             `description:"systemd unit to stream new journald records from, alternative to source"`
+	Container   string             `description:"docker container to stream new log records from, alternative to source"`
 	Types       []*Type            `required:"true" description:"log types"`
 }
 
+// Validate checks if request is valid
+func (r *ListenRequest) Validate() error {
+	sources := 0
+	for _, defined := range []bool{r.Source != nil, r.Journal != "", r.Container != ""} {
+		if defined {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("expected one of source, journal or container")
+	}
+	if r.Tail && r.Source == nil {
+		return fmt.Errorf("tail mode requires source")
+	}
+	if len(r.Types) == 0 {
+		return fmt.Errorf("types were empty")
+	}
+	for _, logType := range r.Types {
+		if !isFormatSupported(logType.Format) {
+			return fmt.Errorf("unsupported %v log format: %v", logType.Name, logType.Format)
+		}
+		if (r.Tail || r.Source == nil) && logType.UDF != "" {
+			return fmt.Errorf("%v: UDF is not supported in tail or stream mode", logType.Name)
+		}
+		if logType.MultiLine != "" {
+			if _, err := logType.GetMultiLineExpr(); err != nil {
+				return fmt.Errorf("invalid %v multiLine expression: %w", logType.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // ListenResponse represents a log validation listen response.
 type ListenResponse struct {
 	Meta TypesMeta
@@ -116,6 +166,16 @@ func (t *Type) GetIndexExpr() (*regexp.Regexp, error) {
 	return t.indexExpr, err
 }
 
+// GetMultiLineExpr returns multi-line record start expression.
+func (t *Type) GetMultiLineExpr() (*regexp.Regexp, error) {
+	if t.startExpr != nil {
+		return t.startExpr, nil
+	}
+	var err error
+	t.startExpr, err = regexp.Compile(t.MultiLine)
+	return t.startExpr, err
+}
+
 // ResetRequest represents a log reset request
 type ResetRequest struct {
 	LogTypes []string `required:"true" description:"log types to reset"`
@@ -10,6 +10,7 @@ import (
 	"github.com/viant/toolbox"
 	"io"
 	"io/ioutil"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +29,9 @@ type File struct {
 	IndexedRecords  map[string]*Record
 	Mutex           *sync.RWMutex
 	context         *endly.Context
+	lastRecord      *Record
+	skipRecord      bool
+	fileInfo        os.FileInfo
 }
 
 // ShiftLogRecord returns and remove the first log record if present
@@ -82,6 +86,25 @@ func (f *File) ShiftLogRecordByIndex(value string) (*Record, bool) {
 	return result, has
 }
 
+// MatchLogRecords returns pending log records matching predicate, matched records are removed if shift is set
+func (f *File) MatchLogRecords(predicate func(record *Record) bool, shift bool) []*Record {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+	var result = make([]*Record, 0)
+	var pending = make([]*Record, 0, len(f.Records))
+	for _, candidate := range f.Records {
+		if predicate(candidate) {
+			result = append(result, candidate)
+			continue
+		}
+		pending = append(pending, candidate)
+	}
+	if shift {
+		f.Records = pending
+	}
+	return result
+}
+
 // PushLogRecord appends provided log record to the records.
 func (f *File) PushLogRecord(record *Record) {
 	f.Mutex.Lock()
@@ -137,13 +160,16 @@ func (f *File) readLogRecords(reader io.Reader) error {
 	if f.ProcessingState.Position > len(data) {
 		return nil
 	}
+	return f.processLogRecords(data[f.ProcessingState.Position:])
+}
+
+// processLogRecords processes complete lines of data read from the current processing position
+func (f *File) processLogRecords(data []byte) error {
 	var line = ""
-	var startPosition = f.ProcessingState.Position
-	var startLine = f.ProcessingState.Line
-	var lineIndex = startLine
+	var lineIndex = f.ProcessingState.Line
 	var dataProcessed = 0
 
-	r := bufio.NewReader(strings.NewReader(string(data[startPosition:])))
+	r := bufio.NewReader(strings.NewReader(string(data)))
 	for {
 		code, size, err := r.ReadRune()
 		if err == io.EOF {
@@ -159,33 +185,36 @@ func (f *File) readLogRecords(reader io.Reader) error {
 			line += aChar
 			continue
 		}
-
-		line = strings.Trim(line, " \r\t")
 		lineIndex++
-		if f.Exclusion != "" {
-			if strings.Contains(line, f.Exclusion) {
-				line, dataProcessed = f.ProcessingState.Update(dataProcessed, lineIndex)
-				continue
-			}
-		}
-		if f.Inclusion != "" {
-			if !strings.Contains(line, f.Inclusion) {
-				line, dataProcessed = f.ProcessingState.Update(dataProcessed, lineIndex)
-				continue
-			}
-		}
-
-		if len(line) > 0 {
-			f.PushLogRecord(&Record{
-				URL:    f.URL,
-				Line:   line,
-				Number: lineIndex,
-			})
-		}
-		if err != nil {
-			return err
-		}
+		f.processLine(line, lineIndex)
 		line, dataProcessed = f.ProcessingState.Update(dataProcessed, lineIndex)
 	}
 	return nil
 }
+
+// processLine applies type filters and pushes a log record, with multi-line type a line not matching record start is appended to the previous record
+func (f *File) processLine(line string, number int) {
+	if f.MultiLine != "" && (f.lastRecord != nil || f.skipRecord) {
+		if expr, err := f.GetMultiLineExpr(); err == nil && !expr.MatchString(line) {
+			if f.lastRecord != nil && strings.TrimSpace(line) != "" {
+				f.Mutex.Lock()
+				f.lastRecord.Line += "\n" + strings.TrimRight(line, " \r\t")
+				f.Mutex.Unlock()
+			}
+			return
+		}
+	}
+	line = strings.Trim(line, " \r\t")
+	f.lastRecord = nil
+	f.skipRecord = (f.Exclusion != "" && strings.Contains(line, f.Exclusion)) || (f.Inclusion != "" && !strings.Contains(line, f.Inclusion))
+	if f.skipRecord || len(line) == 0 {
+		return
+	}
+	f.lastRecord = &Record{
+		URL:    f.URL,
+		Line:   line,
+		Number: number,
+		format: f.Format,
+	}
+	f.PushLogRecord(f.lastRecord)
+}
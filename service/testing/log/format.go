@@ -0,0 +1,224 @@
+package log
+
+import (
+	"fmt"
+	"github.com/viant/toolbox"
+	"regexp"
+	"strings"
+)
+
+const (
+	//FormatJSON represents JSON log record format (default)
+	FormatJSON = "json"
+	//FormatLogfmt represents key=value log record format
+	FormatLogfmt = "logfmt"
+	//FormatCombined represents Apache/Nginx combined (or common) access log format
+	FormatCombined = "combined"
+	//FormatSyslog represents syslog RFC5424 format
+	FormatSyslog = "syslog"
+)
+
+var combinedExpr = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "(\S+) (\S+)(?: (\S+))?" (\d{3}) (\d+|-)(?: "([^"]*)" "([^"]*)")?`)
+
+var syslogSeverities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// isFormatSupported returns true if log format is supported
+func isFormatSupported(format string) bool {
+	switch strings.ToLower(format) {
+	case "", FormatJSON, FormatLogfmt, FormatCombined, FormatSyslog:
+		return true
+	}
+	return false
+}
+
+// parseRecord parses log line with supplied format
+func parseRecord(format, line string) (map[string]interface{}, error) {
+	switch strings.ToLower(format) {
+	case FormatLogfmt:
+		return parseLogfmt(line)
+	case FormatCombined:
+		return parseCombined(line)
+	case FormatSyslog:
+		return parseSyslog(line)
+	}
+	var result = make(map[string]interface{})
+	err := toolbox.NewJSONDecoderFactory().Create(strings.NewReader(line)).Decode(&result)
+	return result, err
+}
+
+// parseLogfmt parses key=value pairs, values can be double quoted, a key without value is set to true
+func parseLogfmt(line string) (map[string]interface{}, error) {
+	var result = make(map[string]interface{})
+	for i := 0; i < len(line); {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			if i < len(line) {
+				return nil, fmt.Errorf("invalid logfmt at %v: %v", i, line)
+			}
+			break
+		}
+		if i >= len(line) || line[i] == ' ' {
+			result[key] = true
+			continue
+		}
+		i++ //skip '='
+		if i < len(line) && line[i] == '"' {
+			value, end, err := unquote(line, i+1, '"')
+			if err != nil {
+				return nil, err
+			}
+			result[key] = value
+			i = end + 1
+			continue
+		}
+		start = i
+		for i < len(line) && line[i] != ' ' {
+			i++
+		}
+		result[key] = line[start:i]
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("invalid logfmt record: %v", line)
+	}
+	return result, nil
+}
+
+// unquote reads escaped text from offset till terminator, returns text and terminator position
+func unquote(line string, offset int, terminator byte) (string, int, error) {
+	var value = make([]byte, 0)
+	for i := offset; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if i+1 < len(line) {
+				i++
+				value = append(value, line[i])
+			}
+		case terminator:
+			return string(value), i, nil
+		default:
+			value = append(value, line[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted value: %v", line)
+}
+
+// parseCombined parses Apache/Nginx combined or common log format record
+func parseCombined(line string) (map[string]interface{}, error) {
+	matches := combinedExpr.FindStringSubmatch(line)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("invalid combined log record: %v", line)
+	}
+	var result = map[string]interface{}{
+		"host":     matches[1],
+		"ident":    matches[2],
+		"user":     matches[3],
+		"time":     matches[4],
+		"method":   matches[5],
+		"path":     matches[6],
+		"protocol": matches[7],
+		"status":   toolbox.AsInt(matches[8]),
+		"size":     0,
+	}
+	if matches[9] != "-" {
+		result["size"] = toolbox.AsInt(matches[9])
+	}
+	if matches[10] != "" || matches[11] != "" {
+		result["referer"] = matches[10]
+		result["userAgent"] = matches[11]
+	}
+	return result, nil
+}
+
+// parseSyslog parses RFC5424 record: <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]
+func parseSyslog(line string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(line, "<") {
+		return nil, fmt.Errorf("invalid syslog record: %v", line)
+	}
+	end := strings.Index(line, ">")
+	if end == -1 {
+		return nil, fmt.Errorf("invalid syslog priority: %v", line)
+	}
+	priority, err := toolbox.ToInt(line[1:end])
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog priority: %v", line)
+	}
+	fields := strings.SplitN(line[end+1:], " ", 7)
+	if len(fields) < 7 {
+		return nil, fmt.Errorf("invalid syslog header: %v", line)
+	}
+	severity := priority % 8
+	var result = map[string]interface{}{
+		"priority":  priority,
+		"facility":  priority / 8,
+		"severity":  severity,
+		"level":     syslogSeverities[severity],
+		"version":   toolbox.AsInt(fields[0]),
+		"timestamp": nilValue(fields[1]),
+		"hostname":  nilValue(fields[2]),
+		"appName":   nilValue(fields[3]),
+		"procID":    nilValue(fields[4]),
+		"msgID":     nilValue(fields[5]),
+	}
+	structured, message, err := parseStructuredData(fields[6])
+	if err != nil {
+		return nil, err
+	}
+	if len(structured) > 0 {
+		result["structuredData"] = structured
+	}
+	result["message"] = strings.TrimPrefix(message, "\ufeff")
+	return result, nil
+}
+
+// parseStructuredData parses [id name="value" ...] elements, returns elements and remaining message
+func parseStructuredData(text string) (map[string]interface{}, string, error) {
+	var result = make(map[string]interface{})
+	if strings.HasPrefix(text, "-") {
+		return result, strings.TrimPrefix(strings.TrimPrefix(text, "-"), " "), nil
+	}
+	i := 0
+	for i < len(text) && text[i] == '[' {
+		i++
+		start := i
+		for i < len(text) && text[i] != ' ' && text[i] != ']' {
+			i++
+		}
+		params := make(map[string]interface{})
+		result[text[start:i]] = params
+		for i < len(text) && text[i] == ' ' {
+			i++
+			start = i
+			for i < len(text) && text[i] != '=' {
+				i++
+			}
+			if i+1 >= len(text) || text[i+1] != '"' {
+				return nil, "", fmt.Errorf("invalid syslog structured data: %v", text)
+			}
+			value, end, err := unquote(text, i+2, '"')
+			if err != nil {
+				return nil, "", err
+			}
+			params[text[start:i]] = value
+			i = end + 1
+		}
+		if i >= len(text) || text[i] != ']' {
+			return nil, "", fmt.Errorf("invalid syslog structured data: %v", text)
+		}
+		i++
+	}
+	return result, strings.TrimPrefix(text[i:], " "), nil
+}
+
+func nilValue(value string) interface{} {
+	if value == "-" {
+		return nil
+	}
+	return value
+}
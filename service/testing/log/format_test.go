@@ -0,0 +1,87 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseRecord(t *testing.T) {
+	var useCases = []struct {
+		description string
+		format      string
+		line        string
+		expect      map[string]interface{}
+		hasError    bool
+	}{
+		{
+			description: "json",
+			format:      FormatJSON,
+			line:        `{"level":"ERROR","id":1}`,
+			expect:      map[string]interface{}{"level": "ERROR", "id": 1.0},
+		},
+		{
+			description: "logfmt",
+			format:      FormatLogfmt,
+			line:        `level=error msg="failed to connect \"db\"" retry attempt=3`,
+			expect:      map[string]interface{}{"level": "error", "msg": `failed to connect "db"`, "retry": true, "attempt": "3"},
+		},
+		{
+			description: "combined",
+			format:      FormatCombined,
+			line:        `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"`,
+			expect: map[string]interface{}{
+				"host": "127.0.0.1", "ident": "-", "user": "frank", "time": "10/Oct/2000:13:55:36 -0700",
+				"method": "GET", "path": "/apache_pb.gif", "protocol": "HTTP/1.0", "status": 200, "size": 2326,
+				"referer": "http://www.example.com/start.html", "userAgent": "Mozilla/4.08",
+			},
+		},
+		{
+			description: "common",
+			format:      FormatCombined,
+			line:        `10.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "POST /v1/event HTTP/1.1" 204 -`,
+			expect: map[string]interface{}{
+				"host": "10.0.0.1", "ident": "-", "user": "-", "time": "10/Oct/2000:13:55:36 -0700",
+				"method": "POST", "path": "/v1/event", "protocol": "HTTP/1.1", "status": 204, "size": 0,
+			},
+		},
+		{
+			description: "syslog",
+			format:      FormatSyslog,
+			line:        `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application"] An application event`,
+			expect: map[string]interface{}{
+				"priority": 165, "facility": 20, "severity": 5, "level": "notice", "version": 1,
+				"timestamp": "2003-10-11T22:14:15.003Z", "hostname": "mymachine.example.com", "appName": "evntslog",
+				"procID": nil, "msgID": "ID47",
+				"structuredData": map[string]interface{}{"exampleSDID@32473": map[string]interface{}{"iut": "3", "eventSource": "Application"}},
+				"message":        "An application event",
+			},
+		},
+		{
+			description: "syslog without structured data",
+			format:      FormatSyslog,
+			line:        `<34>1 2003-10-11T22:14:15.003Z host su - ID47 - 'su root' failed`,
+			expect: map[string]interface{}{
+				"priority": 34, "facility": 4, "severity": 2, "level": "crit", "version": 1,
+				"timestamp": "2003-10-11T22:14:15.003Z", "hostname": "host", "appName": "su",
+				"procID": nil, "msgID": "ID47", "message": "'su root' failed",
+			},
+		},
+		{
+			description: "invalid syslog",
+			format:      FormatSyslog,
+			line:        `Oct 11 22:14:15 host su: failed`,
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		actual, err := parseRecord(useCase.format, useCase.line)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 func matchLogIndex(expr *regexp.Regexp, input string) string {
@@ -18,3 +19,13 @@ func matchLogIndex(expr *regexp.Regexp, input string) string {
 func logTypeMetaKey(name string) string {
 	return fmt.Sprintf("meta_%v", name)
 }
+
+// matchMask returns true if log file name matches log type mask, i.e. *.log
+func matchMask(mask, name string) (bool, error) {
+	mask = strings.Replace(mask, "*", ".+", len(mask))
+	maskExpression, err := regexp.Compile("^" + mask + "$")
+	if err != nil {
+		return false, err
+	}
+	return maskExpression.MatchString(name), nil
+}
@@ -18,26 +18,35 @@ type TypeMeta struct {
 
 // Iterator returns log record iterator
 func (m *TypeMeta) Iterator() toolbox.Iterator {
-	logFileProvider := func() []*File {
-		var result = make([]*File, 0)
-		for _, logFile := range m.LogFiles {
-			result = append(result, logFile)
-		}
-		sort.Slice(result, func(i, j int) bool {
-			var left = result[i].LastModified
-			var right = result[j].LastModified
-			if !left.After(right) && !right.After(left) {
-				return result[i].URL > result[j].URL
-			}
-			return left.After(right)
-		})
-		return result
+	return &logRecordIterator{
+		logFiles:        m.logFiles(),
+		logFileProvider: m.logFiles,
 	}
+}
 
-	return &logRecordIterator{
-		logFiles:        logFileProvider(),
-		logFileProvider: logFileProvider,
+// Match returns pending log records matching predicate, matched records are removed if shift is set
+func (m *TypeMeta) Match(predicate func(record *Record) bool, shift bool) []*Record {
+	var result = make([]*Record, 0)
+	for _, logFile := range m.logFiles() {
+		result = append(result, logFile.MatchLogRecords(predicate, shift)...)
 	}
+	return result
+}
+
+func (m *TypeMeta) logFiles() []*File {
+	var result = make([]*File, 0)
+	for _, logFile := range m.LogFiles {
+		result = append(result, logFile)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		var left = result[i].LastModified
+		var right = result[j].LastModified
+		if !left.After(right) && !right.After(left) {
+			return result[i].URL > result[j].URL
+		}
+		return left.After(right)
+	})
+	return result
 }
 
 // NewTypeMeta creates a nre log type meta.
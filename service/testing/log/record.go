@@ -1,15 +1,11 @@
 package log
 
-import (
-	"github.com/viant/toolbox"
-	"strings"
-)
-
 // Record represents a log record
 type Record struct {
 	URL    string
 	Number int
 	Line   string
+	format string
 }
 
 // IndexedRecord represents indexed log record
@@ -18,9 +14,7 @@ type IndexedRecord struct {
 	IndexValue string
 }
 
-// AsMap returns log records as map
+// AsMap returns log records as map, the line is parsed with log type format (json by default)
 func (r *Record) AsMap() (map[string]interface{}, error) {
-	var result = make(map[string]interface{})
-	err := toolbox.NewJSONDecoderFactory().Create(strings.NewReader(r.Line)).Decode(&result)
-	return result, err
+	return parseRecord(r.format, r.Line)
 }
@@ -3,6 +3,7 @@ package log
 import (
 	"bytes"
 	"fmt"
+	"github.com/fsnotify/fsnotify"
 	"github.com/viant/afs"
 	"github.com/viant/afs/storage"
 	"github.com/viant/assertly"
@@ -17,7 +18,6 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -75,6 +75,13 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 		aMap.Put("logType", expectedLogRecords.Type)
 		aMap.Put("tagID", expectedLogRecords.TagID)
 
+		if expectedLogRecords.IsFiltered() {
+			if err = s.assertMatched(context, request, typeMeta, expectedLogRecords, aMap.ExpandAsText(request.DescriptionTemplate), response); err != nil {
+				return response, err
+			}
+			continue
+		}
+
 		for _, expectedRecord := range expectedLogRecords.Records {
 			var validation = &assertly.Validation{
 				TagID:       expectedLogRecords.TagID,
@@ -117,16 +124,110 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 	return response, nil
 }
 
+// assertMatched validates count and order of pending records matching expected filter, matched records are removed from queues
+func (s *service) assertMatched(context *endly.Context, request *AssertRequest, typeMeta *TypeMeta, expected *TypedRecord, description string, response *AssertResponse) error {
+	var matchErr error
+	predicate := func(record *Record) bool {
+		if expected.Match == nil {
+			return true
+		}
+		actual, err := actualLogRecord(record, expected.Match)
+		if err != nil {
+			return false
+		}
+		validation, err := criteria.Assert(context, "", expected.Match, actual)
+		if err != nil {
+			matchErr = err
+			return false
+		}
+		return !validation.HasFailure()
+	}
+	minCount := len(expected.Records)
+	if expected.Count != nil && *expected.Count > minCount {
+		minCount = *expected.Count
+	}
+	for j := 0; j < request.LogWaitRetryCount; j++ {
+		if len(typeMeta.Match(predicate, false)) >= minCount || !waitForLogs(context, request.LogWaitTimeMs) {
+			break
+		}
+	}
+	records := typeMeta.Match(predicate, true)
+	if matchErr != nil {
+		return matchErr
+	}
+	var validation = &assertly.Validation{
+		TagID:       expected.TagID,
+		Description: description,
+	}
+	response.Validations = append(response.Validations, validation)
+	path := fmt.Sprintf("[%v]", expected.TagID)
+	if expected.Count != nil {
+		if len(records) == *expected.Count {
+			validation.PassedCount++
+		} else {
+			validation.AddFailure(assertly.NewFailure("", path+".count", "count mismatch", *expected.Count, len(records)))
+		}
+	}
+	for i, expectedRecord := range expected.Records {
+		if i >= len(records) {
+			validation.AddFailure(assertly.NewFailure("", fmt.Sprintf("%v[%v]", path, i), "missing log record", expectedRecord, nil))
+			continue
+		}
+		actual, err := actualLogRecord(records[i], expectedRecord)
+		if err != nil {
+			return err
+		}
+		_, filename := toolbox.URLSplit(records[i].URL)
+		recordValidation, err := criteria.Assert(context, fmt.Sprintf("%v:%v", filename, records[i].Number), expectedRecord, actual)
+		if err != nil {
+			return err
+		}
+		context.Publish(&validator.TaggedAssert{TagID: expected.TagID, Expected: expectedRecord, Actual: actual})
+		context.Publish(recordValidation)
+		validation.MergeFrom(recordValidation)
+	}
+	return nil
+}
+
+// actualLogRecord returns log record map for structured expected value, or log line otherwise
+func actualLogRecord(record *Record, expected interface{}) (interface{}, error) {
+	if toolbox.IsMap(expected) {
+		return record.AsMap()
+	}
+	return record.Line, nil
+}
+
 func (s *service) waitForRecord(context *endly.Context, recordIterator toolbox.Iterator, request *AssertRequest) bool {
 	for j := 0; j < request.LogWaitRetryCount; j++ {
 		if recordIterator.HasNext() {
 			return true
 		}
-		s.Sleep(context, int(request.LogWaitTimeMs))
+		if !waitForLogs(context, request.LogWaitTimeMs) {
+			break
+		}
 	}
 	return recordIterator.HasNext()
 }
 
+// waitForLogs waits supplied time for pending log records, it returns false once the context deadline is exceeded
+func waitForLogs(context *endly.Context, waitTimeMs int) bool {
+	ctx := context.Background()
+	if ctx.Err() != nil {
+		return false
+	}
+	if waitTimeMs <= 0 {
+		return true
+	}
+	timer := time.NewTimer(time.Duration(waitTimeMs) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func (s *service) matchLogRecord(typeMeta *TypeMeta, expectedRecord interface{}, logRecordIterator toolbox.Iterator) (*Record, error) {
 	var calledNext = false
 	var logRecord *Record
@@ -195,10 +296,11 @@ func (s *service) tryReadSnapshot(context *endly.Context, fs afs.Service, object
 	return nil, nil
 }
 
-func (s *service) readLogFile(context *endly.Context, source *location.Resource, fs afs.Service, candidate storage.Object, logType *Type) (*TypeMeta, error) {
-	var result *TypeMeta
+// getLogFile returns log type meta and log file, a new log file is registered if needed
+func (s *service) getLogFile(context *endly.Context, source *location.Resource, logType *Type, URL string, modified time.Time, size int) (*TypeMeta, *File, bool, error) {
 	var key = logTypeMetaKey(logType.Name)
 	s.Mutex().Lock()
+	defer s.Mutex().Unlock()
 
 	var state = s.State()
 	if !state.Has(key) {
@@ -207,22 +309,19 @@ func (s *service) readLogFile(context *endly.Context, source *location.Resource,
 
 	result, ok := state.Get(key).(*TypeMeta)
 	if !ok {
-		return nil, fmt.Errorf("failed to fwtch type meta")
+		return nil, nil, false, fmt.Errorf("failed to fwtch type meta")
 	}
 
-	var isNewLogFile = false
-	_, name := toolbox.URLSplit(candidate.URL())
+	_, name := toolbox.URLSplit(URL)
 	logFile, has := result.LogFiles[name]
-	fileInfo := candidate
 	if !has {
-		isNewLogFile = true
 		logFile = &File{
 			context:         context,
 			Type:            logType,
 			Name:            name,
-			URL:             candidate.URL(),
-			LastModified:    fileInfo.ModTime(),
-			Size:            int(fileInfo.Size()),
+			URL:             URL,
+			LastModified:    modified,
+			Size:            size,
 			ProcessingState: &ProcessingState{},
 			Mutex:           &sync.RWMutex{},
 			Records:         make([]*Record, 0),
@@ -230,7 +329,15 @@ func (s *service) readLogFile(context *endly.Context, source *location.Resource,
 		}
 		result.LogFiles[name] = logFile
 	}
-	s.Mutex().Unlock()
+	return result, logFile, !has, nil
+}
+
+func (s *service) readLogFile(context *endly.Context, source *location.Resource, fs afs.Service, candidate storage.Object, logType *Type) (*TypeMeta, error) {
+	fileInfo := candidate
+	result, logFile, isNewLogFile, err := s.getLogFile(context, source, logType, candidate.URL(), fileInfo.ModTime(), int(fileInfo.Size()))
+	if err != nil {
+		return nil, err
+	}
 
 	if !isNewLogFile && (logFile.Size == int(fileInfo.Size()) && logFile.LastModified.Unix() == fileInfo.ModTime().Unix()) {
 		return result, nil
@@ -298,13 +405,12 @@ func (s *service) readLogFiles(context *endly.Context, fs afs.Service, source *l
 			continue
 		}
 		for _, logType := range logTypes {
-			mask := strings.Replace(logType.Mask, "*", ".+", len(logType.Mask))
-			maskExpression, err := regexp.Compile("^" + mask + "$")
+			_, name := toolbox.URLSplit(candidate.URL())
+			matched, err := matchMask(logType.Mask, name)
 			if err != nil {
 				return nil, err
 			}
-			_, name := toolbox.URLSplit(candidate.URL())
-			if maskExpression.MatchString(name) {
+			if matched {
 				logTypeMeta, err := s.readLogFile(context, source, fs, candidate, logType)
 				if err != nil {
 					return nil, err
@@ -321,6 +427,9 @@ func (s *service) listenForChanges(context *endly.Context, request *ListenReques
 	if err != nil {
 		return err
 	}
+	if request.Tail {
+		return s.tailForChanges(context, target, request)
+	}
 	fs, err := estorage.StorageService(context, target)
 	if err != nil {
 		return err
@@ -344,17 +453,64 @@ func (s *service) listenForChanges(context *endly.Context, request *ListenReques
 	return nil
 }
 
-func (s *service) listen(context *endly.Context, request *ListenRequest) (*ListenResponse, error) {
-	var source, err = context.ExpandResource(request.Source)
+// tailForChanges watches source directory and tails matching log files once they change, tail errors are published as TailErrorEvent
+func (s *service) tailForChanges(context *endly.Context, target *location.Resource, request *ListenRequest) error {
+	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if err = watcher.Add(target.Path()); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch %v: %w", target.URL, err)
 	}
+	context.Deffer(func() {
+		_ = watcher.Close()
+	})
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok || context.IsClosed() {
+					return
+				}
+				if event.Op == fsnotify.Chmod {
+					continue
+				}
+				if _, err := s.tailLogFiles(context, target, request.Types...); err != nil {
+					context.Publish(NewTailErrorEvent(target.URL, err))
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok || context.IsClosed() {
+					return
+				}
+				context.Publish(NewTailErrorEvent(target.URL, err))
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *service) listen(context *endly.Context, request *ListenRequest) (*ListenResponse, error) {
 	var state = s.State()
 	for _, logType := range request.Types {
 		if state.Has(logTypeMetaKey(logType.Name)) {
 			return nil, fmt.Errorf("listener has been already register for %v", logType.Name)
 		}
 	}
+	if request.Source == nil {
+		return s.listenForStream(context, request)
+	}
+	var source, err = context.ExpandResource(request.Source)
+	if err != nil {
+		return nil, err
+	}
+	if request.Tail {
+		logTypeMetas, err := s.tailLogFiles(context, source, request.Types...)
+		if err != nil {
+			return nil, err
+		}
+		return s.registerTypes(context, source, request, logTypeMetas)
+	}
 
 	fs, err := estorage.StorageService(context, source)
 	if err != nil {
@@ -370,6 +526,12 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 	if err != nil {
 		return nil, err
 	}
+	return s.registerTypes(context, source, request, logTypeMetas)
+}
+
+// registerTypes registers log types meta and starts listening for changes
+func (s *service) registerTypes(context *endly.Context, source *location.Resource, request *ListenRequest, logTypeMetas TypesMeta) (*ListenResponse, error) {
+	var state = s.State()
 	for _, logType := range request.Types {
 		logMeta, ok := logTypeMetas[logType.Name]
 		if !ok {
@@ -382,7 +544,7 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 	response := &ListenResponse{
 		Meta: logTypeMetas,
 	}
-	err = s.listenForChanges(context, request)
+	err := s.listenForChanges(context, request)
 	return response, err
 }
 
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// streamCommand returns command streaming new journald or docker container log records
+func streamCommand(request *ListenRequest) (string, []string) {
+	if request.Journal != "" {
+		return "journalctl", []string{"--no-pager", "--follow", "--lines=0", "--output=cat", "--unit", request.Journal}
+	}
+	return "docker", []string{"logs", "--follow", "--tail", "0", request.Container}
+}
+
+// streamURL returns URL representing a stream, i.e. journald://localhost/nginx.service or docker://db, the same layout as docker target
+func streamURL(request *ListenRequest) string {
+	if request.Journal != "" {
+		return "journald://localhost/" + request.Journal
+	}
+	return "docker://" + request.Container
+}
+
+// listenForStream starts journalctl or docker logs process and pushes streamed lines to log types queues
+func (s *service) listenForStream(context *endly.Context, request *ListenRequest) (*ListenResponse, error) {
+	URL := streamURL(request)
+	source := location.NewResource(URL)
+	var logFiles = make([]*File, 0)
+	var response = &ListenResponse{Meta: make(map[string]*TypeMeta)}
+	for _, logType := range request.Types {
+		logTypeMeta, logFile, _, err := s.getLogFile(context, source, logType, URL, time.Now(), 0)
+		if err != nil {
+			return nil, err
+		}
+		response.Meta[logType.Name] = logTypeMeta
+		logFiles = append(logFiles, logFile)
+	}
+	command, args := streamCommand(request)
+	cmd := exec.Command(command, args...)
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer //docker logs writes container stderr to stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %v: %w", command, err)
+	}
+	context.Deffer(func() {
+		_ = cmd.Process.Kill()
+	})
+	go func() {
+		err := cmd.Wait()
+		_ = writer.CloseWithError(err)
+	}()
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		number := 0
+		for scanner.Scan() {
+			number++
+			for _, logFile := range logFiles {
+				logFile.processLine(scanner.Text(), number)
+			}
+		}
+		if err := scanner.Err(); err != nil && !context.IsClosed() {
+			context.Publish(NewTailErrorEvent(URL, fmt.Errorf("log stream terminated: %w", err)))
+		}
+	}()
+	return response, nil
+}
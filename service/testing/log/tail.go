@@ -0,0 +1,93 @@
+package log
+
+import (
+	"fmt"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"io"
+	"os"
+	"path"
+)
+
+// TailErrorEvent represents an error of tailing or streaming log records, listening continues unless the stream terminated
+type TailErrorEvent struct {
+	Source string
+	Error  string
+}
+
+// NewTailErrorEvent creates tail error event
+func NewTailErrorEvent(source string, err error) *TailErrorEvent {
+	return &TailErrorEvent{Source: source, Error: err.Error()}
+}
+
+// tailLogFiles reads only content appended to local log files since the last read, truncated or rotated files are read from the beginning
+func (s *service) tailLogFiles(context *endly.Context, source *location.Resource, logTypes ...*Type) (TypesMeta, error) {
+	if scheme := url.Scheme(source.URL, file.Scheme); scheme != file.Scheme {
+		return nil, fmt.Errorf("tail mode supports local files only, but had: %v", source.URL)
+	}
+	directory := source.Path()
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	var response TypesMeta = make(map[string]*TypeMeta)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		for _, logType := range logTypes {
+			matched, err := matchMask(logType.Mask, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+			logTypeMeta, err := s.tailLogFile(context, source, entry.Name(), logType)
+			if err != nil {
+				return nil, err
+			}
+			response[logType.Name] = logTypeMeta
+		}
+	}
+	return response, nil
+}
+
+func (s *service) tailLogFile(context *endly.Context, source *location.Resource, name string, logType *Type) (*TypeMeta, error) {
+	filename := path.Join(source.Path(), name)
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	result, logFile, _, err := s.getLogFile(context, source, logType, url.Join(source.URL, name), info.ModTime(), 0)
+	if err != nil {
+		return nil, err
+	}
+	rotated := logFile.fileInfo != nil && !os.SameFile(logFile.fileInfo, info)
+	if rotated || int(info.Size()) < logFile.ProcessingState.Position {
+		logFile.Mutex.Lock()
+		logFile.ProcessingState.Reset()
+		logFile.Mutex.Unlock()
+	}
+	logFile.fileInfo = info
+	logFile.LastModified = info.ModTime()
+	logFile.Size = int(info.Size())
+	if logFile.Size == logFile.ProcessingState.Position {
+		return result, nil
+	}
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	if _, err = reader.Seek(int64(logFile.ProcessingState.Position), io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return result, logFile.processLogRecords(data)
+}
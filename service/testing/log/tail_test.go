@@ -0,0 +1,173 @@
+package log_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/service/testing/log"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestService_Tail(t *testing.T) {
+	context := endly.New().NewContext(toolbox.NewContext())
+	defer context.Close()
+	directory := t.TempDir()
+	logFile := path.Join(directory, "app.log")
+	assert.Nil(t, os.WriteFile(logFile, []byte("ts=1 level=INFO msg=start\n"), 0644))
+
+	err := endly.Run(context, &log.ListenRequest{
+		Tail:   true,
+		Source: location.NewResource(directory),
+		Types: []*log.Type{
+			{Name: "app", Format: log.FormatLogfmt, Mask: "*.log", MultiLine: "^ts="},
+		},
+	}, &log.ListenResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	appended, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, _ = appended.WriteString("ts=2 level=ERROR msg=failed\n  at a.go:1\n  at b.go:2\nts=3 level=ERROR msg=again\nts=4 level=INFO msg=done\n")
+	_ = appended.Close()
+	time.Sleep(300 * time.Millisecond)
+
+	count := func(value int) *int { return &value }
+	var useCases = []struct {
+		description string
+		expect      *log.TypedRecord
+		failed      int
+	}{
+		{
+			description: "matched count and order",
+			expect: &log.TypedRecord{Type: "app", TagID: "errors", Match: map[string]interface{}{"level": "ERROR"}, Count: count(2), Records: []interface{}{
+				"ts=2 level=ERROR msg=failed\n  at a.go:1\n  at b.go:2",
+				map[string]interface{}{"msg": "again"},
+			}},
+		},
+		{
+			description: "count mismatch",
+			expect:      &log.TypedRecord{Type: "app", Match: map[string]interface{}{"level": "WARN"}, Count: count(1)},
+			failed:      1,
+		},
+		{
+			description: "remaining records order",
+			expect: &log.TypedRecord{Type: "app", Count: count(2), Records: []interface{}{
+				map[string]interface{}{"msg": "start"},
+				map[string]interface{}{"msg": "done"},
+			}},
+		},
+	}
+	for _, useCase := range useCases {
+		response := &log.AssertResponse{}
+		err = endly.Run(context, &log.AssertRequest{
+			LogWaitTimeMs:     50,
+			LogWaitRetryCount: 2,
+			Expect:            []*log.TypedRecord{useCase.expect},
+		}, response)
+		if !assert.Nil(t, err, useCase.description) || !assert.Len(t, response.Validations, 1, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.failed, response.Validations[0].FailedCount, useCase.description)
+	}
+
+	timedContext, cancel := context.WithTimeout(200 * time.Millisecond)
+	started := time.Now()
+	err = endly.Run(timedContext, &log.AssertRequest{
+		LogWaitTimeMs:     5000,
+		LogWaitRetryCount: 3,
+		Expect:            []*log.TypedRecord{{Type: "app", Match: map[string]interface{}{"level": "WARN"}, Count: count(1)}},
+	}, &log.AssertResponse{})
+	cancel()
+	assert.Nil(t, err)
+	assert.True(t, time.Since(started) < 2*time.Second, "log wait should stop once deadline is exceeded")
+
+	assert.Nil(t, os.WriteFile(logFile, []byte("ts=5 level=INFO msg=rotated\n"), 0644))
+	time.Sleep(300 * time.Millisecond)
+	response := &log.AssertResponse{}
+	err = endly.Run(context, &log.AssertRequest{
+		Expect: []*log.TypedRecord{{Type: "app", Count: count(1), Records: []interface{}{map[string]interface{}{"msg": "rotated"}}}},
+	}, response)
+	if assert.Nil(t, err) && assert.Len(t, response.Validations, 1) {
+		assert.EqualValues(t, 0, response.Validations[0].FailedCount)
+	}
+}
+
+func TestService_TailError(t *testing.T) {
+	context := endly.New().NewContext(toolbox.NewContext())
+	defer context.Close()
+	var mux sync.Mutex
+	var tailErrors = make([]*log.TailErrorEvent, 0)
+	context.SetListener(func(event msg.Event) {
+		if tailError, ok := event.Value().(*log.TailErrorEvent); ok {
+			mux.Lock()
+			tailErrors = append(tailErrors, tailError)
+			mux.Unlock()
+		}
+	})
+	directory := t.TempDir()
+	logFile := path.Join(directory, "app.log")
+	assert.Nil(t, os.WriteFile(logFile, []byte("ts=1 level=INFO msg=start\n"), 0644))
+	err := endly.Run(context, &log.ListenRequest{
+		Tail:   true,
+		Source: location.NewResource(directory),
+		Types:  []*log.Type{{Name: "app", Format: log.FormatLogfmt, Mask: "*.log"}},
+	}, &log.ListenResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	brokenLink := path.Join(directory, "broken.log")
+	if !assert.Nil(t, os.Symlink(path.Join(directory, "missing"), brokenLink)) {
+		return
+	}
+	time.Sleep(200 * time.Millisecond)
+	mux.Lock()
+	if assert.True(t, len(tailErrors) > 0, "tail error should be published") {
+		assert.Contains(t, tailErrors[0].Error, "broken.log")
+	}
+	mux.Unlock()
+
+	assert.Nil(t, os.Remove(brokenLink))
+	appended, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, _ = appended.WriteString("ts=2 level=INFO msg=next\n")
+	_ = appended.Close()
+	time.Sleep(200 * time.Millisecond)
+	count := func(value int) *int { return &value }
+	response := &log.AssertResponse{}
+	err = endly.Run(context, &log.AssertRequest{
+		Expect: []*log.TypedRecord{{Type: "app", Count: count(2)}},
+	}, response)
+	if assert.Nil(t, err) && assert.Len(t, response.Validations, 1) {
+		assert.EqualValues(t, 0, response.Validations[0].FailedCount, "tailing should continue after error")
+	}
+}
+
+func TestListenRequest_Validate(t *testing.T) {
+	var useCases = []struct {
+		description string
+		request     *log.ListenRequest
+		hasError    bool
+	}{
+		{description: "source", request: &log.ListenRequest{Source: location.NewResource("/tmp"), Types: []*log.Type{{Name: "t"}}}},
+		{description: "container", request: &log.ListenRequest{Container: "app", Types: []*log.Type{{Name: "t", Format: log.FormatSyslog}}}},
+		{description: "no source", request: &log.ListenRequest{Types: []*log.Type{{Name: "t"}}}, hasError: true},
+		{description: "many sources", request: &log.ListenRequest{Journal: "nginx", Container: "app", Types: []*log.Type{{Name: "t"}}}, hasError: true},
+		{description: "unsupported format", request: &log.ListenRequest{Journal: "nginx", Types: []*log.Type{{Name: "t", Format: "xml"}}}, hasError: true},
+		{description: "tail udf", request: &log.ListenRequest{Tail: true, Source: location.NewResource("/tmp"), Types: []*log.Type{{Name: "t", UDF: "CsvReader"}}}, hasError: true},
+		{description: "invalid multiLine", request: &log.ListenRequest{Source: location.NewResource("/tmp"), Types: []*log.Type{{Name: "t", MultiLine: "("}}}, hasError: true},
+	}
+	for _, useCase := range useCases {
+		err := useCase.request.Validate()
+		assert.EqualValues(t, useCase.hasError, err != nil, useCase.description)
+	}
+}
@@ -109,7 +109,7 @@ func (s *service) push(context *endly.Context, request *PushRequest) (interface{
 		request.Messages = loadMessages([]byte(download.Payload))
 	}
 
-	var duration, _ = toolbox.NewDuration(request.TimeoutMs, toolbox.DurationMillisecond)
+	var duration, _ = toolbox.NewDuration(context.TimeoutMs(request.TimeoutMs), toolbox.DurationMillisecond)
 	client, err := NewPubSubClient(context, request.Dest, duration)
 	if err != nil {
 		return response, err
@@ -137,7 +137,7 @@ func (s *service) push(context *endly.Context, request *PushRequest) (interface{
 
 func (s *service) pull(context *endly.Context, request *PullRequest) (interface{}, error) {
 	response := PullResponse{}
-	var duration, _ = toolbox.NewDuration(request.TimeoutMs, toolbox.DurationMillisecond)
+	var duration, _ = toolbox.NewDuration(context.TimeoutMs(request.TimeoutMs), toolbox.DurationMillisecond)
 	client, err := NewPubSubClient(context, request.Source, duration)
 	if err != nil {
 		return response, err
@@ -163,7 +163,7 @@ func (s *service) pull(context *endly.Context, request *PullRequest) (interface{
 }
 
 func (s *service) setupResource(context *endly.Context, resource *ResourceSetup) (*Resource, error) {
-	var duration, _ = toolbox.NewDuration(defaultTimeoutMs, toolbox.DurationMillisecond)
+	var duration, _ = toolbox.NewDuration(context.TimeoutMs(defaultTimeoutMs), toolbox.DurationMillisecond)
 	client, err := NewPubSubClient(context, &resource.Resource, duration)
 	if err != nil {
 		return nil, err
@@ -205,7 +205,7 @@ func (s *service) delete(context *endly.Context, request *DeleteRequest) (interf
 	return response, nil
 }
 func (s *service) deleteResource(context *endly.Context, resource *Resource) error {
-	var duration, _ = toolbox.NewDuration(defaultTimeoutMs, toolbox.DurationMillisecond)
+	var duration, _ = toolbox.NewDuration(context.TimeoutMs(defaultTimeoutMs), toolbox.DurationMillisecond)
 	client, err := NewPubSubClient(context, resource, duration)
 	if err != nil {
 		return err
@@ -0,0 +1,145 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/assertly"
+	"github.com/viant/endly/service/testing/runner/rest"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/toolbox"
+	"net/http"
+	"strings"
+)
+
+// SendRequest represents GraphQL query or mutation request
+type SendRequest struct {
+	Options       map[string]interface{} `description:"http client options: key value pairs, where key is one of the following: HTTP options:RequestTimeoutMs,TimeoutMs,KeepAliveTimeMs,TLSHandshakeTimeoutMs,ResponseHeaderTimeoutMs,MaxIdleConns,FollowRedirects"`
+	httpOptions   []*toolbox.HttpOptions
+	URL           string `required:"true" description:"GraphQL endpoint URL"`
+	Header        http.Header
+	Query         string                 `required:"true" description:"query or mutation document"`
+	OperationName string                 `description:"operation to execute if query document defines more than one"`
+	Variables     map[string]interface{} `description:"query variables"`
+	AllowErrors   bool                   `description:"flag to not report response errors as validation failures, i.e. to validate errors with expect"`
+	Expect        interface{}            `description:"If specified it will validated response as actual"`
+}
+
+// Init initializes request
+func (r *SendRequest) Init() error {
+	r.httpOptions = rest.NewHTTPOptions(r.Options)
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *SendRequest) Validate() error {
+	if r.URL == "" {
+		return errors.New("URL was empty")
+	}
+	if strings.TrimSpace(r.Query) == "" {
+		return errors.New("query was empty")
+	}
+	return nil
+}
+
+// payload returns GraphQL over HTTP request payload
+func (r *SendRequest) payload() map[string]interface{} {
+	var result = map[string]interface{}{
+		"query": r.Query,
+	}
+	if r.OperationName != "" {
+		result["operationName"] = r.OperationName
+	}
+	if len(r.Variables) > 0 {
+		result["variables"] = r.Variables
+	}
+	return result
+}
+
+// Location represents GraphQL error location
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error represents GraphQL response error
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Locations  []*Location            `json:"locations,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// PathText returns error path as text, i.e. user.friends[1].name
+func (e *Error) PathText() string {
+	var result = ""
+	for _, element := range e.Path {
+		if toolbox.IsInt(element) || toolbox.IsFloat(element) {
+			result += fmt.Sprintf("[%v]", element)
+			continue
+		}
+		if result != "" {
+			result += "."
+		}
+		result += toolbox.AsString(element)
+	}
+	return result
+}
+
+// SendResponse represents GraphQL response
+type SendResponse struct {
+	Code       int
+	Header     http.Header
+	Data       interface{}
+	Errors     []*Error               `json:",omitempty"`
+	Extensions map[string]interface{} `json:",omitempty"`
+	Assert     *validator.AssertResponse
+	errors     *assertly.Validation
+}
+
+// Assertion returns response errors as validation failures
+func (r *SendResponse) Assertion() []*assertly.Validation {
+	if r == nil || r.errors == nil {
+		return []*assertly.Validation{}
+	}
+	return []*assertly.Validation{r.errors}
+}
+
+// IntrospectRequest represents schema introspection request
+type IntrospectRequest struct {
+	Options     map[string]interface{} `description:"http client options"`
+	httpOptions []*toolbox.HttpOptions
+	URL         string `required:"true" description:"GraphQL endpoint URL"`
+	Header      http.Header
+	Expect      interface{} `description:"If specified it will validated introspection response as actual"`
+}
+
+// Init initializes request
+func (r *IntrospectRequest) Init() error {
+	r.httpOptions = rest.NewHTTPOptions(r.Options)
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *IntrospectRequest) Validate() error {
+	if r.URL == "" {
+		return errors.New("URL was empty")
+	}
+	return nil
+}
+
+// IntrospectResponse represents schema introspection response
+type IntrospectResponse struct {
+	QueryType        string
+	MutationType     string           `json:",omitempty"`
+	SubscriptionType string           `json:",omitempty"`
+	Types            map[string]*Type `description:"user defined types (introspection types excluded) keyed by name"`
+	Schema           interface{}      `description:"raw __schema introspection result"`
+	Assert           *validator.AssertResponse
+}
+
+// Type represents schema type summary
+type Type struct {
+	Kind   string
+	Fields []string `json:",omitempty"` //field or input field names
+	Values []string `json:",omitempty"` //enum values
+}
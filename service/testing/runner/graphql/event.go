@@ -0,0 +1,41 @@
+package graphql
+
+import (
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+)
+
+// Messages returns messages
+func (r *SendRequest) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	response = append(response, msg.NewMessage(msg.NewStyled("POST "+r.URL, msg.MessageStyleGeneric), msg.NewStyled("graphql.SendRequest", msg.MessageStyleGeneric),
+		msg.NewStyled(r.Query, msg.MessageStyleInput),
+	))
+	if len(r.Variables) > 0 {
+		variablesJSON, _ := toolbox.AsJSONText(r.Variables)
+		response = append(response, msg.NewMessage(msg.NewStyled("Variables", msg.MessageStyleGeneric), msg.NewStyled("graphql.SendRequest", msg.MessageStyleGeneric),
+			msg.NewStyled(variablesJSON, msg.MessageStyleInput),
+		))
+	}
+	return response
+}
+
+// Messages returns messages
+func (r *SendResponse) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	responseJSON, _ := toolbox.AsJSONText(r)
+	response = append(response, msg.NewMessage(msg.NewStyled("Response", msg.MessageStyleGeneric), msg.NewStyled("graphql.SendResponse", msg.MessageStyleGeneric),
+		msg.NewStyled(responseJSON, msg.MessageStyleOutput),
+	))
+	return response
+}
+
+// IsInput returns this request (CLI reporter interface)
+func (r *SendRequest) IsInput() bool {
+	return true
+}
+
+// IsOutput returns this response (CLI reporter interface)
+func (r *SendResponse) IsOutput() bool {
+	return true
+}
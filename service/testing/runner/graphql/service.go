@@ -0,0 +1,286 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/assertly"
+	"github.com/viant/endly"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/toolbox"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ServiceID represents GraphQL runner service id.
+const ServiceID = "graphql/runner"
+
+// introspectionQuery represents schema introspection query
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      fields(includeDeprecated: true) { name }
+      inputFields { name }
+      enumValues(includeDeprecated: true) { name }
+    }
+  }
+}`
+
+type service struct {
+	*endly.AbstractService
+}
+
+// graphQLResponse represents GraphQL over HTTP response payload
+type graphQLResponse struct {
+	Data       interface{}            `json:"data"`
+	Errors     []*Error               `json:"errors"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+func (s *service) send(context *endly.Context, request *SendRequest) (*SendResponse, error) {
+	var response = &SendResponse{}
+	payload, err := s.post(request.URL, request.Header, request.payload(), request.httpOptions, response)
+	if err != nil {
+		return response, err
+	}
+	response.Data, response.Errors, response.Extensions = payload.Data, payload.Errors, payload.Extensions
+	if len(response.Errors) > 0 && !request.AllowErrors {
+		response.errors = errorsValidation(response.Errors)
+	}
+	if request.Expect != nil {
+		response.Assert, err = validator.Assert(context, request, request.Expect, asActual(response), "GraphQL.response", "assert GraphQL response")
+	}
+	return response, err
+}
+
+func (s *service) introspect(context *endly.Context, request *IntrospectRequest) (*IntrospectResponse, error) {
+	var response = &IntrospectResponse{Types: make(map[string]*Type)}
+	sendResponse := &SendResponse{}
+	payload, err := s.post(request.URL, request.Header, map[string]interface{}{"query": introspectionQuery}, request.httpOptions, sendResponse)
+	if err != nil {
+		return response, err
+	}
+	if len(payload.Errors) > 0 {
+		return response, fmt.Errorf("failed to introspect %v schema: %v", request.URL, payload.Errors[0].Message)
+	}
+	schema := data(payload.Data, "__schema")
+	if schema == nil {
+		return response, fmt.Errorf("failed to introspect %v schema: __schema was empty", request.URL)
+	}
+	response.Schema = schema
+	schemaMap := toolbox.AsMap(schema)
+	response.QueryType = toolbox.AsString(data(schemaMap["queryType"], "name"))
+	response.MutationType = toolbox.AsString(data(schemaMap["mutationType"], "name"))
+	response.SubscriptionType = toolbox.AsString(data(schemaMap["subscriptionType"], "name"))
+	if types, ok := schemaMap["types"].([]interface{}); ok {
+		for _, item := range types {
+			aMap := toolbox.AsMap(item)
+			name := toolbox.AsString(aMap["name"])
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			aType := &Type{Kind: toolbox.AsString(aMap["kind"])}
+			aType.Fields = append(names(aMap["fields"]), names(aMap["inputFields"])...)
+			aType.Values = names(aMap["enumValues"])
+			response.Types[name] = aType
+		}
+	}
+	if request.Expect != nil {
+		response.Assert, err = validator.Assert(context, request, request.Expect, asActual(response), "GraphQL.schema", "assert GraphQL schema")
+	}
+	return response, err
+}
+
+// post sends GraphQL payload, response payload is decoded regardless of status code since servers report errors with non 200 codes
+func (s *service) post(URL string, header http.Header, payload map[string]interface{}, options []*toolbox.HttpOptions, response *SendResponse) (*graphQLResponse, error) {
+	client, err := toolbox.NewHttpClient(options...)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest, err := http.NewRequest(http.MethodPost, URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		for _, value := range values {
+			httpRequest.Header.Add(name, value)
+		}
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if httpRequest.Header.Get("Accept") == "" {
+		httpRequest.Header.Set("Accept", "application/json")
+	}
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send GraphQL request to %v: %w", URL, err)
+	}
+	defer httpResponse.Body.Close()
+	response.Code = httpResponse.StatusCode
+	response.Header = httpResponse.Header
+	content, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, err
+	}
+	var result = &graphQLResponse{}
+	if err = json.Unmarshal(content, result); err != nil {
+		return nil, fmt.Errorf("failed to decode GraphQL response (%v): %s, %w", httpResponse.StatusCode, content, err)
+	}
+	return result, nil
+}
+
+// errorsValidation converts GraphQL errors into validation failures
+func errorsValidation(errors []*Error) *assertly.Validation {
+	var result = &assertly.Validation{
+		TagID:       "GraphQL",
+		Description: "GraphQL response errors",
+	}
+	for i, item := range errors {
+		path := fmt.Sprintf("errors[%d]", i)
+		if pathText := item.PathText(); pathText != "" {
+			path += "/" + pathText
+		}
+		result.AddFailure(&assertly.Failure{
+			Path:    path,
+			Reason:  "GraphQL error",
+			Actual:  item.Message,
+			Message: fmt.Sprintf("GraphQL error: %v", item.Message),
+		})
+	}
+	return result
+}
+
+// asActual converts response into generic data structure for validation
+func asActual(response interface{}) interface{} {
+	content, err := json.Marshal(response)
+	if err != nil {
+		return response
+	}
+	var result interface{}
+	if err = json.Unmarshal(content, &result); err != nil {
+		return response
+	}
+	return result
+}
+
+func data(source interface{}, key string) interface{} {
+	if source == nil || !toolbox.IsMap(source) {
+		return nil
+	}
+	return toolbox.AsMap(source)[key]
+}
+
+func names(source interface{}) []string {
+	items, ok := source.([]interface{})
+	if !ok {
+		return nil
+	}
+	var result = make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, toolbox.AsString(data(item, "name")))
+	}
+	return result
+}
+
+const sendExample = `{
+  "URL": "http://127.0.0.1:8080/graphql",
+  "Header": {
+    "Authorization": ["Bearer ${token}"]
+  },
+  "Query": "query User($id: ID!) { user(id: $id) { id name } }",
+  "OperationName": "User",
+  "Variables": {
+    "id": "1"
+  },
+  "Expect": {
+    "Data": {
+      "user": {
+        "id": "1",
+        "name": "Bob"
+      }
+    }
+  }
+}`
+
+const introspectExample = `{
+  "URL": "http://127.0.0.1:8080/graphql",
+  "Expect": {
+    "QueryType": "Query",
+    "Types": {
+      "User": {
+        "Kind": "OBJECT",
+        "Fields": ["id", "name"]
+      }
+    }
+  }
+}`
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "send",
+		RequestInfo: &endly.ActionInfo{
+			Description: "send GraphQL query or mutation",
+			Examples: []*endly.UseCase{
+				{
+					Description: "send query",
+					Data:        sendExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &SendRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &SendResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*SendRequest); ok {
+				return s.send(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "introspect",
+		RequestInfo: &endly.ActionInfo{
+			Description: "introspect GraphQL schema",
+			Examples: []*endly.UseCase{
+				{
+					Description: "introspect schema",
+					Data:        introspectExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &IntrospectRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &IntrospectResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*IntrospectRequest); ok {
+				return s.introspect(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+// New creates a new GraphQL runner service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
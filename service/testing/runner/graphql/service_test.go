@@ -0,0 +1,182 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	runner "github.com/viant/endly/service/testing/runner/graphql"
+	"github.com/viant/toolbox"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newGraphQLServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		payload := map[string]interface{}{}
+		_ = json.NewDecoder(request.Body).Decode(&payload)
+		query := toolbox.AsString(payload["query"])
+		var response interface{}
+		switch {
+		case strings.Contains(query, "__schema"):
+			response = map[string]interface{}{"data": map[string]interface{}{"__schema": map[string]interface{}{
+				"queryType":    map[string]interface{}{"name": "Query"},
+				"mutationType": map[string]interface{}{"name": "Mutation"},
+				"types": []interface{}{
+					map[string]interface{}{"kind": "OBJECT", "name": "User", "fields": []interface{}{map[string]interface{}{"name": "id"}, map[string]interface{}{"name": "name"}}},
+					map[string]interface{}{"kind": "ENUM", "name": "Role", "enumValues": []interface{}{map[string]interface{}{"name": "ADMIN"}}},
+					map[string]interface{}{"kind": "OBJECT", "name": "__Type"},
+				},
+			}}}
+		case request.Header.Get("Authorization") != "Bearer abc":
+			writer.WriteHeader(http.StatusUnauthorized)
+			response = map[string]interface{}{"errors": []interface{}{map[string]interface{}{"message": "unauthorized"}}}
+		case payload["operationName"] == "User":
+			variables := toolbox.AsMap(payload["variables"])
+			if toolbox.AsString(variables["id"]) == "1" {
+				response = map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"id": "1", "name": "Bob"}}}
+			} else {
+				response = map[string]interface{}{
+					"data":   map[string]interface{}{"user": nil},
+					"errors": []interface{}{map[string]interface{}{"message": "user not found", "path": []interface{}{"user"}, "locations": []interface{}{map[string]interface{}{"line": 1, "column": 2}}}},
+				}
+			}
+		default:
+			response = map[string]interface{}{"errors": []interface{}{map[string]interface{}{"message": "unknown operation"}}}
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(response)
+	}))
+}
+
+func TestService_Send(t *testing.T) {
+	server := newGraphQLServer()
+	defer server.Close()
+	var useCases = []struct {
+		description   string
+		request       *runner.SendRequest
+		expectCode    int
+		expectData    interface{}
+		expectErrors  []string
+		failedCount   int
+		assertFailed  int
+		assertPassed  int
+		expectedError bool
+	}{
+		{
+			description: "query with variables",
+			request: &runner.SendRequest{
+				Header:        http.Header{"Authorization": []string{"Bearer abc"}},
+				Query:         "query User($id: ID!) { user(id: $id) { id name } }",
+				OperationName: "User",
+				Variables:     map[string]interface{}{"id": 1},
+				Expect:        map[string]interface{}{"Data": map[string]interface{}{"user": map[string]interface{}{"name": "Bob"}}},
+			},
+			expectCode:   200,
+			expectData:   map[string]interface{}{"user": map[string]interface{}{"id": "1", "name": "Bob"}},
+			assertPassed: 1,
+		},
+		{
+			description: "response errors as failures",
+			request: &runner.SendRequest{
+				Header:        http.Header{"Authorization": []string{"Bearer abc"}},
+				Query:         "query User($id: ID!) { user(id: $id) { id name } }",
+				OperationName: "User",
+				Variables:     map[string]interface{}{"id": 2},
+			},
+			expectCode:   200,
+			expectData:   map[string]interface{}{"user": nil},
+			expectErrors: []string{"user not found"},
+			failedCount:  1,
+		},
+		{
+			description: "allowed errors validated with expect",
+			request: &runner.SendRequest{
+				Query:       "{ user(id: 1) { id } }",
+				AllowErrors: true,
+				Expect:      map[string]interface{}{"Code": 401, "Errors": []interface{}{map[string]interface{}{"message": "/authorized/"}}},
+			},
+			expectCode:   401,
+			expectErrors: []string{"unauthorized"},
+			assertPassed: 2,
+		},
+		{
+			description:   "missing query",
+			request:       &runner.SendRequest{},
+			expectedError: true,
+		},
+	}
+
+	manager := endly.New()
+	service, err := manager.Service(runner.ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(toolbox.NewContext())
+		useCase.request.URL = server.URL
+		serviceResponse := service.Run(context, useCase.request)
+		if useCase.expectedError {
+			assert.NotEqual(t, "", serviceResponse.Error, useCase.description)
+			continue
+		}
+		if !assert.Equal(t, "", serviceResponse.Error, useCase.description) {
+			continue
+		}
+		response, ok := serviceResponse.Response.(*runner.SendResponse)
+		if !assert.True(t, ok, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.expectCode, response.Code, useCase.description)
+		assert.EqualValues(t, useCase.expectData, response.Data, useCase.description)
+		var messages = make([]string, 0)
+		for _, item := range response.Errors {
+			messages = append(messages, item.Message)
+		}
+		assert.EqualValues(t, len(useCase.expectErrors), len(messages), useCase.description)
+		for i, message := range useCase.expectErrors {
+			assert.Equal(t, message, messages[i], useCase.description)
+		}
+		failed := 0
+		for _, validation := range response.Assertion() {
+			failed += validation.FailedCount
+		}
+		assert.Equal(t, useCase.failedCount, failed, useCase.description)
+		if useCase.failedCount > 0 {
+			assert.Equal(t, "errors[0]/user", response.Assertion()[0].Failures[0].Path, useCase.description)
+		}
+		if useCase.request.Expect != nil && assert.NotNil(t, response.Assert, useCase.description) {
+			assert.Equal(t, useCase.assertPassed, response.Assert.Validation.PassedCount, useCase.description)
+			assert.Equal(t, useCase.assertFailed, response.Assert.Validation.FailedCount, useCase.description)
+		}
+	}
+}
+
+func TestService_Introspect(t *testing.T) {
+	server := newGraphQLServer()
+	defer server.Close()
+	manager := endly.New()
+	service, err := manager.Service(runner.ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	context := manager.NewContext(toolbox.NewContext())
+	serviceResponse := service.Run(context, &runner.IntrospectRequest{
+		URL:    server.URL,
+		Expect: map[string]interface{}{"QueryType": "Query", "Types": map[string]interface{}{"User": map[string]interface{}{"Kind": "OBJECT"}}},
+	})
+	if !assert.Equal(t, "", serviceResponse.Error) {
+		return
+	}
+	response, ok := serviceResponse.Response.(*runner.IntrospectResponse)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "Query", response.QueryType)
+	assert.Equal(t, "Mutation", response.MutationType)
+	assert.Equal(t, 2, len(response.Types))
+	assert.EqualValues(t, []string{"id", "name"}, response.Types["User"].Fields)
+	assert.EqualValues(t, []string{"ADMIN"}, response.Types["Role"].Values)
+	assert.Equal(t, 0, response.Assert.Validation.FailedCount)
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/viant/endly/internal/descriptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+)
+
+// dial creates client connection
+func dial(request *CallRequest) (*grpc.ClientConn, error) {
+	transport := insecure.NewCredentials()
+	if request.TLS {
+		transport = credentials.NewTLS(&tls.Config{InsecureSkipVerify: request.InsecureSkipVerify})
+	}
+	conn, err := grpc.NewClient(request.Target, grpc.WithTransportCredentials(transport))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v: %w", request.Target, err)
+	}
+	return conn, nil
+}
+
+// lookupMethod returns method descriptor from proto source or server reflection
+func lookupMethod(ctx context.Context, conn *grpc.ClientConn, request *CallRequest) (*desc.MethodDescriptor, error) {
+	if !request.Source.IsEmpty() {
+		files, err := request.Source.Load()
+		if err != nil {
+			return nil, err
+		}
+		return descriptor.FindMethod(files, request.Method)
+	}
+	serviceName, methodName, err := descriptor.SplitMethod(request.Method)
+	if err != nil {
+		return nil, err
+	}
+	client := grpcreflect.NewClientAuto(ctx, conn)
+	defer client.Reset()
+	service, err := client.ResolveService(serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %v with server reflection: %w", serviceName, err)
+	}
+	return descriptor.Method(service, methodName)
+}
+
+// invoke calls unary or server streaming method, the call is bound to both request timeout and parent deadline
+func invoke(parent context.Context, request *CallRequest, response *CallResponse) error {
+	ctx, cancel := context.WithTimeout(parent, time.Duration(request.TimeoutMs)*time.Millisecond)
+	defer cancel()
+	conn, err := dial(request)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	method, err := lookupMethod(ctx, conn, request)
+	if err != nil {
+		return err
+	}
+	if method.IsClientStreaming() {
+		return fmt.Errorf("client streaming method %v is not supported", method.GetFullyQualifiedName())
+	}
+	message, err := descriptor.NewMessage(method.GetInputType(), request.Payload)
+	if err != nil {
+		return err
+	}
+	if len(request.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(request.Metadata))
+	}
+	stub := grpcdynamic.NewStub(conn)
+	if !method.IsServerStreaming() {
+		var header, trailer metadata.MD
+		output, err := stub.InvokeRpc(ctx, method, message, grpc.Header(&header), grpc.Trailer(&trailer))
+		response.Header, response.Trailer = header, trailer
+		if err != nil {
+			return setStatus(response, err)
+		}
+		setStatus(response, nil)
+		response.Response, err = descriptor.AsData(output)
+		return err
+	}
+	stream, err := stub.InvokeRpcServerStream(ctx, method, message)
+	if err != nil {
+		return setStatus(response, err)
+	}
+	response.Responses = make([]interface{}, 0)
+	for {
+		var output protoiface.MessageV1
+		if output, err = stream.RecvMsg(); err != nil {
+			break
+		}
+		item, err := descriptor.AsData(output)
+		if err != nil {
+			return err
+		}
+		response.Responses = append(response.Responses, item)
+	}
+	response.Header, _ = stream.Header()
+	response.Trailer = stream.Trailer()
+	if err == io.EOF {
+		err = nil
+	}
+	return setStatus(response, err)
+}
+
+// setStatus sets response status, it returns non status errors
+func setStatus(response *CallResponse, err error) error {
+	aStatus, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	response.Code = aStatus.Code().String()
+	response.Message = aStatus.Message()
+	return nil
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"errors"
+	"github.com/viant/endly/internal/descriptor"
+	"github.com/viant/endly/service/testing/validator"
+)
+
+// CallRequest represents gRPC method call request
+type CallRequest struct {
+	Target             string `required:"true" description:"server address, i.e. 127.0.0.1:50051"`
+	descriptor.Source  `description:"proto file or descriptor set, if both are empty server reflection is used"`
+	Method             string            `required:"true" description:"fully qualified method name, i.e. package.Service/Method"`
+	Metadata           map[string]string `description:"request metadata"`
+	Payload            interface{}       `description:"request message as JSON text or data structure"`
+	TimeoutMs          int               `description:"call timeout, default 30000"`
+	TLS                bool              `description:"flag to use TLS transport"`
+	InsecureSkipVerify bool              `description:"flag to skip server certificate verification with TLS transport"`
+	Expect             interface{}       `description:"If specified it will validated response as actual, non OK status code is only reported as error without expect"`
+}
+
+// Init initializes request
+func (r *CallRequest) Init() error {
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = 30000
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *CallRequest) Validate() error {
+	if r.Target == "" {
+		return errors.New("target was empty")
+	}
+	if r.Method == "" {
+		return errors.New("method was empty")
+	}
+	return nil
+}
+
+// CallResponse represents gRPC method call response
+type CallResponse struct {
+	Code      string              `description:"status code name, i.e. OK, NotFound"`
+	Message   string              `json:",omitempty" description:"status message"`
+	Header    map[string][]string `json:",omitempty"`
+	Trailer   map[string][]string `json:",omitempty"`
+	Response  interface{}         `json:",omitempty" description:"unary method response message"`
+	Responses []interface{}       `json:",omitempty" description:"server streaming method response messages"`
+	Assert    *validator.AssertResponse
+}
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+)
+
+// Messages returns messages
+func (r *CallRequest) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	payloadJSON, _ := toolbox.AsJSONText(r.Payload)
+	response = append(response, msg.NewMessage(msg.NewStyled(r.Target+" "+r.Method, msg.MessageStyleGeneric), msg.NewStyled("grpc.CallRequest", msg.MessageStyleGeneric),
+		msg.NewStyled(payloadJSON, msg.MessageStyleInput),
+	))
+	return response
+}
+
+// Messages returns messages
+func (r *CallResponse) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	responseJSON, _ := toolbox.AsJSONText(r)
+	response = append(response, msg.NewMessage(msg.NewStyled("Response", msg.MessageStyleGeneric), msg.NewStyled("grpc.CallResponse", msg.MessageStyleGeneric),
+		msg.NewStyled(responseJSON, msg.MessageStyleOutput),
+	))
+	return response
+}
+
+// IsInput returns this request (CLI reporter interface)
+func (r *CallRequest) IsInput() bool {
+	return true
+}
+
+// IsOutput returns this response (CLI reporter interface)
+func (r *CallResponse) IsOutput() bool {
+	return true
+}
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/toolbox"
+	"google.golang.org/grpc/codes"
+)
+
+// ServiceID represents gRPC runner service id.
+const ServiceID = "grpc/runner"
+
+type service struct {
+	*endly.AbstractService
+}
+
+func (s *service) call(context *endly.Context, request *CallRequest) (*CallResponse, error) {
+	var response = &CallResponse{}
+	if request.ProtoFile != "" && len(request.ImportPaths) == 0 {
+		request.ProtoFile = location.NewResource(request.ProtoFile).Path()
+	}
+	if request.DescriptorSet != "" {
+		request.DescriptorSet = location.NewResource(request.DescriptorSet).Path()
+	}
+	if err := invoke(context.Background(), request, response); err != nil {
+		return response, err
+	}
+	if request.Expect == nil {
+		if response.Code != codes.OK.String() {
+			return response, fmt.Errorf("%v failed with %v: %v", request.Method, response.Code, response.Message)
+		}
+		return response, nil
+	}
+	var err error
+	response.Assert, err = validator.Assert(context, request, request.Expect, toolbox.AsMap(response), "gRPC.response", "assert gRPC response")
+	return response, err
+}
+
+const callExample = `{
+  "Target": "127.0.0.1:50051",
+  "ProtoFile": "proto/user.proto",
+  "Method": "user.UserService/GetUser",
+  "Metadata": {
+    "authorization": "Bearer ${token}"
+  },
+  "Payload": {
+    "id": 1
+  },
+  "Expect": {
+    "Code": "OK",
+    "Response": {
+      "name": "Bob"
+    }
+  }
+}`
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "call",
+		RequestInfo: &endly.ActionInfo{
+			Description: "call unary or server streaming gRPC method",
+			Examples: []*endly.UseCase{
+				{
+					Description: "call method",
+					Data:        callExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &CallRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CallResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CallRequest); ok {
+				return s.call(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+// New creates a new gRPC runner service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,136 @@
+package grpc_test
+
+import (
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/descriptor"
+	endpoint "github.com/viant/endly/service/testing/endpoint/grpc"
+	runner "github.com/viant/endly/service/testing/runner/grpc"
+	"github.com/viant/toolbox"
+	"google.golang.org/protobuf/proto"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestService_Call(t *testing.T) {
+	protoFile := path.Join(toolbox.CallerDirectory(3), "../../endpoint/grpc/test/user.proto")
+	files, err := (&descriptor.Source{ProtoFile: protoFile}).Load()
+	if !assert.Nil(t, err) {
+		return
+	}
+	descriptorSet := path.Join(t.TempDir(), "user.protoset")
+	data, err := proto.Marshal(desc.ToFileDescriptorSet(files...))
+	if !assert.Nil(t, err) || !assert.Nil(t, os.WriteFile(descriptorSet, data, 0644)) {
+		return
+	}
+	stubs, err := endpoint.NewStubs(&endpoint.Stub{
+		Method:   "user.UserService/GetUser",
+		Response: map[string]interface{}{"id": "$request.id", "name": "Bob"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	server, err := endpoint.StartServer(7732, files, stubs)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer server.Stop()
+
+	var useCases = []struct {
+		description string
+		source      descriptor.Source
+		method      string
+		expect      interface{}
+		hasError    bool
+	}{
+		{
+			description: "server reflection",
+			method:      "user.UserService/GetUser",
+			expect:      map[string]interface{}{"id": float64(7), "name": "Bob"},
+		},
+		{
+			description: "proto file",
+			source:      descriptor.Source{ProtoFile: protoFile},
+			method:      "/user.UserService/GetUser",
+			expect:      map[string]interface{}{"id": float64(7), "name": "Bob"},
+		},
+		{
+			description: "descriptor set",
+			source:      descriptor.Source{DescriptorSet: descriptorSet},
+			method:      "user.UserService.GetUser",
+			expect:      map[string]interface{}{"id": float64(7), "name": "Bob"},
+		},
+		{
+			description: "unknown method",
+			method:      "user.UserService/DeleteUser",
+			hasError:    true,
+		},
+		{
+			description: "no stub",
+			source:      descriptor.Source{ProtoFile: protoFile},
+			method:      "user.UserService/ListUsers",
+			hasError:    true,
+		},
+	}
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	for _, useCase := range useCases {
+		response := &runner.CallResponse{}
+		err := endly.Run(context, &runner.CallRequest{
+			Target:  "127.0.0.1:7732",
+			Source:  useCase.source,
+			Method:  useCase.method,
+			Payload: map[string]interface{}{"id": 7},
+		}, response)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, "OK", response.Code, useCase.description)
+		assert.EqualValues(t, useCase.expect, response.Response, useCase.description)
+	}
+}
+
+func TestService_CallDeadline(t *testing.T) {
+	protoFile := path.Join(toolbox.CallerDirectory(3), "../../endpoint/grpc/test/user.proto")
+	files, err := (&descriptor.Source{ProtoFile: protoFile}).Load()
+	if !assert.Nil(t, err) {
+		return
+	}
+	stubs, err := endpoint.NewStubs(&endpoint.Stub{
+		Method:   "user.UserService/GetUser",
+		Response: map[string]interface{}{"id": "$request.id", "name": "Bob"},
+		DelayMs:  1000,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	server, err := endpoint.StartServer(7733, files, stubs)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer server.Stop()
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	timedContext, cancel := context.WithTimeout(200 * time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	response := &runner.CallResponse{}
+	err = endly.Run(timedContext, &runner.CallRequest{
+		Target:  "127.0.0.1:7733",
+		Source:  descriptor.Source{ProtoFile: protoFile},
+		Method:  "user.UserService/GetUser",
+		Payload: map[string]interface{}{"id": 7},
+	}, response)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "DeadlineExceeded")
+	}
+	assert.True(t, time.Since(started) < 800*time.Millisecond, "call should be bound to context deadline")
+}
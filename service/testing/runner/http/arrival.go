@@ -0,0 +1,233 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LoadStage represents arrival rate stage, step and spike profiles are expressed as consecutive constant stages
+type LoadStage struct {
+	DurationMs int     `required:"true" description:"stage duration"`
+	RPS        float64 `description:"iterations arrival rate per second"`
+	Ramp       bool    `description:"flag to linearly ramp rate from the previous stage rate (0 for the first stage) to RPS"`
+}
+
+// Validate checks if stage is valid
+func (s *LoadStage) Validate() error {
+	if s.DurationMs <= 0 {
+		return fmt.Errorf("durationMs was empty")
+	}
+	if s.RPS < 0 {
+		return fmt.Errorf("invalid RPS: %v", s.RPS)
+	}
+	return nil
+}
+
+// arrivalScheduler computes iteration arrival offsets, arrival k takes place when cumulative rate integral reaches k
+type arrivalScheduler struct {
+	stages     []*LoadStage
+	index      int
+	stageStart time.Duration
+	stageCount float64 //arrivals before the current stage
+	startRate  float64 //rate at the current stage start
+	arrival    int
+}
+
+// next returns next arrival offset since load test start
+func (s *arrivalScheduler) next() (time.Duration, bool) {
+	for s.index < len(s.stages) {
+		stage := s.stages[s.index]
+		duration := float64(stage.DurationMs) / 1000
+		fromRate := stage.RPS
+		if stage.Ramp {
+			fromRate = s.startRate
+		}
+		stageTotal := (fromRate + stage.RPS) / 2 * duration
+		need := float64(s.arrival) - s.stageCount
+		if need < stageTotal {
+			var offset float64
+			if slope := (stage.RPS - fromRate) / duration; math.Abs(slope) < 1e-9 {
+				offset = need / fromRate
+			} else {
+				offset = (-fromRate + math.Sqrt(fromRate*fromRate+2*slope*need)) / slope
+			}
+			s.arrival++
+			return s.stageStart + time.Duration(offset*float64(time.Second)), true
+		}
+		s.stageCount += stageTotal
+		s.stageStart += time.Duration(stage.DurationMs) * time.Millisecond
+		s.startRate = stage.RPS
+		s.index++
+	}
+	return 0, false
+}
+
+func newArrivalScheduler(stages []*LoadStage) *arrivalScheduler {
+	return &arrivalScheduler{stages: stages}
+}
+
+// arrivalRateTest starts iterations at scheduled arrival rate (open model), each iteration sends all requests sequentially,
+// the first request response time is measured since scheduled arrival to avoid coordinated omission
+func (s *service) arrivalRateTest(context *endly.Context, request *LoadRequest, metrics *runtimeMetric) ([]*stressTestTrip, int, error) {
+	client, err := toolbox.NewHttpClient(s.applyDefaultTimeoutIfNeeded(request.httpOptions)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	var state = context.State()
+	baseState := state.Clone() //iteration state snapshot
+	contexts := make(chan *endly.Context, request.MaxInFlight)
+	created := 0
+	var trips = make([]*stressTestTrip, 0)
+	var mux sync.Mutex
+	var waitGroup sync.WaitGroup
+	var droppedCount int
+	scheduler := newArrivalScheduler(request.Stages)
+	startTime := time.Now()
+	done := context.Background().Done()
+	var interrupted error
+	for iteration := 0; interrupted == nil; iteration++ {
+		offset, ok := scheduler.next()
+		if !ok {
+			break
+		}
+		scheduled := startTime.Add(offset)
+		if interrupted = waitForArrival(context, done, scheduled); interrupted != nil {
+			break
+		}
+		var iterationContext *endly.Context
+		select {
+		case iterationContext = <-contexts:
+		default:
+			if created >= request.MaxInFlight {
+				droppedCount++
+				continue
+			}
+			created++
+			iterationContext = context.Clone()
+		}
+		waitGroup.Add(1)
+		go func(iteration int, iterationContext *endly.Context, scheduled time.Time) {
+			defer func() {
+				contexts <- iterationContext
+				waitGroup.Done()
+			}()
+			iterationContext.SetState(baseState.Clone())
+			iterationTrips := s.runIteration(iterationContext, client, request, metrics, iteration, scheduled)
+			mux.Lock()
+			trips = append(trips, iterationTrips...)
+			mux.Unlock()
+		}(iteration, iterationContext, scheduled)
+	}
+	waitGroup.Wait()
+	if interrupted != nil {
+		return trips, droppedCount, fmt.Errorf("arrival rate test was interrupted: %w", interrupted)
+	}
+	return trips, droppedCount, nil
+}
+
+// waitForArrival waits till scheduled arrival, it returns error if context was closed or its deadline exceeded in the meantime
+func waitForArrival(context *endly.Context, done <-chan struct{}, scheduled time.Time) error {
+	timer := time.NewTimer(time.Until(scheduled))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-done:
+		return context.Background().Err()
+	}
+	if context.IsClosed() {
+		return fmt.Errorf("context was closed")
+	}
+	return nil
+}
+
+// runIteration sends iteration requests sequentially with its own state, cookies and extracted data
+func (s *service) runIteration(context *endly.Context, client *http.Client, request *LoadRequest, metrics *runtimeMetric, iteration int, scheduled time.Time) []*stressTestTrip {
+	var result = make([]*stressTestTrip, 0, len(request.Requests))
+	state := context.State()
+	state.Put("cookies", data.NewMap())
+	trips := newTrips()
+	state.Put(TripsKey, trips)
+	var extracted = data.NewMap()
+	var sessionCookies Cookies = make([]*http.Cookie, 0)
+	expectedResponses := request.expectedResponses()
+	requestTime := scheduled
+	for index, template := range request.Requests {
+		req := *template
+		if req.Repeater != nil {
+			repeater := *req.Repeater
+			req.Repeater = &repeater
+		}
+		req.whenEval = nil
+		canRun, err := criteria.Evaluate(context, state, req.When, &req.whenEval, "HttpRequest.When", true)
+		if err != nil {
+			result = append(result, &stressTestTrip{index: index, err: err, requestTime: time.Now()})
+			return result
+		}
+		if !canRun {
+			continue
+		}
+		trip := &stressTestTrip{
+			index:    index,
+			capture:  len(req.Variables) > 0 || len(req.Extract) > 0 || index+1 < len(request.Requests),
+			expected: iteration%request.AssertMod == 0 && index < len(expectedResponses),
+		}
+		result = append(result, trip)
+		_ = trips.addRequest(&req)
+		if trip.request, trip.expectBinary, err = req.Build(context, sessionCookies); err != nil {
+			trip.err, trip.requestTime = err, time.Now()
+			return result
+		}
+		trip.requestTime = requestTime
+		s.sendTrip(client, metrics, trip)
+		if trip.err != nil || trip.timeout || trip.response == nil {
+			return result
+		}
+		if err = s.extractIterationData(context, &req, trip, trips, extracted, &sessionCookies); err != nil {
+			trip.err = err
+			return result
+		}
+		if req.Repeater != nil && req.ThinkTimeMs > 0 {
+			time.Sleep(time.Duration(req.ThinkTimeMs) * time.Millisecond)
+		}
+		requestTime = time.Now()
+	}
+	return result
+}
+
+// extractIterationData applies request extraction and variables to iteration state
+func (s *service) extractIterationData(context *endly.Context, request *Request, trip *stressTestTrip, trips Trips, extracted data.Map, sessionCookies *Cookies) error {
+	captured := *trip.response
+	captured.Body = io.NopCloser(bytes.NewReader(trip.content))
+	response := NewResponse()
+	response.Merge(&captured, trip.expectBinary)
+	sessionCookies.AddCookies(captured.Cookies()...)
+	if err := response.TransformBodyIfNeeded(context, request); err != nil {
+		return err
+	}
+	if toolbox.IsStructuredJSON(response.Body) {
+		response.JSONBody, _ = toolbox.JSONToInterface(response.Body)
+	}
+	if request.Repeater != nil {
+		var out interface{} = response.Body
+		if request.DataSource == "response" {
+			out = toolbox.AsMap(response)
+		}
+		if _, err := request.Repeater.Eval(context, RunnerID, out, extracted); err != nil {
+			return err
+		}
+		for key, value := range toolbox.AsMap(extracted.Expand(context.State())) {
+			extracted[key] = value
+		}
+	}
+	trips.setData(extracted)
+	return trips.addResponse(response)
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+)
+
+func TestArrivalScheduler_Next(t *testing.T) {
+	var useCases = []struct {
+		description string
+		stages      []*LoadStage
+		expectCount int
+		expectLast  time.Duration
+		expectAt    map[int]time.Duration
+	}{
+		{
+			description: "constant rate",
+			stages:      []*LoadStage{{DurationMs: 1000, RPS: 10}},
+			expectCount: 10,
+			expectAt:    map[int]time.Duration{0: 0, 1: 100 * time.Millisecond, 9: 900 * time.Millisecond},
+		},
+		{
+			description: "linear ramp",
+			stages:      []*LoadStage{{DurationMs: 2000, RPS: 10, Ramp: true}},
+			expectCount: 10,
+			expectAt:    map[int]time.Duration{1: 632455532, 4: 1264911064},
+		},
+		{
+			description: "step and spike",
+			stages:      []*LoadStage{{DurationMs: 1000, RPS: 5}, {DurationMs: 100, RPS: 100}, {DurationMs: 1000, RPS: 5}},
+			expectCount: 20,
+			expectAt:    map[int]time.Duration{5: time.Second, 15: 1100 * time.Millisecond},
+		},
+		{
+			description: "ramp down",
+			stages:      []*LoadStage{{DurationMs: 1000, RPS: 20}, {DurationMs: 1000, RPS: 0, Ramp: true}},
+			expectCount: 30,
+		},
+	}
+	for _, useCase := range useCases {
+		scheduler := newArrivalScheduler(useCase.stages)
+		var offsets []time.Duration
+		for {
+			offset, ok := scheduler.next()
+			if !ok {
+				break
+			}
+			offsets = append(offsets, offset)
+		}
+		assert.EqualValues(t, useCase.expectCount, len(offsets), useCase.description)
+		for i := 1; i < len(offsets); i++ {
+			assert.True(t, offsets[i] >= offsets[i-1], useCase.description)
+		}
+		for i, expect := range useCase.expectAt {
+			assert.InDelta(t, float64(expect), float64(offsets[i]), float64(time.Microsecond), "%v[%v]", useCase.description, i)
+		}
+	}
+}
+
+func TestService_ArrivalRateTest(t *testing.T) {
+	var sessions int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/login":
+			fmt.Fprintf(writer, `{"token":"t%v"}`, atomic.AddInt32(&sessions, 1))
+		case "/profile":
+			body, _ := io.ReadAll(request.Body)
+			fmt.Fprintf(writer, "token:%v", string(body))
+		}
+	}))
+	defer server.Close()
+
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	state := context.State()
+	state.Put("session", "arrival")
+	runner := New().(*service)
+	request := &LoadRequest{
+		SendRequest: &SendRequest{
+			Requests: []*Request{
+				{
+					Method:   "POST",
+					URL:      server.URL + "/login",
+					Repeater: &model.Repeater{Variables: model.Variables{{Name: "token", From: "token"}}},
+				},
+				{
+					When:   "${httpTrips.Data.token}:/t/",
+					Method: "POST",
+					URL:    server.URL + "/profile",
+					Body:   "${httpTrips.Data.token}",
+				},
+			},
+			Expect: map[string]interface{}{
+				"Responses": []interface{}{
+					map[string]interface{}{"Code": 200},
+					map[string]interface{}{"Body": "~/token:t\\d+/"},
+				},
+			},
+		},
+		Stages:    []*LoadStage{{DurationMs: 500, RPS: 40, Ramp: true}, {DurationMs: 250, RPS: 40}},
+		AssertMod: 1,
+		Message:   "",
+	}
+	if !assert.Nil(t, request.Init()) || !assert.Nil(t, request.Validate()) {
+		return
+	}
+	response, err := runner.stressTest(context, request)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 20, sessions)
+	assert.EqualValues(t, 40, response.RequestCount)
+	assert.EqualValues(t, 0, response.ErrorCount)
+	assert.EqualValues(t, 0, response.DroppedCount)
+	assert.EqualValues(t, 0, response.Assert.Validation.FailedCount, response.Assert.Validation.Report())
+	assert.EqualValues(t, 40, response.Assert.Validation.PassedCount)
+	assert.Len(t, response.Templates, 2)
+}
+
+func TestService_ArrivalRateTestDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	defer server.Close()
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	context, cancel := context.WithTimeout(200 * time.Millisecond)
+	defer cancel()
+	runner := New().(*service)
+	request := &LoadRequest{
+		SendRequest: &SendRequest{
+			Requests: []*Request{{Method: "GET", URL: server.URL}},
+		},
+		Stages: []*LoadStage{{DurationMs: 10000, RPS: 10}},
+	}
+	if !assert.Nil(t, request.Init()) || !assert.Nil(t, request.Validate()) {
+		return
+	}
+	started := time.Now()
+	trips, _, err := runner.arrivalRateTest(context, request, &runtimeMetric{})
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(started) < 2*time.Second, "scheduler should stop once deadline is exceeded")
+	assert.True(t, len(trips) > 0 && len(trips) < 10)
+}
@@ -12,13 +12,19 @@ import (
 type SendRequest struct {
 	Options     map[string]interface{} `description:"http client httpOptions: key value pairs, where key is one of the following: HTTP httpOptions:RequestTimeoutMs,TimeoutMs,KeepAliveTimeMs,TLSHandshakeTimeoutMs,ResponseHeaderTimeoutMs,MaxIdleConns,FollowRedirects"`
 	httpOptions []*toolbox.HttpOptions
+	harLoaded   bool
 	Requests    []*Request
+	HAR         string                 `description:"HTTP Archive (HAR) file location, recorded entries are appended to requests"`
+	HARFilter   string                 `description:"regular expression matching HAR entry URL to send, all entries by default"`
+	HARBaseURL  string                 `description:"replaces recorded HAR entry scheme and host, i.e. http://127.0.0.1:8080"`
 	Expect      map[string]interface{} `description:"If specified it will validated response as actual"`
 }
 
 // Init initializes send request
 func (s *SendRequest) Init() error {
-
+	if err := s.loadHAR(); err != nil {
+		return err
+	}
 	if s.Expect == nil {
 		s.Expect = make(map[string]interface{})
 	}
@@ -108,10 +114,14 @@ func NewSendResponseFromURL(URL string) (*SendResponse, error) {
 // LoadRequest represents a send http request.
 type LoadRequest struct {
 	*SendRequest
-	ThreadCount int    `description:"defines number of http client sending request concurrently, default 3"`
-	Repeat      int    `description:"defines how many times repeat individual request, default 1"`
-	AssertMod   int    `description:"defines modulo for assertion on repeated request (make sure you have enough memory)"`
-	Message     string `description:"reporting message during stress test, the following is available: $load.[QPS|Count|Elapsed|Timeouts|Errors|Error]"`
+	ThreadCount int          `description:"defines number of http client sending request concurrently, default 3"`
+	Repeat      int          `description:"defines how many times repeat individual request, default 1"`
+	AssertMod   int          `description:"defines modulo for assertion on repeated request (make sure you have enough memory)"`
+	Message     string       `description:"reporting message during stress test, the following is available: $load.[QPS|Count|Elapsed|Timeouts|Errors|Error]"`
+	Stages      []*LoadStage `description:"arrival rate stages, if specified requests are sent as iterations at scheduled rate instead of ThreadCount workers"`
+	MaxInFlight int          `description:"max concurrent iterations with arrival rate stages, arrivals above are dropped, default 1000"`
+	SLO         *SLO         `description:"service level objective thresholds, action fails if any is exceeded"`
+	Export      string       `description:"optional raw request timings export location, CSV or JSON if location has .json extension"`
 }
 
 func (r *LoadRequest) Init() error {
@@ -121,6 +131,9 @@ func (r *LoadRequest) Init() error {
 	if r.Repeat == 0 {
 		r.Repeat = 1
 	}
+	if err := r.loadHAR(); err != nil {
+		return err
+	}
 	if len(r.Requests) == 0 {
 		return nil
 	}
@@ -138,6 +151,9 @@ func (r *LoadRequest) Init() error {
 	if r.AssertMod == 0 {
 		r.AssertMod = 1024
 	}
+	if len(r.Stages) > 0 && r.MaxInFlight == 0 {
+		r.MaxInFlight = 1000
+	}
 
 	if r.Message == "" {
 		r.Message = " $load.Elapsed: Count: $load.Count, QPS: $load.QPS, Timeouts: $load.Timeouts, Errors: $load.Errors, Error: $load.Error"
@@ -149,21 +165,44 @@ func (r *LoadRequest) Validate() error {
 	if len(r.Requests) == 0 {
 		return fmt.Errorf("requests were empty")
 	}
+	if len(r.Stages) > 0 {
+		for i, stage := range r.Stages {
+			if err := stage.Validate(); err != nil {
+				return fmt.Errorf("invalid stages[%d]: %w", i, err)
+			}
+		}
+		return nil
+	}
 	for _, request := range r.Requests {
 		if request.When != "" {
-			return fmt.Errorf("conditional execution is not supported in stress test mode")
+			return fmt.Errorf("conditional execution is only supported with arrival rate stages")
 		}
 		if len(request.Variables) > 0 {
-			return fmt.Errorf("scraping variables is not supported in stress test mode")
+			return fmt.Errorf("scraping variables is only supported with arrival rate stages")
 		}
 		if len(request.Extract) > 0 {
-			return fmt.Errorf("scraping data is not supported in stress test mode")
+			return fmt.Errorf("scraping data is only supported with arrival rate stages")
 		}
 	}
 
 	return nil
 }
 
+// expectedResponses returns expected responses indexed by request
+func (r *LoadRequest) expectedResponses() []interface{} {
+	if len(r.Expect) == 0 {
+		return nil
+	}
+	responses, ok := r.Expect["Responses"]
+	if !ok {
+		responses, ok = r.Expect["responses"]
+	}
+	if !ok {
+		return nil
+	}
+	return toolbox.AsSlice(responses)
+}
+
 // LoadRequest represents a stress test response
 type LoadResponse struct {
 	SendResponse
@@ -178,4 +217,9 @@ type LoadResponse struct {
 	MinResponseTimeInMs float64
 	AvgResponseTimeInMs float64
 	MaxResponseTimeInMs float64
+	DroppedCount        int `description:"arrivals dropped due to MaxInFlight limit"`
+	Latency             *LatencyStats
+	Templates           []*TemplateStats `description:"response time stats per request template"`
+	TimeSeries          []*SecondStats   `description:"response time stats per second since load test start"`
+	SLOViolations       []string         `json:",omitempty"`
 }
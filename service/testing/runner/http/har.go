@@ -0,0 +1,74 @@
+package http
+
+import (
+	"fmt"
+	"github.com/viant/endly/internal/har"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// harSkippedHeaders represents recorded headers that are managed by http client
+var harSkippedHeaders = []string{"Content-Length", "Host", "Connection", "Accept-Encoding"}
+
+// loadHAR appends requests from HAR entries matching filter
+func (s *SendRequest) loadHAR() error {
+	if s.HAR == "" || s.harLoaded {
+		return nil
+	}
+	document, err := har.Load(s.HAR)
+	if err != nil {
+		return err
+	}
+	var filter *regexp.Regexp
+	if s.HARFilter != "" {
+		if filter, err = regexp.Compile(s.HARFilter); err != nil {
+			return fmt.Errorf("invalid HAR filter %v, %w", s.HARFilter, err)
+		}
+	}
+	var baseURL *url.URL
+	if s.HARBaseURL != "" {
+		if baseURL, err = url.Parse(s.HARBaseURL); err != nil {
+			return fmt.Errorf("invalid HAR base URL %v, %w", s.HARBaseURL, err)
+		}
+	}
+	for _, entry := range document.Log.Entries {
+		if entry.Request == nil || (filter != nil && !filter.MatchString(entry.Request.URL)) {
+			continue
+		}
+		request := newRequestFromHAR(entry.Request)
+		if baseURL != nil {
+			if request.URL, err = rebaseURL(request.URL, baseURL); err != nil {
+				return err
+			}
+		}
+		s.Requests = append(s.Requests, request)
+	}
+	s.harLoaded = true
+	return nil
+}
+
+func newRequestFromHAR(request *har.Request) *Request {
+	header := request.Header()
+	for _, key := range harSkippedHeaders {
+		header.Del(key)
+	}
+	if len(header) == 0 {
+		header = http.Header{}
+	}
+	return &Request{
+		Method: request.Method,
+		URL:    request.URL,
+		Header: header,
+		Body:   request.Body(),
+	}
+}
+
+func rebaseURL(URL string, baseURL *url.URL) (string, error) {
+	result, err := url.Parse(URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid HAR entry URL %v, %w", URL, err)
+	}
+	result.Scheme, result.Host = baseURL.Scheme, baseURL.Host
+	return result.String(), nil
+}
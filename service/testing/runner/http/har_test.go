@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/internal/har"
+	"github.com/viant/toolbox/bridge"
+)
+
+func TestNewSendRequestFromHAR(t *testing.T) {
+	filename := path.Join(t.TempDir(), "trips.har")
+	recorder := har.NewRecorder(filename, "endly", "test")
+	for _, URL := range []string{"http://localhost:8080/api/users", "http://localhost:8080/static/app.js", "http://localhost:8080/api/orders"} {
+		request := &bridge.HttpRequest{Method: "GET", URL: URL, Header: http.Header{"Accept": {"*/*"}, "Content-Length": {"0"}}}
+		response := &bridge.HttpResponse{Code: 200, Header: http.Header{}}
+		recorder.Append(har.NewEntry(time.Now(), 0, request, response))
+	}
+	if !assert.Nil(t, recorder.Close()) {
+		return
+	}
+
+	var useCases = []struct {
+		description string
+		filter      string
+		baseURL     string
+		expect      []string
+		hasError    bool
+	}{
+		{description: "all entries", expect: []string{"http://localhost:8080/api/users", "http://localhost:8080/static/app.js", "http://localhost:8080/api/orders"}},
+		{description: "filtered entries", filter: "/api/", expect: []string{"http://localhost:8080/api/users", "http://localhost:8080/api/orders"}},
+		{description: "rebased entries", filter: "orders", baseURL: "https://127.0.0.1:9090", expect: []string{"https://127.0.0.1:9090/api/orders"}},
+		{description: "invalid filter", filter: "[", hasError: true},
+	}
+	for _, useCase := range useCases {
+		request := &SendRequest{HAR: filename, HARFilter: useCase.filter, HARBaseURL: useCase.baseURL}
+		err := request.Init()
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		var actual []string
+		for _, req := range request.Requests {
+			actual = append(actual, req.URL)
+			assert.EqualValues(t, "*/*", req.Header.Get("Accept"), useCase.description)
+			assert.EqualValues(t, "", req.Header.Get("Content-Length"), useCase.description)
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+		assert.Nil(t, request.Init(), useCase.description) //HAR is loaded once
+		assert.Len(t, request.Requests, len(useCase.expect), useCase.description)
+	}
+}
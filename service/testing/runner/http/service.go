@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"github.com/viant/afs"
 	"github.com/viant/assertly"
 	"github.com/viant/endly"
 	"github.com/viant/endly/model/criteria"
@@ -162,8 +163,12 @@ func (s *service) handleRequest(client *http.Client, metric *runtimeMetric, trip
 	defer func() {
 		trip.waitGroup.Done()
 	}()
-
 	trip.requestTime = time.Now()
+	s.sendTrip(client, metric, trip)
+}
+
+// sendTrip sends trip request, response time is measured since trip request time
+func (s *service) sendTrip(client *http.Client, metric *runtimeMetric, trip *stressTestTrip) {
 	var response *http.Response
 	var err error
 	if atomic.LoadInt64(&metric.startTime) == 0 {
@@ -193,12 +198,14 @@ func (s *service) handleRequest(client *http.Client, metric *runtimeMetric, trip
 	if trip.err != nil || trip.timeout || response == nil {
 		return
 	}
+	trip.statusCode = response.StatusCode
 	var content []byte
-	if response.ContentLength > 0 {
+	if response.ContentLength > 0 || (trip.capture && response.ContentLength != 0) {
 		content, err = ioutil.ReadAll(response.Body)
 	}
 
-	if trip.expected {
+	if trip.expected || trip.capture {
+		trip.content = content
 		trip.response = &http.Response{
 			Header:        response.Header,
 			Status:        response.Status,
@@ -235,30 +242,39 @@ type runtimeMetric struct {
 }
 
 func (s *service) stressTest(context *endly.Context, request *LoadRequest) (*LoadResponse, error) {
-	var waitGroup = &sync.WaitGroup{}
-	capacity := 1024 * request.ThreadCount
-	var sendChannel = make(chan *stressTestTrip, capacity)
 	var done uint32 = 0
 	metrics := &runtimeMetric{}
-
 	go s.emitMetrics(context, metrics, &done, request.Message)
-	if _, err := s.initClients(request, sendChannel, metrics, &done); err != nil {
-		return nil, err
+	var trips []*stressTestTrip
+	var droppedCount int
+	var err error
+	if len(request.Stages) > 0 {
+		trips, droppedCount, err = s.arrivalRateTest(context, request, metrics)
+	} else {
+		trips, err = s.closedModelTest(context, request, metrics, &done)
 	}
-	partialTrips := newPartialStressTrips(capacity, sendChannel, waitGroup)
-	trips, err := buildStressTestTrip(request, context, partialTrips)
+	atomic.StoreUint32(&done, 1)
 	if err != nil {
 		return nil, err
 	}
-	waitGroup.Wait()
-	atomic.StoreUint32(&done, 1)
+	if len(trips) == 0 {
+		return nil, fmt.Errorf("no requests were sent, dropped: %v", droppedCount)
+	}
 	var response = &LoadResponse{
-		Status: "ok",
+		Status:       "ok",
+		DroppedCount: droppedCount,
 	}
 
 	if err = collectTripResponses(trips, response, request); err != nil {
 		return nil, err
 	}
+	startTime := tripsStartTime(trips)
+	collectLatencyStats(trips, request, startTime, response)
+	if request.Export != "" {
+		if err = exportTimings(afs.New(), context.Expand(request.Export), trips, startTime); err != nil {
+			return nil, err
+		}
+	}
 
 	response.Assert = &validator.AssertResponse{Validation: &assertly.Validation{}}
 	var actual = make([]interface{}, 0)
@@ -295,9 +311,32 @@ func (s *service) stressTest(context *endly.Context, request *LoadRequest) (*Loa
 		}
 		response.Assert, err = validator.Assert(context, request, expected, actual, "HTTP.Responses", "assert http responses")
 	}
+	if err == nil && request.SLO != nil {
+		if response.SLOViolations = request.SLO.Violations(response.Latency, response.QPS); len(response.SLOViolations) > 0 {
+			response.Status = "error"
+			err = fmt.Errorf("SLO violated: %v", strings.Join(response.SLOViolations, ", "))
+		}
+	}
 	return response, err
 }
 
+// closedModelTest sends all trips with ThreadCount workers as fast as possible
+func (s *service) closedModelTest(context *endly.Context, request *LoadRequest, metrics *runtimeMetric, done *uint32) ([]*stressTestTrip, error) {
+	var waitGroup = &sync.WaitGroup{}
+	capacity := 1024 * request.ThreadCount
+	var sendChannel = make(chan *stressTestTrip, capacity)
+	if _, err := s.initClients(request, sendChannel, metrics, done); err != nil {
+		return nil, err
+	}
+	partialTrips := newPartialStressTrips(capacity, sendChannel, waitGroup)
+	trips, err := buildStressTestTrip(request, context, partialTrips)
+	if err != nil {
+		return nil, err
+	}
+	waitGroup.Wait()
+	return trips, nil
+}
+
 func collectTripResponses(trips []*stressTestTrip, response *LoadResponse, request *LoadRequest) error {
 	startTime := trips[0].requestTime
 	endTime := trips[0].responseTime
@@ -352,6 +391,9 @@ type stressTestTrip struct {
 	request      *http.Request
 	response     *http.Response
 	expected     bool
+	capture      bool //keeps response for data extraction
+	content      []byte
+	statusCode   int
 	waitGroup    *sync.WaitGroup
 	requestTime  time.Time
 	responseTime time.Time
@@ -363,17 +405,7 @@ func buildStressTestTrip(request *LoadRequest, context *endly.Context, partials
 	var err error
 	var trips = make([]*stressTestTrip, 0)
 
-	var expectedResponses []interface{}
-	if len(request.Expect) > 0 {
-		responses, ok := request.Expect["Responses"]
-		if !ok {
-			responses, ok = request.Expect["responses"]
-		}
-		if ok {
-			expectedResponses = toolbox.AsSlice(responses)
-		}
-	}
-
+	var expectedResponses = request.expectedResponses()
 	for index, req := range request.Requests {
 		var state = context.State()
 		req.Expand(state)
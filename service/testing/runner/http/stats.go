@@ -0,0 +1,344 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//histogramSubBuckets represents linear sub buckets per power of two, keeps relative error below 1%
+	histogramSubBuckets     = 256
+	histogramHalfSubBuckets = histogramSubBuckets / 2
+)
+
+// histogram represents HDR style log-linear latency histogram with microsecond resolution
+type histogram struct {
+	counts map[int]uint64
+	count  uint64
+	sum    float64
+	min    int64
+	max    int64
+}
+
+func (h *histogram) record(value time.Duration) {
+	us := value.Microseconds()
+	if us < 0 {
+		us = 0
+	}
+	h.counts[bucketIndex(us)]++
+	if h.count == 0 || us < h.min {
+		h.min = us
+	}
+	if us > h.max {
+		h.max = us
+	}
+	h.count++
+	h.sum += float64(us)
+}
+
+// percentiles returns supplied percentiles in ms
+func (h *histogram) percentiles(percentiles ...float64) []float64 {
+	var result = make([]float64, len(percentiles))
+	if h.count == 0 {
+		return result
+	}
+	var indexes = make([]int, 0, len(h.counts))
+	for index := range h.counts {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for i, percentile := range percentiles {
+		target := uint64(math.Ceil(percentile / 100 * float64(h.count)))
+		if target == 0 {
+			target = 1
+		}
+		var cumulative uint64
+		for _, index := range indexes {
+			cumulative += h.counts[index]
+			if cumulative >= target {
+				value := bucketUpperBound(index)
+				if value > h.max {
+					value = h.max
+				}
+				result[i] = asMs(value)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (h *histogram) merge(source *histogram) {
+	for index, count := range source.counts {
+		h.counts[index] += count
+	}
+	if source.count > 0 && (h.count == 0 || source.min < h.min) {
+		h.min = source.min
+	}
+	if source.max > h.max {
+		h.max = source.max
+	}
+	h.count += source.count
+	h.sum += source.sum
+}
+
+func newHistogram() *histogram {
+	return &histogram{counts: make(map[int]uint64)}
+}
+
+func bucketIndex(us int64) int {
+	if us < histogramSubBuckets {
+		return int(us)
+	}
+	exponent := bits.Len64(uint64(us)) - bits.Len64(histogramSubBuckets-1)
+	sub := int(us >> uint(exponent))
+	return histogramSubBuckets + (exponent-1)*histogramHalfSubBuckets + sub - histogramHalfSubBuckets
+}
+
+func bucketUpperBound(index int) int64 {
+	if index < histogramSubBuckets {
+		return int64(index)
+	}
+	offset := index - histogramSubBuckets
+	exponent := offset/histogramHalfSubBuckets + 1
+	sub := int64(offset%histogramHalfSubBuckets + histogramHalfSubBuckets)
+	return ((sub + 1) << uint(exponent)) - 1
+}
+
+func asMs(us int64) float64 {
+	return float64(us) / 1000
+}
+
+// LatencyStats represents response time distribution
+type LatencyStats struct {
+	Count        int
+	ErrorCount   int
+	TimeoutCount int
+	ErrorRate    float64 `description:"errors and timeouts to count ratio"`
+	MinMs        float64
+	MeanMs       float64
+	MaxMs        float64
+	P50Ms        float64
+	P90Ms        float64
+	P95Ms        float64
+	P99Ms        float64
+	P999Ms       float64
+}
+
+// TemplateStats represents response time distribution of a request template
+type TemplateStats struct {
+	Index  int
+	Method string
+	URL    string
+	LatencyStats
+}
+
+// SecondStats represents response time distribution of requests sent within a second since load test start
+type SecondStats struct {
+	Second int
+	LatencyStats
+}
+
+// SLO represents service level objective thresholds, zero value threshold is not checked
+type SLO struct {
+	P50Ms     float64 `description:"p50 response time has to be below"`
+	P90Ms     float64 `description:"p90 response time has to be below"`
+	P95Ms     float64 `description:"p95 response time has to be below"`
+	P99Ms     float64 `description:"p99 response time has to be below"`
+	P999Ms    float64 `description:"p99.9 response time has to be below"`
+	MaxMs     float64 `description:"max response time has to be below"`
+	ErrorRate float64 `description:"errors and timeouts ratio has to be below, i.e. 0.005 for 0.5%"`
+	MinQPS    float64 `description:"QPS has to be at least"`
+}
+
+// Violations returns SLO violations for supplied stats
+func (s *SLO) Violations(stats *LatencyStats, QPS float64) []string {
+	var result = make([]string, 0)
+	var thresholds = []struct {
+		name      string
+		actual    float64
+		threshold float64
+	}{
+		{"p50", stats.P50Ms, s.P50Ms},
+		{"p90", stats.P90Ms, s.P90Ms},
+		{"p95", stats.P95Ms, s.P95Ms},
+		{"p99", stats.P99Ms, s.P99Ms},
+		{"p99.9", stats.P999Ms, s.P999Ms},
+		{"max", stats.MaxMs, s.MaxMs},
+	}
+	for _, candidate := range thresholds {
+		if candidate.threshold > 0 && candidate.actual >= candidate.threshold {
+			result = append(result, fmt.Sprintf("%v %vms >= %vms", candidate.name, candidate.actual, candidate.threshold))
+		}
+	}
+	if s.ErrorRate > 0 && stats.ErrorRate >= s.ErrorRate {
+		result = append(result, fmt.Sprintf("error rate %.4f >= %v", stats.ErrorRate, s.ErrorRate))
+	}
+	if s.MinQPS > 0 && QPS < s.MinQPS {
+		result = append(result, fmt.Sprintf("QPS %.1f < %v", QPS, s.MinQPS))
+	}
+	return result
+}
+
+// latencyCollector represents response time stats collector
+type latencyCollector struct {
+	histogram    *histogram
+	errorCount   int
+	timeoutCount int
+}
+
+func (c *latencyCollector) add(trip *stressTestTrip) {
+	if trip.err != nil {
+		c.errorCount++
+	}
+	if trip.timeout {
+		c.timeoutCount++
+	}
+	c.histogram.record(trip.elapsed)
+}
+
+func (c *latencyCollector) merge(source *latencyCollector) {
+	c.histogram.merge(source.histogram)
+	c.errorCount += source.errorCount
+	c.timeoutCount += source.timeoutCount
+}
+
+func (c *latencyCollector) stats() LatencyStats {
+	h := c.histogram
+	result := LatencyStats{
+		Count:        int(h.count),
+		ErrorCount:   c.errorCount,
+		TimeoutCount: c.timeoutCount,
+	}
+	if h.count == 0 {
+		return result
+	}
+	result.ErrorRate = float64(c.errorCount+c.timeoutCount) / float64(h.count)
+	result.MinMs = asMs(h.min)
+	result.MaxMs = asMs(h.max)
+	result.MeanMs = math.Round(h.sum/float64(h.count)) / 1000
+	percentiles := h.percentiles(50, 90, 95, 99, 99.9)
+	result.P50Ms, result.P90Ms, result.P95Ms, result.P99Ms, result.P999Ms = percentiles[0], percentiles[1], percentiles[2], percentiles[3], percentiles[4]
+	return result
+}
+
+func newLatencyCollector() *latencyCollector {
+	return &latencyCollector{histogram: newHistogram()}
+}
+
+func tripsStartTime(trips []*stressTestTrip) time.Time {
+	var result time.Time
+	for _, trip := range trips {
+		if result.IsZero() || trip.requestTime.Before(result) {
+			result = trip.requestTime
+		}
+	}
+	return result
+}
+
+// collectLatencyStats builds overall, per request template and per second response time stats
+func collectLatencyStats(trips []*stressTestTrip, request *LoadRequest, startTime time.Time, response *LoadResponse) {
+	var templates = make(map[int]*latencyCollector)
+	var seconds = make(map[int]*latencyCollector)
+	for _, trip := range trips {
+		if _, ok := templates[trip.index]; !ok {
+			templates[trip.index] = newLatencyCollector()
+		}
+		templates[trip.index].add(trip)
+		second := int(trip.requestTime.Sub(startTime) / time.Second)
+		if _, ok := seconds[second]; !ok {
+			seconds[second] = newLatencyCollector()
+		}
+		seconds[second].add(trip)
+	}
+	total := newLatencyCollector()
+	response.Templates = make([]*TemplateStats, 0, len(templates))
+	for index := 0; index < len(request.Requests); index++ {
+		collector, ok := templates[index]
+		if !ok {
+			continue
+		}
+		total.merge(collector)
+		template := request.Requests[index]
+		response.Templates = append(response.Templates, &TemplateStats{Index: index, Method: template.Method, URL: template.URL, LatencyStats: collector.stats()})
+	}
+	stats := total.stats()
+	response.Latency = &stats
+	var keys = make([]int, 0, len(seconds))
+	for second := range seconds {
+		keys = append(keys, second)
+	}
+	sort.Ints(keys)
+	response.TimeSeries = make([]*SecondStats, 0, len(keys))
+	for _, second := range keys {
+		response.TimeSeries = append(response.TimeSeries, &SecondStats{Second: second, LatencyStats: seconds[second].stats()})
+	}
+}
+
+// timingRecord represents raw request timing
+type timingRecord struct {
+	OffsetMs   float64 `json:"offsetMs"`
+	Index      int     `json:"index"`
+	ElapsedMs  float64 `json:"elapsedMs"`
+	StatusCode int     `json:"statusCode,omitempty"`
+	Timeout    bool    `json:"timeout,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// exportTimings writes raw request timings as CSV or JSON (if URL has .json extension)
+func exportTimings(fs afs.Service, URL string, trips []*stressTestTrip, startTime time.Time) error {
+	var records = make([]*timingRecord, 0, len(trips))
+	for _, trip := range trips {
+		record := &timingRecord{
+			OffsetMs:   asMs(trip.requestTime.Sub(startTime).Microseconds()),
+			Index:      trip.index,
+			ElapsedMs:  asMs(trip.elapsed.Microseconds()),
+			StatusCode: trip.statusCode,
+			Timeout:    trip.timeout,
+		}
+		if trip.err != nil {
+			record.Error = trip.err.Error()
+		}
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].OffsetMs < records[j].OffsetMs
+	})
+	buffer := new(bytes.Buffer)
+	if strings.HasSuffix(strings.ToLower(URL), ".json") {
+		if err := json.NewEncoder(buffer).Encode(records); err != nil {
+			return err
+		}
+	} else {
+		writer := csv.NewWriter(buffer)
+		_ = writer.Write([]string{"offsetMs", "index", "elapsedMs", "statusCode", "timeout", "error"})
+		for _, record := range records {
+			_ = writer.Write([]string{
+				strconv.FormatFloat(record.OffsetMs, 'f', 3, 64),
+				strconv.Itoa(record.Index),
+				strconv.FormatFloat(record.ElapsedMs, 'f', 3, 64),
+				strconv.Itoa(record.StatusCode),
+				strconv.FormatBool(record.Timeout),
+				record.Error,
+			})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	}
+	if err := fs.Upload(context.Background(), URL, 0644, buffer); err != nil {
+		return fmt.Errorf("failed to export timings to %v, %w", URL, err)
+	}
+	return nil
+}
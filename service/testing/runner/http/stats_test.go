@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+)
+
+func TestHistogram_Percentiles(t *testing.T) {
+	h := newHistogram()
+	for i := 1; i <= 10000; i++ {
+		h.record(time.Duration(i) * time.Microsecond * 100) //0.1ms .. 1000ms
+	}
+	var useCases = []struct {
+		percentile float64
+		expectMs   float64
+	}{
+		{percentile: 50, expectMs: 500},
+		{percentile: 90, expectMs: 900},
+		{percentile: 99, expectMs: 990},
+		{percentile: 99.9, expectMs: 999},
+		{percentile: 100, expectMs: 1000},
+	}
+	for _, useCase := range useCases {
+		actual := h.percentiles(useCase.percentile)[0]
+		assert.InEpsilon(t, useCase.expectMs, actual, 0.01, "p%v", useCase.percentile)
+		assert.True(t, actual >= useCase.expectMs, "p%v has to be upper bound: %v", useCase.percentile, actual)
+	}
+	for _, us := range []int64{0, 255, 256, 511, 512, 1 << 20, 1<<40 + 12345} {
+		assert.True(t, bucketUpperBound(bucketIndex(us)) >= us, us)
+		assert.True(t, bucketIndex(us+1) >= bucketIndex(us), us)
+	}
+}
+
+func TestCollectLatencyStats(t *testing.T) {
+	request := &LoadRequest{SendRequest: &SendRequest{Requests: []*Request{
+		{Method: "GET", URL: "http://localhost/a"},
+		{Method: "POST", URL: "http://localhost/b"},
+	}}}
+	startTime := time.Now()
+	var trips []*stressTestTrip
+	for i := 0; i < 100; i++ {
+		trip := &stressTestTrip{
+			index:       i % 2,
+			requestTime: startTime.Add(time.Duration(i) * 20 * time.Millisecond),
+			elapsed:     time.Duration(1+i%2*9) * time.Millisecond, //a: 1ms, b: 10ms
+			statusCode:  200,
+		}
+		if i == 99 {
+			trip.err, trip.statusCode = errors.New("connection refused"), 0
+		}
+		trips = append(trips, trip)
+	}
+	response := &LoadResponse{QPS: 50}
+	collectLatencyStats(trips, request, startTime, response)
+
+	assert.EqualValues(t, 100, response.Latency.Count)
+	assert.EqualValues(t, 1, response.Latency.ErrorCount)
+	assert.EqualValues(t, 0.01, response.Latency.ErrorRate)
+	assert.EqualValues(t, 1, response.Latency.MinMs)
+	assert.EqualValues(t, 10, response.Latency.MaxMs)
+	assert.EqualValues(t, 10, response.Latency.P99Ms)
+	if assert.Len(t, response.Templates, 2) {
+		assert.EqualValues(t, "http://localhost/b", response.Templates[1].URL)
+		assert.EqualValues(t, 1, response.Templates[0].P99Ms)
+		assert.EqualValues(t, 10, response.Templates[1].P50Ms)
+	}
+	if assert.Len(t, response.TimeSeries, 2) {
+		assert.EqualValues(t, 1, response.TimeSeries[1].Second)
+		assert.EqualValues(t, 50, response.TimeSeries[0].Count)
+	}
+
+	var useCases = []struct {
+		description string
+		slo         *SLO
+		expect      []string
+	}{
+		{description: "within SLO", slo: &SLO{P99Ms: 250, ErrorRate: 0.05, MinQPS: 10}, expect: []string{}},
+		{description: "latency violation", slo: &SLO{P50Ms: 1, P99Ms: 10}, expect: []string{"p50 1.003ms >= 1ms", "p99 10ms >= 10ms"}},
+		{description: "error rate and QPS violation", slo: &SLO{ErrorRate: 0.005, MinQPS: 100}, expect: []string{"error rate 0.0100 >= 0.005", "QPS 50.0 < 100"}},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, useCase.slo.Violations(response.Latency, response.QPS), useCase.description)
+	}
+}
+
+func TestExportTimings(t *testing.T) {
+	startTime := time.Now()
+	trips := []*stressTestTrip{
+		{index: 1, requestTime: startTime.Add(time.Millisecond), elapsed: 2 * time.Millisecond, statusCode: 200},
+		{index: 0, requestTime: startTime, elapsed: time.Millisecond, timeout: true},
+	}
+	fs := afs.New()
+	var useCases = []struct {
+		URL    string
+		expect string
+	}{
+		{URL: "mem://localhost/load/timings.csv", expect: "offsetMs,index,elapsedMs,statusCode,timeout,error\n0.000,0,1.000,0,true,\n1.000,1,2.000,200,false,\n"},
+		{URL: "mem://localhost/load/timings.json", expect: `[{"offsetMs":0,"index":0,"elapsedMs":1,"timeout":true},{"offsetMs":1,"index":1,"elapsedMs":2,"statusCode":200}]` + "\n"},
+	}
+	for _, useCase := range useCases {
+		if !assert.Nil(t, exportTimings(fs, useCase.URL, trips, startTime), useCase.URL) {
+			continue
+		}
+		data, err := fs.DownloadWithURL(context.Background(), useCase.URL)
+		assert.Nil(t, err, useCase.URL)
+		assert.EqualValues(t, useCase.expect, strings.TrimLeft(string(data), " "), useCase.URL)
+	}
+}
@@ -20,15 +20,19 @@ type Request struct {
 }
 
 func (r *Request) Init() error {
-	r.httpOptions = make([]*toolbox.HttpOptions, 0)
-	if len(r.Options) > 0 {
-		for k, v := range r.Options {
-			r.httpOptions = append(r.httpOptions, &toolbox.HttpOptions{Key: k, Value: v})
-		}
-	}
+	r.httpOptions = NewHTTPOptions(r.Options)
 	return nil
 }
 
+// NewHTTPOptions creates http client options from key value pairs
+func NewHTTPOptions(options map[string]interface{}) []*toolbox.HttpOptions {
+	var result = make([]*toolbox.HttpOptions, 0)
+	for k, v := range options {
+		result = append(result, &toolbox.HttpOptions{Key: k, Value: v})
+	}
+	return result
+}
+
 // Response represents a rest response
 type Response struct {
 	Response interface{}
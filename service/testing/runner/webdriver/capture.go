@@ -634,16 +634,20 @@ func getResponseBody(sess *Session, requestID string, maxBytes int) (data string
 	if sess == nil || sess.driver == nil || sess.Remote == "" {
 		return "", "", false, errors.New("missing session remote")
 	}
-	wdSession := sess.driver.SessionID()
-	if wdSession == "" {
-		return "", "", false, errors.New("missing webdriver session id")
-	}
-
 	type result struct {
 		Body          string `json:"body"`
 		Base64Encoded bool   `json:"base64Encoded"`
 	}
-	raw, err := cdpExecute(sess.Remote, wdSession, "Network.getResponseBody", map[string]any{"requestId": requestID})
+	var raw json.RawMessage
+	if driver, ok := sess.driver.(*cdpDriver); ok {
+		raw, err = driver.Execute("Network.getResponseBody", map[string]interface{}{"requestId": requestID})
+	} else {
+		wdSession := sess.driver.SessionID()
+		if wdSession == "" {
+			return "", "", false, errors.New("missing webdriver session id")
+		}
+		raw, err = cdpExecute(sess.Remote, wdSession, "Network.getResponseBody", map[string]any{"requestId": requestID})
+	}
 	if err != nil {
 		return "", "", false, err
 	}
@@ -33,10 +33,10 @@ func (s *service) captureStart(context *endly.Context, request *CaptureStartRequ
 		sess.Remote = fmt.Sprintf("http://%v:%v/wd/hub", host, port)
 	}
 
-	// Best-effort CDP enable (Chrome/Edge chromedriver only).
+	// Best-effort CDP enable (Chrome/Edge chromedriver only), cdp backend has network and runtime domains enabled.
 	if strings.EqualFold(sess.Browser, ChromeBrowser) {
 		wdSession := sess.driver.SessionID()
-		if wdSession != "" {
+		if wdSession != "" && sess.Backend != BackendCDP {
 			_, _ = cdpExecute(sess.Remote, wdSession, "Network.enable", map[string]any{})
 			_, _ = cdpExecute(sess.Remote, wdSession, "Runtime.enable", map[string]any{})
 		}
@@ -71,7 +71,7 @@ func (s *service) captureStop(context *endly.Context, request *CaptureStopReques
 		sess.Capture.Drain(sess)
 		_ = sess.Capture.CloseSink()
 	}
-	if sess.driver != nil && sess.Remote != "" && strings.EqualFold(sess.Browser, ChromeBrowser) {
+	if sess.driver != nil && sess.Remote != "" && strings.EqualFold(sess.Browser, ChromeBrowser) && sess.Backend != BackendCDP {
 		wdSession := sess.driver.SessionID()
 		if wdSession != "" {
 			_, _ = cdpExecute(sess.Remote, wdSession, "Network.disable", map[string]any{})
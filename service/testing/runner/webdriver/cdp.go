@@ -0,0 +1,180 @@
+package webdriver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const defaultCDPCallTimeout = 60 * time.Second
+
+// cdpError represents DevTools protocol error
+type cdpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (e *cdpError) Error() string {
+	if e.Data != "" {
+		return fmt.Sprintf("%v (%v): %v", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("%v (%v)", e.Message, e.Code)
+}
+
+// cdpMessage represents DevTools protocol command, result or event
+type cdpMessage struct {
+	ID        int64           `json:"id,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *cdpError       `json:"error,omitempty"`
+}
+
+// cdpConn represents DevTools protocol connection, commands are sent to a browser or to an attached target with flatten session id
+type cdpConn struct {
+	conn     *websocket.Conn
+	sequence int64
+	writeMux sync.Mutex
+	mux      sync.Mutex
+	pending  map[int64]chan *cdpMessage
+	handler  func(message *cdpMessage)
+	closed   chan struct{}
+	err      error
+}
+
+// call sends command and decodes its result into result if not nil
+func (c *cdpConn) call(sessionID, method string, params interface{}, result interface{}) error {
+	message := &cdpMessage{ID: atomic.AddInt64(&c.sequence, 1), SessionID: sessionID, Method: method}
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		message.Params = encoded
+	}
+	reply := make(chan *cdpMessage, 1)
+	c.mux.Lock()
+	if c.err != nil {
+		c.mux.Unlock()
+		return fmt.Errorf("%v: %w", method, c.err)
+	}
+	c.pending[message.ID] = reply
+	c.mux.Unlock()
+	defer func() {
+		c.mux.Lock()
+		delete(c.pending, message.ID)
+		c.mux.Unlock()
+	}()
+	c.writeMux.Lock()
+	err := c.conn.WriteJSON(message)
+	c.writeMux.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to send %v: %w", method, err)
+	}
+	select {
+	case response := <-reply:
+		if response.Error != nil {
+			return fmt.Errorf("%v: %w", method, response.Error)
+		}
+		if result != nil && len(response.Result) > 0 {
+			return json.Unmarshal(response.Result, result)
+		}
+		return nil
+	case <-c.closed:
+		return fmt.Errorf("%v: %w", method, c.closeError())
+	case <-time.After(defaultCDPCallTimeout):
+		return fmt.Errorf("%v: timeout after %v", method, defaultCDPCallTimeout)
+	}
+}
+
+func (c *cdpConn) closeError() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.err == nil {
+		return errors.New("connection closed")
+	}
+	return c.err
+}
+
+// read dispatches command results and events, event handler must not block on commands
+func (c *cdpConn) read() {
+	defer close(c.closed)
+	for {
+		message := &cdpMessage{}
+		if err := c.conn.ReadJSON(message); err != nil {
+			c.mux.Lock()
+			c.err = fmt.Errorf("devtools connection closed: %w", err)
+			c.mux.Unlock()
+			return
+		}
+		if message.ID == 0 {
+			if c.handler != nil {
+				c.handler(message)
+			}
+			continue
+		}
+		c.mux.Lock()
+		reply, ok := c.pending[message.ID]
+		c.mux.Unlock()
+		if ok {
+			reply <- message
+		}
+	}
+}
+
+func (c *cdpConn) close() error {
+	return c.conn.Close()
+}
+
+// dialCDP connects to browser DevTools websocket endpoint
+func dialCDP(URL string, handler func(message *cdpMessage)) (*cdpConn, error) {
+	dialer := &websocket.Dialer{HandshakeTimeout: 30 * time.Second, ReadBufferSize: 1 << 20, WriteBufferSize: 1 << 20}
+	conn, _, err := dialer.Dial(URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to devtools %v: %w", URL, err)
+	}
+	result := &cdpConn{
+		conn:    conn,
+		pending: make(map[int64]chan *cdpMessage),
+		handler: handler,
+		closed:  make(chan struct{}),
+	}
+	go result.read()
+	return result, nil
+}
+
+// browserWebSocketURL returns browser DevTools websocket URL for supplied http://host:port endpoint
+func browserWebSocketURL(endpoint string) (string, error) {
+	if strings.HasPrefix(endpoint, "ws://") || strings.HasPrefix(endpoint, "wss://") {
+		return endpoint, nil
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	response, err := client.Get(strings.TrimRight(endpoint, "/") + "/json/version")
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected devtools status: %v", response.Status)
+	}
+	version := struct {
+		Browser              string `json:"Browser"`
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}{}
+	if err = json.NewDecoder(response.Body).Decode(&version); err != nil {
+		return "", err
+	}
+	if version.WebSocketDebuggerURL == "" {
+		return "", fmt.Errorf("webSocketDebuggerUrl was empty: %v", endpoint)
+	}
+	return version.WebSocketDebuggerURL, nil
+}
@@ -0,0 +1,1107 @@
+package webdriver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tebeka/selenium"
+	selog "github.com/tebeka/selenium/log"
+)
+
+const (
+	defaultPageLoadTimeout = 300 * time.Second
+	defaultScriptTimeout   = 30 * time.Second
+	defaultWaitTimeout     = 60 * time.Second
+	defaultWaitInterval    = 100 * time.Millisecond
+	maxBufferedLogMessages = 10000
+)
+
+// cdpDriver implements selenium.WebDriver on top of Chrome DevTools protocol
+type cdpDriver struct {
+	conn            *cdpConn
+	targetID        string
+	sessionID       string
+	frame           *cdpElement
+	pageLoadTimeout time.Duration
+	scriptTimeout   time.Duration
+	mouseX, mouseY  float64
+	mux             sync.Mutex
+	loaded          chan struct{}
+	performance     []selog.Message
+	console         []selog.Message
+	dialog          string
+	hasDialog       bool
+	promptText      string
+	routes          []*Route
+	downloadDir     string
+	downloads       []*Download
+}
+
+// remoteObject represents DevTools Runtime.RemoteObject
+type remoteObject struct {
+	Type        string          `json:"type"`
+	Subtype     string          `json:"subtype,omitempty"`
+	Value       json.RawMessage `json:"value,omitempty"`
+	ObjectID    string          `json:"objectId,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// exceptionDetails represents DevTools Runtime.ExceptionDetails
+type exceptionDetails struct {
+	Text      string        `json:"text"`
+	Exception *remoteObject `json:"exception,omitempty"`
+}
+
+func (e *exceptionDetails) error() error {
+	if e.Exception != nil && e.Exception.Description != "" {
+		return fmt.Errorf("javascript error: %v", e.Exception.Description)
+	}
+	return fmt.Errorf("javascript error: %v", e.Text)
+}
+
+type evaluateResult struct {
+	Result           *remoteObject     `json:"result"`
+	ExceptionDetails *exceptionDetails `json:"exceptionDetails,omitempty"`
+}
+
+// Status returns driver status
+func (d *cdpDriver) Status() (*selenium.Status, error) {
+	return &selenium.Status{}, nil
+}
+
+// NewSession returns current session, a page target is attached when driver is created
+func (d *cdpDriver) NewSession() (string, error) {
+	return d.targetID, nil
+}
+
+// SessionId returns current target id
+// Deprecated: use SessionID
+func (d *cdpDriver) SessionId() string {
+	return d.targetID
+}
+
+// SessionID returns current target id
+func (d *cdpDriver) SessionID() string {
+	return d.targetID
+}
+
+// SwitchSession switches to other page target
+func (d *cdpDriver) SwitchSession(sessionID string) error {
+	return d.SwitchWindow(sessionID)
+}
+
+// Capabilities returns driver capabilities
+func (d *cdpDriver) Capabilities() (selenium.Capabilities, error) {
+	return selenium.Capabilities{
+		"browserName": ChromeBrowser,
+		selog.CapabilitiesKey: map[string]interface{}{
+			string(selog.Performance): string(selog.All),
+			string(selog.Browser):     string(selog.All),
+		},
+	}, nil
+}
+
+// SetAsyncScriptTimeout sets ExecuteScriptAsync timeout
+func (d *cdpDriver) SetAsyncScriptTimeout(timeout time.Duration) error {
+	d.scriptTimeout = timeout
+	return nil
+}
+
+// SetImplicitWaitTimeout is no-op, element lookups are retried by the runner
+func (d *cdpDriver) SetImplicitWaitTimeout(timeout time.Duration) error {
+	return nil
+}
+
+// SetPageLoadTimeout sets navigation timeout
+func (d *cdpDriver) SetPageLoadTimeout(timeout time.Duration) error {
+	d.pageLoadTimeout = timeout
+	return nil
+}
+
+// Quit closes page target and devtools connection
+func (d *cdpDriver) Quit() error {
+	err := d.Close()
+	_ = d.conn.close()
+	return err
+}
+
+// CurrentWindowHandle returns current page target id
+func (d *cdpDriver) CurrentWindowHandle() (string, error) {
+	return d.targetID, nil
+}
+
+// WindowHandles returns page target ids
+func (d *cdpDriver) WindowHandles() ([]string, error) {
+	targets := struct {
+		TargetInfos []struct {
+			TargetID string `json:"targetId"`
+			Type     string `json:"type"`
+		} `json:"targetInfos"`
+	}{}
+	if err := d.conn.call("", "Target.getTargets", nil, &targets); err != nil {
+		return nil, err
+	}
+	var result = make([]string, 0)
+	for _, info := range targets.TargetInfos {
+		if info.Type == "page" {
+			result = append(result, info.TargetID)
+		}
+	}
+	return result, nil
+}
+
+// CurrentURL returns current page URL
+func (d *cdpDriver) CurrentURL() (string, error) {
+	var result string
+	return result, d.evaluate("location.href", &result)
+}
+
+// Title returns current page title
+func (d *cdpDriver) Title() (string, error) {
+	var result string
+	return result, d.evaluate("document.title", &result)
+}
+
+// PageSource returns current page source
+func (d *cdpDriver) PageSource() (string, error) {
+	var result string
+	return result, d.evaluate("document.documentElement ? document.documentElement.outerHTML : ''", &result)
+}
+
+// Close closes current page target
+func (d *cdpDriver) Close() error {
+	if d.targetID == "" {
+		return nil
+	}
+	err := d.conn.call("", "Target.closeTarget", map[string]interface{}{"targetId": d.targetID}, nil)
+	d.mux.Lock()
+	d.targetID, d.sessionID = "", ""
+	d.mux.Unlock()
+	return err
+}
+
+// SwitchFrame switches element lookups to frame document, frame is either iframe element, frame index, id or name, nil or empty string switches to top document
+func (d *cdpDriver) SwitchFrame(frame interface{}) error {
+	switch actual := frame.(type) {
+	case nil:
+		d.frame = nil
+		return nil
+	case *cdpElement:
+		d.frame = actual
+		return nil
+	case string:
+		if actual == "" {
+			d.frame = nil
+			return nil
+		}
+	}
+	d.frame = nil
+	selector := fmt.Sprintf(`(function(key) {
+  var frames = Array.from(document.querySelectorAll('iframe,frame'));
+  if (/^[0-9]+$/.test(key)) { return frames[parseInt(key)]; }
+  return frames.find(function(f) { return f.id === key || f.name === key; });
+})(%v)`, jsString(fmt.Sprint(frame)))
+	object, err := d.evaluateObject(selector)
+	if err != nil {
+		return err
+	}
+	if object.ObjectID == "" {
+		return noSuchElementError(fmt.Sprintf("frame %v", frame))
+	}
+	d.frame = &cdpElement{driver: d, objectID: object.ObjectID}
+	return nil
+}
+
+// SwitchWindow attaches to other page target
+func (d *cdpDriver) SwitchWindow(name string) error {
+	if name == d.targetID {
+		return nil
+	}
+	return d.attach(name)
+}
+
+// CloseWindow closes page target
+func (d *cdpDriver) CloseWindow(name string) error {
+	if name == d.targetID {
+		return d.Close()
+	}
+	return d.conn.call("", "Target.closeTarget", map[string]interface{}{"targetId": name}, nil)
+}
+
+// MaximizeWindow maximizes browser window
+func (d *cdpDriver) MaximizeWindow(name string) error {
+	return d.setWindowBounds(map[string]interface{}{"windowState": "maximized"})
+}
+
+// ResizeWindow resizes browser window
+func (d *cdpDriver) ResizeWindow(name string, width, height int) error {
+	if err := d.setWindowBounds(map[string]interface{}{"windowState": "normal"}); err != nil {
+		return err
+	}
+	return d.setWindowBounds(map[string]interface{}{"width": width, "height": height})
+}
+
+func (d *cdpDriver) setWindowBounds(bounds map[string]interface{}) error {
+	window := struct {
+		WindowID int `json:"windowId"`
+	}{}
+	if err := d.conn.call("", "Browser.getWindowForTarget", map[string]interface{}{"targetId": d.targetID}, &window); err != nil {
+		return err
+	}
+	return d.conn.call("", "Browser.setWindowBounds", map[string]interface{}{"windowId": window.WindowID, "bounds": bounds}, nil)
+}
+
+// Get navigates to URL and waits for page load event
+func (d *cdpDriver) Get(URL string) error {
+	return d.navigate(func() (bool, error) {
+		result := struct {
+			LoaderID  string `json:"loaderId"`
+			ErrorText string `json:"errorText"`
+		}{}
+		if err := d.call("Page.navigate", map[string]interface{}{"url": URL}, &result); err != nil {
+			return false, err
+		}
+		if result.ErrorText != "" {
+			return false, fmt.Errorf("failed to navigate to %v: %v", URL, result.ErrorText)
+		}
+		return result.LoaderID != "", nil
+	})
+}
+
+// Forward navigates forward in browser history
+func (d *cdpDriver) Forward() error {
+	return d.navigateHistory(1)
+}
+
+// Back navigates back in browser history
+func (d *cdpDriver) Back() error {
+	return d.navigateHistory(-1)
+}
+
+// Refresh reloads current page
+func (d *cdpDriver) Refresh() error {
+	return d.navigate(func() (bool, error) {
+		return true, d.call("Page.reload", nil, nil)
+	})
+}
+
+func (d *cdpDriver) navigateHistory(delta int) error {
+	history := struct {
+		CurrentIndex int `json:"currentIndex"`
+		Entries      []struct {
+			ID int `json:"id"`
+		} `json:"entries"`
+	}{}
+	if err := d.call("Page.getNavigationHistory", nil, &history); err != nil {
+		return err
+	}
+	index := history.CurrentIndex + delta
+	if index < 0 || index >= len(history.Entries) {
+		return nil
+	}
+	return d.navigate(func() (bool, error) {
+		return true, d.call("Page.navigateToHistoryEntry", map[string]interface{}{"entryId": history.Entries[index].ID}, nil)
+	})
+}
+
+// navigate runs navigation and waits for load event if navigation created a new document
+func (d *cdpDriver) navigate(navigation func() (bool, error)) error {
+	loaded := make(chan struct{})
+	d.mux.Lock()
+	d.loaded = loaded
+	d.mux.Unlock()
+	d.frame = nil
+	wait, err := navigation()
+	if err != nil || !wait {
+		return err
+	}
+	timeout := d.pageLoadTimeout
+	if timeout <= 0 {
+		timeout = defaultPageLoadTimeout
+	}
+	select {
+	case <-loaded:
+		return nil
+	case <-time.After(timeout):
+		return &selenium.Error{Err: "timeout", Message: fmt.Sprintf("timeout: page load exceeded %v", timeout), LegacyCode: 21}
+	}
+}
+
+// FindElement returns the first matching element
+func (d *cdpDriver) FindElement(by, value string) (selenium.WebElement, error) {
+	return findElement(d, d.frame, by, value)
+}
+
+// FindElements returns matching elements
+func (d *cdpDriver) FindElements(by, value string) ([]selenium.WebElement, error) {
+	return findElements(d, d.frame, by, value)
+}
+
+// ActiveElement returns focused element
+func (d *cdpDriver) ActiveElement() (selenium.WebElement, error) {
+	object, err := d.evaluateObject("document.activeElement")
+	if err != nil {
+		return nil, err
+	}
+	if object.ObjectID == "" {
+		return nil, noSuchElementError("active element")
+	}
+	return &cdpElement{driver: d, objectID: object.ObjectID}, nil
+}
+
+// DecodeElement is not supported by devtools backend
+func (d *cdpDriver) DecodeElement([]byte) (selenium.WebElement, error) {
+	return nil, unsupportedError("DecodeElement")
+}
+
+// DecodeElements is not supported by devtools backend
+func (d *cdpDriver) DecodeElements([]byte) ([]selenium.WebElement, error) {
+	return nil, unsupportedError("DecodeElements")
+}
+
+type cdpCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite,omitempty"`
+}
+
+// GetCookies returns current page cookies
+func (d *cdpDriver) GetCookies() ([]selenium.Cookie, error) {
+	URL, err := d.CurrentURL()
+	if err != nil {
+		return nil, err
+	}
+	cookies := struct {
+		Cookies []*cdpCookie `json:"cookies"`
+	}{}
+	if err = d.call("Network.getCookies", map[string]interface{}{"urls": []string{URL}}, &cookies); err != nil {
+		return nil, err
+	}
+	var result = make([]selenium.Cookie, 0, len(cookies.Cookies))
+	for _, cookie := range cookies.Cookies {
+		item := selenium.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+			SameSite: selenium.SameSite(cookie.SameSite),
+		}
+		if cookie.Expires > 0 {
+			item.Expiry = uint(cookie.Expires)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetCookie returns current page cookie
+func (d *cdpDriver) GetCookie(name string) (selenium.Cookie, error) {
+	cookies, err := d.GetCookies()
+	if err != nil {
+		return selenium.Cookie{}, err
+	}
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie, nil
+		}
+	}
+	return selenium.Cookie{}, &selenium.Error{Err: "no such cookie", Message: fmt.Sprintf("cookie %v was not found", name)}
+}
+
+// AddCookie sets cookie, current page URL is used if domain was not specified
+func (d *cdpDriver) AddCookie(cookie *selenium.Cookie) error {
+	params := map[string]interface{}{
+		"name":     cookie.Name,
+		"value":    cookie.Value,
+		"secure":   cookie.Secure,
+		"httpOnly": cookie.HTTPOnly,
+	}
+	if cookie.Domain != "" {
+		params["domain"] = cookie.Domain
+	} else {
+		URL, err := d.CurrentURL()
+		if err != nil {
+			return err
+		}
+		params["url"] = URL
+	}
+	if cookie.Path != "" {
+		params["path"] = cookie.Path
+	}
+	if cookie.Expiry > 0 {
+		params["expires"] = cookie.Expiry
+	}
+	if cookie.SameSite != "" {
+		params["sameSite"] = string(cookie.SameSite)
+	}
+	return d.call("Network.setCookie", params, nil)
+}
+
+// DeleteAllCookies removes browser cookies
+func (d *cdpDriver) DeleteAllCookies() error {
+	return d.call("Network.clearBrowserCookies", nil, nil)
+}
+
+// DeleteCookie removes current page cookie
+func (d *cdpDriver) DeleteCookie(name string) error {
+	URL, err := d.CurrentURL()
+	if err != nil {
+		return err
+	}
+	return d.call("Network.deleteCookies", map[string]interface{}{"name": name, "url": URL}, nil)
+}
+
+// Click clicks at the current mouse position
+func (d *cdpDriver) Click(button int) error {
+	if err := d.mouse("mousePressed", selenium.MouseButton(button), 1); err != nil {
+		return err
+	}
+	return d.mouse("mouseReleased", selenium.MouseButton(button), 1)
+}
+
+// DoubleClick double clicks at the current mouse position
+func (d *cdpDriver) DoubleClick() error {
+	if err := d.Click(int(selenium.LeftButton)); err != nil {
+		return err
+	}
+	if err := d.mouse("mousePressed", selenium.LeftButton, 2); err != nil {
+		return err
+	}
+	return d.mouse("mouseReleased", selenium.LeftButton, 2)
+}
+
+// ButtonDown presses left mouse button
+func (d *cdpDriver) ButtonDown() error {
+	return d.mouse("mousePressed", selenium.LeftButton, 1)
+}
+
+// ButtonUp releases left mouse button
+func (d *cdpDriver) ButtonUp() error {
+	return d.mouse("mouseReleased", selenium.LeftButton, 1)
+}
+
+func (d *cdpDriver) mouse(eventType string, button selenium.MouseButton, clickCount int) error {
+	buttons := []string{"left", "middle", "right"}
+	name := "none"
+	if button >= 0 && int(button) < len(buttons) {
+		name = buttons[button]
+	}
+	return d.call("Input.dispatchMouseEvent", map[string]interface{}{
+		"type":       eventType,
+		"x":          d.mouseX,
+		"y":          d.mouseY,
+		"button":     name,
+		"clickCount": clickCount,
+	}, nil)
+}
+
+// moveMouse moves mouse to viewport position
+func (d *cdpDriver) moveMouse(x, y float64) error {
+	d.mouseX, d.mouseY = x, y
+	return d.call("Input.dispatchMouseEvent", map[string]interface{}{"type": "mouseMoved", "x": x, "y": y}, nil)
+}
+
+// StoreKeyActions is not supported by devtools backend, use KeyDown/KeyUp
+func (d *cdpDriver) StoreKeyActions(inputID string, actions ...selenium.KeyAction) {}
+
+// StorePointerActions is not supported by devtools backend, use element MoveTo and Click
+func (d *cdpDriver) StorePointerActions(inputID string, pointer selenium.PointerType, actions ...selenium.PointerAction) {
+}
+
+// PerformActions is not supported by devtools backend
+func (d *cdpDriver) PerformActions() error {
+	return unsupportedError("PerformActions")
+}
+
+// ReleaseActions is no-op
+func (d *cdpDriver) ReleaseActions() error {
+	return nil
+}
+
+// SendModifier presses or releases modifier key
+func (d *cdpDriver) SendModifier(modifier string, isDown bool) error {
+	if isDown {
+		return d.KeyDown(modifier)
+	}
+	return d.KeyUp(modifier)
+}
+
+// KeyDown presses keys
+func (d *cdpDriver) KeyDown(keys string) error {
+	for _, r := range keys {
+		if err := d.key("keyDown", r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// KeyUp releases keys
+func (d *cdpDriver) KeyUp(keys string) error {
+	for _, r := range keys {
+		if err := d.key("keyUp", r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendKeys types text into focused element, selenium special keys are dispatched as key events
+func (d *cdpDriver) sendKeys(keys string) error {
+	var text strings.Builder
+	flush := func() error {
+		if text.Len() == 0 {
+			return nil
+		}
+		defer text.Reset()
+		return d.call("Input.insertText", map[string]interface{}{"text": text.String()}, nil)
+	}
+	for _, r := range keys {
+		if _, ok := specialKeys[r]; !ok {
+			text.WriteRune(r)
+			continue
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		if err := d.key("keyDown", r); err != nil {
+			return err
+		}
+		if err := d.key("keyUp", r); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
+
+func (d *cdpDriver) key(eventType string, r rune) error {
+	params := map[string]interface{}{"type": eventType}
+	if key, ok := specialKeys[r]; ok {
+		params["key"], params["code"], params["windowsVirtualKeyCode"] = key.key, key.key, key.code
+		if eventType == "keyDown" && key.text != "" {
+			params["text"] = key.text
+		}
+	} else {
+		params["key"] = string(r)
+		if eventType == "keyDown" {
+			params["text"] = string(r)
+		}
+	}
+	return d.call("Input.dispatchKeyEvent", params, nil)
+}
+
+// Screenshot captures page screenshot as PNG
+func (d *cdpDriver) Screenshot() ([]byte, error) {
+	return d.screenshot(nil)
+}
+
+func (d *cdpDriver) screenshot(clip map[string]interface{}) ([]byte, error) {
+	params := map[string]interface{}{"format": "png"}
+	if clip != nil {
+		params["clip"] = clip
+		params["captureBeyondViewport"] = true
+	}
+	result := struct {
+		Data string `json:"data"`
+	}{}
+	if err := d.call("Page.captureScreenshot", params, &result); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(result.Data)
+}
+
+// Log returns and clears buffered browser (console) or performance (Network events) log messages
+func (d *cdpDriver) Log(typ selog.Type) ([]selog.Message, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	var result []selog.Message
+	switch typ {
+	case selog.Browser:
+		result, d.console = d.console, nil
+	case selog.Performance:
+		result, d.performance = d.performance, nil
+	default:
+		return nil, fmt.Errorf("unsupported log type: %v", typ)
+	}
+	return result, nil
+}
+
+// DismissAlert dismisses JavaScript dialog
+func (d *cdpDriver) DismissAlert() error {
+	return d.handleDialog(false)
+}
+
+// AcceptAlert accepts JavaScript dialog
+func (d *cdpDriver) AcceptAlert() error {
+	return d.handleDialog(true)
+}
+
+// AlertText returns JavaScript dialog message
+func (d *cdpDriver) AlertText() (string, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if !d.hasDialog {
+		return "", &selenium.Error{Err: "no such alert", Message: "no such alert"}
+	}
+	return d.dialog, nil
+}
+
+// SetAlertText sets prompt text used when dialog is accepted
+func (d *cdpDriver) SetAlertText(text string) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.promptText = text
+	return nil
+}
+
+func (d *cdpDriver) handleDialog(accept bool) error {
+	d.mux.Lock()
+	promptText := d.promptText
+	d.mux.Unlock()
+	params := map[string]interface{}{"accept": accept}
+	if promptText != "" {
+		params["promptText"] = promptText
+	}
+	if err := d.call("Page.handleJavaScriptDialog", params, nil); err != nil {
+		return &selenium.Error{Err: "no such alert", Message: err.Error()}
+	}
+	d.mux.Lock()
+	d.hasDialog, d.dialog, d.promptText = false, "", ""
+	d.mux.Unlock()
+	return nil
+}
+
+// ExecuteScript executes script function body with supplied arguments, web elements are passed as DOM nodes
+func (d *cdpDriver) ExecuteScript(script string, args []interface{}) (interface{}, error) {
+	return d.execute("function() {"+script+"\n}", args)
+}
+
+// ExecuteScriptAsync executes script function body, script signals completion by calling the last argument callback
+func (d *cdpDriver) ExecuteScriptAsync(script string, args []interface{}) (interface{}, error) {
+	timeout := d.scriptTimeout
+	if timeout <= 0 {
+		timeout = defaultScriptTimeout
+	}
+	declaration := fmt.Sprintf(`function() {
+  var args = Array.prototype.slice.call(arguments);
+  var self = this;
+  return new Promise(function(resolve, reject) {
+    setTimeout(function() { reject(new Error('script timeout')); }, %d);
+    args.push(resolve);
+    (function() {%v
+    }).apply(self, args);
+  });
+}`, timeout.Milliseconds(), script)
+	return d.execute(declaration, args)
+}
+
+// ExecuteScriptRaw executes script and returns JSON encoded {"value": result}
+func (d *cdpDriver) ExecuteScriptRaw(script string, args []interface{}) ([]byte, error) {
+	result, err := d.ExecuteScript(script, args)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{"value": result})
+}
+
+// ExecuteScriptAsyncRaw executes async script and returns JSON encoded {"value": result}
+func (d *cdpDriver) ExecuteScriptAsyncRaw(script string, args []interface{}) ([]byte, error) {
+	result, err := d.ExecuteScriptAsync(script, args)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{"value": result})
+}
+
+// WaitWithTimeoutAndInterval waits for condition
+func (d *cdpDriver) WaitWithTimeoutAndInterval(condition selenium.Condition, timeout, interval time.Duration) error {
+	startTime := time.Now()
+	for {
+		done, err := condition(d)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if elapsed := time.Since(startTime); elapsed > timeout {
+			return fmt.Errorf("timeout after %v", elapsed)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// WaitWithTimeout waits for condition with default interval
+func (d *cdpDriver) WaitWithTimeout(condition selenium.Condition, timeout time.Duration) error {
+	return d.WaitWithTimeoutAndInterval(condition, timeout, defaultWaitInterval)
+}
+
+// Wait waits for condition with default timeout and interval
+func (d *cdpDriver) Wait(condition selenium.Condition) error {
+	return d.WaitWithTimeoutAndInterval(condition, defaultWaitTimeout, defaultWaitInterval)
+}
+
+// Execute sends raw devtools command to the current page target
+func (d *cdpDriver) Execute(method string, params map[string]interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := d.call(method, params, &result)
+	return result, err
+}
+
+// call sends command to the current page target
+func (d *cdpDriver) call(method string, params interface{}, result interface{}) error {
+	if d.sessionID == "" {
+		return &selenium.Error{Err: "no such window", Message: "no such window: target window already closed"}
+	}
+	return d.conn.call(d.sessionID, method, params, result)
+}
+
+// execute calls function declaration on global object
+func (d *cdpDriver) execute(declaration string, args []interface{}) (interface{}, error) {
+	global, err := d.evaluateObject("globalThis")
+	if err != nil {
+		return nil, err
+	}
+	defer d.release(global.ObjectID)
+	object, err := d.callFunction(global.ObjectID, declaration, args, true)
+	if err != nil {
+		return nil, err
+	}
+	return object.value()
+}
+
+// evaluate evaluates expression and decodes its value into result
+func (d *cdpDriver) evaluate(expression string, result interface{}) error {
+	response := &evaluateResult{}
+	if err := d.call("Runtime.evaluate", map[string]interface{}{"expression": expression, "returnByValue": true, "awaitPromise": true}, response); err != nil {
+		return err
+	}
+	if response.ExceptionDetails != nil {
+		return response.ExceptionDetails.error()
+	}
+	if response.Result == nil || len(response.Result.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(response.Result.Value, result)
+}
+
+// evaluateObject evaluates expression and returns remote object reference
+func (d *cdpDriver) evaluateObject(expression string) (*remoteObject, error) {
+	response := &evaluateResult{}
+	if err := d.call("Runtime.evaluate", map[string]interface{}{"expression": expression}, response); err != nil {
+		return nil, err
+	}
+	if response.ExceptionDetails != nil {
+		return nil, response.ExceptionDetails.error()
+	}
+	if response.Result == nil {
+		return &remoteObject{}, nil
+	}
+	return response.Result, nil
+}
+
+// callFunction calls function declaration with this bound to supplied object
+func (d *cdpDriver) callFunction(objectID, declaration string, args []interface{}, returnByValue bool) (*remoteObject, error) {
+	var arguments = make([]map[string]interface{}, 0, len(args))
+	for _, arg := range args {
+		if element, ok := arg.(*cdpElement); ok {
+			arguments = append(arguments, map[string]interface{}{"objectId": element.objectID})
+			continue
+		}
+		arguments = append(arguments, map[string]interface{}{"value": arg})
+	}
+	response := &evaluateResult{}
+	err := d.call("Runtime.callFunctionOn", map[string]interface{}{
+		"objectId":            objectID,
+		"functionDeclaration": declaration,
+		"arguments":           arguments,
+		"returnByValue":       returnByValue,
+		"awaitPromise":        true,
+	}, response)
+	if err != nil {
+		if isStaleObjectError(err) {
+			return nil, staleElementError(err)
+		}
+		return nil, err
+	}
+	if response.ExceptionDetails != nil {
+		return nil, response.ExceptionDetails.error()
+	}
+	if response.Result == nil {
+		return &remoteObject{}, nil
+	}
+	return response.Result, nil
+}
+
+// properties returns object own indexed property object ids
+func (d *cdpDriver) properties(objectID string) ([]string, error) {
+	response := struct {
+		Result []struct {
+			Name  string        `json:"name"`
+			Value *remoteObject `json:"value"`
+		} `json:"result"`
+	}{}
+	if err := d.call("Runtime.getProperties", map[string]interface{}{"objectId": objectID, "ownProperties": true}, &response); err != nil {
+		return nil, err
+	}
+	var result = make([]string, 0)
+	for _, property := range response.Result {
+		if property.Value == nil || property.Value.ObjectID == "" || property.Value.Subtype != "node" {
+			continue
+		}
+		result = append(result, property.Value.ObjectID)
+	}
+	return result, nil
+}
+
+func (d *cdpDriver) release(objectID string) {
+	if objectID != "" {
+		_ = d.call("Runtime.releaseObject", map[string]interface{}{"objectId": objectID}, nil)
+	}
+}
+
+// attach attaches to page target and enables domains used by the driver
+func (d *cdpDriver) attach(targetID string) error {
+	attached := struct {
+		SessionID string `json:"sessionId"`
+	}{}
+	if err := d.conn.call("", "Target.attachToTarget", map[string]interface{}{"targetId": targetID, "flatten": true}, &attached); err != nil {
+		return err
+	}
+	d.mux.Lock()
+	d.targetID, d.sessionID, d.frame = targetID, attached.SessionID, nil
+	d.mux.Unlock()
+	for _, domain := range []string{"Page.enable", "Runtime.enable", "Network.enable"} {
+		if err := d.call(domain, nil, nil); err != nil {
+			return err
+		}
+	}
+	if err := d.call("Page.setLifecycleEventsEnabled", map[string]interface{}{"enabled": true}, nil); err != nil {
+		return err
+	}
+	if len(d.routes) > 0 {
+		return d.enableInterception()
+	}
+	return nil
+}
+
+// onEvent handles devtools events, it runs on connection reader goroutine thus commands are sent asynchronously
+func (d *cdpDriver) onEvent(message *cdpMessage) {
+	if message.SessionID != "" && message.SessionID != d.currentSessionID() {
+		if !strings.HasPrefix(message.Method, "Fetch.") {
+			return
+		}
+	}
+	switch message.Method {
+	case "Page.loadEventFired":
+		d.mux.Lock()
+		if d.loaded != nil {
+			close(d.loaded)
+			d.loaded = nil
+		}
+		d.mux.Unlock()
+	case "Page.javascriptDialogOpening":
+		dialog := struct {
+			Message string `json:"message"`
+		}{}
+		_ = json.Unmarshal(message.Params, &dialog)
+		d.mux.Lock()
+		d.hasDialog, d.dialog = true, dialog.Message
+		d.mux.Unlock()
+	case "Page.javascriptDialogClosed":
+		d.mux.Lock()
+		d.hasDialog, d.dialog = false, ""
+		d.mux.Unlock()
+	case "Runtime.consoleAPICalled", "Runtime.exceptionThrown":
+		d.appendLog(&d.console, consoleMessage(message))
+	case "Fetch.requestPaused":
+		go d.onRequestPaused(message.SessionID, message.Params)
+	case "Browser.downloadWillBegin", "Browser.downloadProgress":
+		d.onDownload(message.Method, message.Params)
+	}
+	if strings.HasPrefix(message.Method, "Network.") || strings.HasPrefix(message.Method, "Page.") {
+		entry, err := json.Marshal(map[string]interface{}{
+			"webview": message.SessionID,
+			"message": map[string]interface{}{"method": message.Method, "params": message.Params},
+		})
+		if err == nil {
+			d.appendLog(&d.performance, selog.Message{Timestamp: time.Now(), Level: selog.Info, Message: string(entry)})
+		}
+	}
+}
+
+func (d *cdpDriver) currentSessionID() string {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.sessionID
+}
+
+func (d *cdpDriver) appendLog(messages *[]selog.Message, message selog.Message) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if len(*messages) >= maxBufferedLogMessages {
+		*messages = (*messages)[1:]
+	}
+	*messages = append(*messages, message)
+}
+
+// consoleMessage converts Runtime console or exception event into browser log message
+func consoleMessage(message *cdpMessage) selog.Message {
+	result := selog.Message{Timestamp: time.Now(), Level: selog.Info}
+	if message.Method == "Runtime.exceptionThrown" {
+		event := struct {
+			ExceptionDetails *exceptionDetails `json:"exceptionDetails"`
+		}{}
+		_ = json.Unmarshal(message.Params, &event)
+		result.Level = selog.Severe
+		if event.ExceptionDetails != nil {
+			result.Message = event.ExceptionDetails.error().Error()
+		}
+		return result
+	}
+	event := struct {
+		Type string          `json:"type"`
+		Args []*remoteObject `json:"args"`
+	}{}
+	_ = json.Unmarshal(message.Params, &event)
+	switch event.Type {
+	case "error", "assert":
+		result.Level = selog.Severe
+	case "warning":
+		result.Level = selog.Warning
+	case "debug":
+		result.Level = selog.Debug
+	}
+	var parts = make([]string, 0, len(event.Args))
+	for _, arg := range event.Args {
+		switch {
+		case len(arg.Value) > 0:
+			var value interface{}
+			if err := json.Unmarshal(arg.Value, &value); err == nil {
+				parts = append(parts, fmt.Sprint(value))
+				continue
+			}
+			parts = append(parts, string(arg.Value))
+		case arg.Description != "":
+			parts = append(parts, arg.Description)
+		default:
+			parts = append(parts, arg.Type)
+		}
+	}
+	result.Message = strings.Join(parts, " ")
+	return result
+}
+
+// value decodes by value remote object
+func (o *remoteObject) value() (interface{}, error) {
+	if o == nil || len(o.Value) == 0 {
+		return nil, nil
+	}
+	var result interface{}
+	return result, json.Unmarshal(o.Value, &result)
+}
+
+// newCDPDriver connects to browser devtools endpoint and attaches to a new page target
+func newCDPDriver(endpoint string, downloadDir string) (*cdpDriver, error) {
+	URL, err := browserWebSocketURL(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup devtools endpoint %v: %w", endpoint, err)
+	}
+	result := &cdpDriver{downloadDir: downloadDir}
+	if result.conn, err = dialCDP(URL, result.onEvent); err != nil {
+		return nil, err
+	}
+	target := struct {
+		TargetID string `json:"targetId"`
+	}{}
+	if err = result.conn.call("", "Target.createTarget", map[string]interface{}{"url": "about:blank"}, &target); err != nil {
+		_ = result.conn.close()
+		return nil, err
+	}
+	if err = result.attach(target.TargetID); err != nil {
+		_ = result.Quit()
+		return nil, err
+	}
+	if downloadDir != "" {
+		if downloadDir, err = filepath.Abs(downloadDir); err == nil {
+			result.downloadDir = downloadDir
+		}
+		err = result.conn.call("", "Browser.setDownloadBehavior", map[string]interface{}{
+			"behavior":      "allow",
+			"downloadPath":  result.downloadDir,
+			"eventsEnabled": true,
+		}, nil)
+		if err != nil {
+			_ = result.Quit()
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// specialKey represents selenium special key mapping to devtools key event
+type specialKey struct {
+	key  string
+	code int
+	text string
+}
+
+var specialKeys = map[rune]*specialKey{
+	'\ue003': {key: "Backspace", code: 8},
+	'\ue004': {key: "Tab", code: 9},
+	'\ue006': {key: "Enter", code: 13, text: "\r"},
+	'\ue007': {key: "Enter", code: 13, text: "\r"},
+	'\ue008': {key: "Shift", code: 16},
+	'\ue009': {key: "Control", code: 17},
+	'\ue00a': {key: "Alt", code: 18},
+	'\ue00c': {key: "Escape", code: 27},
+	'\ue00e': {key: "PageUp", code: 33},
+	'\ue00f': {key: "PageDown", code: 34},
+	'\ue010': {key: "End", code: 35},
+	'\ue011': {key: "Home", code: 36},
+	'\ue012': {key: "ArrowLeft", code: 37},
+	'\ue013': {key: "ArrowUp", code: 38},
+	'\ue014': {key: "ArrowRight", code: 39},
+	'\ue015': {key: "ArrowDown", code: 40},
+	'\ue016': {key: "Insert", code: 45},
+	'\ue017': {key: "Delete", code: 46},
+	'\ue03d': {key: "Meta", code: 91},
+}
+
+func jsString(value string) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func unsupportedError(method string) error {
+	return &selenium.Error{Err: "unsupported operation", Message: fmt.Sprintf("%v is not supported with %v backend", method, BackendCDP)}
+}
+
+func noSuchElementError(description string) error {
+	return &selenium.Error{Err: "no such element", Message: fmt.Sprintf("no such element: %v", description), LegacyCode: 7}
+}
+
+func staleElementError(err error) error {
+	return &selenium.Error{Err: "stale element reference", Message: err.Error(), LegacyCode: staleElementReferenceException}
+}
+
+// isStaleObjectError returns true if remote object no longer exists, i.e. after navigation
+func isStaleObjectError(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "Could not find object with given id") ||
+		strings.Contains(message, "Cannot find context with specified id") ||
+		strings.Contains(message, "Execution context was destroyed") ||
+		strings.Contains(message, "Cannot find default execution context")
+}
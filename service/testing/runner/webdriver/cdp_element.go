@@ -0,0 +1,331 @@
+package webdriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tebeka/selenium"
+)
+
+// findElementsFunction returns elements matching selenium locator, this is either document, element or frame element
+const findElementsFunction = `function(by, value) {
+  var root = (this && this.nodeType === 1) ? (this.contentDocument || this) : document;
+  if ((this && this.nodeType === 1) && /^i?frame$/i.test(this.tagName) && !this.contentDocument) {
+    throw new Error('frame document is not accessible');
+  }
+  var query = function(selector) { return Array.from(root.querySelectorAll(selector)); };
+  switch (by) {
+  case 'id': return query('#' + CSS.escape(value));
+  case 'name': return query('[name="' + CSS.escape(value) + '"]');
+  case 'class name': return query('.' + CSS.escape(value));
+  case 'tag name': return query(value);
+  case 'css selector': return query(value);
+  case 'link text': return query('a').filter(function(a) { return a.innerText.trim() === value; });
+  case 'partial link text': return query('a').filter(function(a) { return a.innerText.indexOf(value) !== -1; });
+  case 'xpath':
+    var owner = root.nodeType === 9 ? root : root.ownerDocument;
+    var snapshot = owner.evaluate(value, root, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+    var result = [];
+    for (var i = 0; i < snapshot.snapshotLength; i++) { result.push(snapshot.snapshotItem(i)); }
+    return result;
+  }
+  throw new Error('unsupported locator: ' + by);
+}`
+
+// cdpElement implements selenium.WebElement with devtools remote object
+type cdpElement struct {
+	driver   *cdpDriver
+	objectID string
+}
+
+// rect represents element bounding client rect
+type rect struct {
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	Width   float64 `json:"width"`
+	Height  float64 `json:"height"`
+	ScrollX float64 `json:"scrollX"`
+	ScrollY float64 `json:"scrollY"`
+}
+
+// Click scrolls element into view and dispatches mouse click at element center, options are selected directly
+func (e *cdpElement) Click() error {
+	var selected bool
+	err := e.call(`function() {
+  if (this.tagName === 'OPTION') {
+    var select = this.closest('select');
+    this.selected = true;
+    if (select) {
+      select.dispatchEvent(new Event('input', {bubbles: true}));
+      select.dispatchEvent(new Event('change', {bubbles: true}));
+    }
+    return true;
+  }
+  return false;
+}`, &selected)
+	if err != nil || selected {
+		return err
+	}
+	bounds, err := e.scrollIntoView()
+	if err != nil {
+		return err
+	}
+	if bounds.Width == 0 && bounds.Height == 0 {
+		return e.call("function() { this.click(); }", nil)
+	}
+	if err = e.driver.moveMouse(bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2); err != nil {
+		return err
+	}
+	return e.driver.Click(int(selenium.LeftButton))
+}
+
+// SendKeys focuses element and types keys
+func (e *cdpElement) SendKeys(keys string) error {
+	if err := e.call("function() { this.focus(); }", nil); err != nil {
+		return err
+	}
+	return e.driver.sendKeys(keys)
+}
+
+// Submit submits element form
+func (e *cdpElement) Submit() error {
+	return e.call(`function() {
+  var form = this.tagName === 'FORM' ? this : (this.form || this.closest('form'));
+  if (!form) { throw new Error('element is not in a form'); }
+  if (form.requestSubmit) { form.requestSubmit(); } else { form.submit(); }
+}`, nil)
+}
+
+// Clear clears element value or editable content
+func (e *cdpElement) Clear() error {
+	return e.call(`function() {
+  if ('value' in this) { this.value = ''; } else if (this.isContentEditable) { this.textContent = ''; }
+  this.dispatchEvent(new Event('input', {bubbles: true}));
+  this.dispatchEvent(new Event('change', {bubbles: true}));
+}`, nil)
+}
+
+// MoveTo moves mouse to element offset
+func (e *cdpElement) MoveTo(xOffset, yOffset int) error {
+	bounds, err := e.scrollIntoView()
+	if err != nil {
+		return err
+	}
+	return e.driver.moveMouse(bounds.X+float64(xOffset), bounds.Y+float64(yOffset))
+}
+
+// FindElement returns the first matching descendant element
+func (e *cdpElement) FindElement(by, value string) (selenium.WebElement, error) {
+	return findElement(e.driver, e, by, value)
+}
+
+// FindElements returns matching descendant elements
+func (e *cdpElement) FindElements(by, value string) ([]selenium.WebElement, error) {
+	return findElements(e.driver, e, by, value)
+}
+
+// TagName returns lower case tag name
+func (e *cdpElement) TagName() (string, error) {
+	var result string
+	return result, e.call("function() { return this.tagName.toLowerCase(); }", &result)
+}
+
+// Text returns rendered element text
+func (e *cdpElement) Text() (string, error) {
+	var result string
+	return result, e.call("function() { return this.innerText !== undefined ? this.innerText : this.textContent; }", &result)
+}
+
+// IsSelected returns true if checkbox, radio or option is selected
+func (e *cdpElement) IsSelected() (bool, error) {
+	var result bool
+	return result, e.call("function() { return !!(this.checked || this.selected); }", &result)
+}
+
+// IsEnabled returns true if element is not disabled
+func (e *cdpElement) IsEnabled() (bool, error) {
+	var result bool
+	return result, e.call("function() { return !this.disabled; }", &result)
+}
+
+// IsDisplayed returns true if element is rendered and visible
+func (e *cdpElement) IsDisplayed() (bool, error) {
+	var result bool
+	return result, e.call(`function() {
+  var element = this.tagName === 'OPTION' ? (this.closest('select') || this) : this;
+  var style = window.getComputedStyle(element);
+  if (style.display === 'none' || style.visibility === 'hidden' || style.visibility === 'collapse') { return false; }
+  return element.getClientRects().length > 0;
+}`, &result)
+}
+
+// GetAttribute returns attribute, value, checked and selected are returned from element properties
+func (e *cdpElement) GetAttribute(name string) (string, error) {
+	var result *string
+	err := e.call(`function(name) {
+  var value = null;
+  if (name === 'value' || name === 'checked' || name === 'selected') {
+    value = this[name];
+    if (value === false) { value = null; }
+  } else {
+    value = this.getAttribute(name);
+    if (value === null && name in this) { value = this[name]; }
+  }
+  return value === null || value === undefined ? null : String(value);
+}`, &result, name)
+	if err != nil {
+		return "", err
+	}
+	if result == nil {
+		return "", &selenium.Error{Err: "no such attribute", Message: fmt.Sprintf("attribute %v was not found", name)}
+	}
+	return *result, nil
+}
+
+// GetProperty returns element property
+func (e *cdpElement) GetProperty(name string) (string, error) {
+	var result string
+	return result, e.call("function(name) { var value = this[name]; return value === null || value === undefined ? '' : String(value); }", &result, name)
+}
+
+// Location returns element page location
+func (e *cdpElement) Location() (*selenium.Point, error) {
+	bounds, err := e.bounds()
+	if err != nil {
+		return nil, err
+	}
+	return &selenium.Point{X: int(bounds.X + bounds.ScrollX), Y: int(bounds.Y + bounds.ScrollY)}, nil
+}
+
+// LocationInView scrolls element into view and returns its viewport location
+func (e *cdpElement) LocationInView() (*selenium.Point, error) {
+	bounds, err := e.scrollIntoView()
+	if err != nil {
+		return nil, err
+	}
+	return &selenium.Point{X: int(bounds.X), Y: int(bounds.Y)}, nil
+}
+
+// Size returns element size
+func (e *cdpElement) Size() (*selenium.Size, error) {
+	bounds, err := e.bounds()
+	if err != nil {
+		return nil, err
+	}
+	return &selenium.Size{Width: int(bounds.Width), Height: int(bounds.Height)}, nil
+}
+
+// CSSProperty returns computed style property
+func (e *cdpElement) CSSProperty(name string) (string, error) {
+	var result string
+	return result, e.call("function(name) { return window.getComputedStyle(this).getPropertyValue(name); }", &result, name)
+}
+
+// Screenshot captures element screenshot as PNG
+func (e *cdpElement) Screenshot(scroll bool) ([]byte, error) {
+	var bounds *rect
+	var err error
+	if scroll {
+		bounds, err = e.scrollIntoView()
+	} else {
+		bounds, err = e.bounds()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bounds.Width == 0 || bounds.Height == 0 {
+		return nil, &selenium.Error{Err: "element not interactable", Message: "element has zero size"}
+	}
+	return e.driver.screenshot(map[string]interface{}{
+		"x":      bounds.X + bounds.ScrollX,
+		"y":      bounds.Y + bounds.ScrollY,
+		"width":  bounds.Width,
+		"height": bounds.Height,
+		"scale":  1,
+	})
+}
+
+const boundsFunction = `function() {
+  var r = this.getBoundingClientRect();
+  return {x: r.left, y: r.top, width: r.width, height: r.height, scrollX: window.scrollX, scrollY: window.scrollY};
+}`
+
+func (e *cdpElement) bounds() (*rect, error) {
+	result := &rect{}
+	return result, e.call(boundsFunction, result)
+}
+
+func (e *cdpElement) scrollIntoView() (*rect, error) {
+	if err := e.call("function() { this.scrollIntoView({block: 'center', inline: 'center'}); }", nil); err != nil {
+		return nil, err
+	}
+	return e.bounds()
+}
+
+// call calls function on element, detached elements are reported as stale
+func (e *cdpElement) call(declaration string, result interface{}, args ...interface{}) error {
+	declaration = `function() {
+  if (!this.isConnected) { throw new Error('stale element reference: element is not attached to the page document'); }
+  return (` + declaration + `).apply(this, arguments);
+}`
+	object, err := e.driver.callFunction(e.objectID, declaration, args, true)
+	if err != nil {
+		if isStaleObjectError(err) || isDetachedError(err) {
+			return staleElementError(err)
+		}
+		return err
+	}
+	if result == nil || len(object.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(object.Value, result)
+}
+
+func isDetachedError(err error) bool {
+	return strings.Contains(err.Error(), "stale element reference")
+}
+
+// findElements finds elements within document, frame or element
+func findElements(driver *cdpDriver, parent *cdpElement, by, value string) ([]selenium.WebElement, error) {
+	var object *remoteObject
+	var err error
+	if parent != nil {
+		object, err = driver.callFunction(parent.objectID, findElementsFunction, []interface{}{by, value}, false)
+	} else {
+		var global *remoteObject
+		if global, err = driver.evaluateObject("globalThis"); err != nil {
+			return nil, err
+		}
+		object, err = driver.callFunction(global.ObjectID, findElementsFunction, []interface{}{by, value}, false)
+		driver.release(global.ObjectID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if object.ObjectID == "" {
+		return []selenium.WebElement{}, nil
+	}
+	defer driver.release(object.ObjectID)
+	objectIDs, err := driver.properties(object.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]selenium.WebElement, 0, len(objectIDs))
+	for _, objectID := range objectIDs {
+		result = append(result, &cdpElement{driver: driver, objectID: objectID})
+	}
+	return result, nil
+}
+
+// findElement returns the first matching element
+func findElement(driver *cdpDriver, parent *cdpElement, by, value string) (selenium.WebElement, error) {
+	elements, err := findElements(driver, parent, by, value)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return nil, noSuchElementError(fmt.Sprintf("%v: %v", by, value))
+	}
+	return elements[0], nil
+}
@@ -0,0 +1,312 @@
+package webdriver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/tebeka/selenium"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+)
+
+// fakeDevTools represents minimal DevTools endpoint serving a form page
+type fakeDevTools struct {
+	*httptest.Server
+	mux        sync.Mutex
+	page       string
+	value      string
+	clicks     int
+	keys       []string
+	screenshot []byte
+	fulfilled  chan *cdpMessage
+}
+
+func (f *fakeDevTools) handle(message *cdpMessage) (interface{}, []*cdpMessage) {
+	params := map[string]interface{}{}
+	_ = json.Unmarshal(message.Params, &params)
+	event := func(method string, params interface{}) *cdpMessage {
+		encoded, _ := json.Marshal(params)
+		return &cdpMessage{SessionID: message.SessionID, Method: method, Params: encoded}
+	}
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	switch message.Method {
+	case "Target.createTarget":
+		return map[string]interface{}{"targetId": "T1"}, nil
+	case "Target.attachToTarget":
+		return map[string]interface{}{"sessionId": "S1"}, nil
+	case "Page.navigate":
+		f.page = toolbox.AsString(params["url"])
+		return map[string]interface{}{"frameId": "F1", "loaderId": "L1"}, []*cdpMessage{
+			event("Network.requestWillBeSent", map[string]interface{}{"requestId": "1", "request": map[string]interface{}{"url": f.page}}),
+			event("Page.loadEventFired", map[string]interface{}{"timestamp": 1}),
+		}
+	case "Runtime.evaluate":
+		switch params["expression"] {
+		case "globalThis":
+			return map[string]interface{}{"result": map[string]interface{}{"type": "object", "objectId": "global"}}, nil
+		case "document.title":
+			return map[string]interface{}{"result": map[string]interface{}{"type": "string", "value": "Form"}}, nil
+		case "location.href":
+			return map[string]interface{}{"result": map[string]interface{}{"type": "string", "value": f.page}}, nil
+		}
+	case "Runtime.callFunctionOn":
+		return f.callFunction(params), nil
+	case "Runtime.getProperties":
+		id := strings.TrimPrefix(toolbox.AsString(params["objectId"]), "array:")
+		var result = []interface{}{map[string]interface{}{"name": "length", "value": map[string]interface{}{"type": "number", "value": 0}}}
+		if id != "" {
+			result = append(result, map[string]interface{}{"name": "0", "value": map[string]interface{}{"type": "object", "subtype": "node", "objectId": "element:" + id}})
+		}
+		return map[string]interface{}{"result": result}, nil
+	case "Input.insertText":
+		f.value += toolbox.AsString(params["text"])
+	case "Input.dispatchKeyEvent":
+		if params["type"] == "keyDown" {
+			f.keys = append(f.keys, toolbox.AsString(params["key"]))
+		}
+	case "Input.dispatchMouseEvent":
+		if params["type"] == "mousePressed" {
+			f.clicks++
+		}
+	case "Page.captureScreenshot":
+		if f.screenshot != nil {
+			return map[string]interface{}{"data": base64.StdEncoding.EncodeToString(f.screenshot)}, nil
+		}
+		return map[string]interface{}{"data": base64.StdEncoding.EncodeToString([]byte("png"))}, nil
+	case "Fetch.enable":
+		var events []*cdpMessage
+		for i, URL := range []string{"http://127.0.0.1/api/users", "http://127.0.0.1/tracking/pixel", "http://127.0.0.1/app.js"} {
+			events = append(events, event("Fetch.requestPaused", map[string]interface{}{
+				"requestId": toolbox.AsString(i + 1),
+				"request":   map[string]interface{}{"url": URL, "method": "GET"},
+			}))
+		}
+		return map[string]interface{}{}, events
+	case "Fetch.fulfillRequest", "Fetch.failRequest", "Fetch.continueRequest":
+		f.fulfilled <- message
+	case "Browser.setDownloadBehavior":
+		return map[string]interface{}{}, []*cdpMessage{
+			{Method: "Browser.downloadWillBegin", Params: json.RawMessage(`{"guid":"G1","url":"http://127.0.0.1/report.csv","suggestedFilename":"report.csv"}`)},
+			{Method: "Browser.downloadProgress", Params: json.RawMessage(`{"guid":"G1","state":"completed","receivedBytes":12}`)},
+		}
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (f *fakeDevTools) callFunction(params map[string]interface{}) interface{} {
+	declaration := toolbox.AsString(params["functionDeclaration"])
+	value := func(value interface{}) interface{} {
+		return map[string]interface{}{"result": map[string]interface{}{"type": "object", "value": value}}
+	}
+	if declaration == findElementsFunction {
+		arguments := toolbox.AsSlice(params["arguments"])
+		selector := toolbox.AsString(toolbox.AsMap(arguments[1])["value"])
+		id := ""
+		if selector == "#name" || selector == "#submit" || selector == "#result" {
+			id = selector[1:]
+		}
+		return map[string]interface{}{"result": map[string]interface{}{"type": "object", "subtype": "array", "objectId": "array:" + id}}
+	}
+	switch {
+	case strings.Contains(declaration, "getClientRects"):
+		return value(true)
+	case strings.Contains(declaration, "this.selected = true"):
+		return value(false)
+	case strings.Contains(declaration, "getBoundingClientRect"):
+		return value(map[string]interface{}{"x": 10, "y": 20, "width": 100, "height": 20})
+	case strings.Contains(declaration, "innerText"):
+		return value("Hello " + f.value)
+	}
+	return value(nil)
+}
+
+func (f *fakeDevTools) serve(writer http.ResponseWriter, request *http.Request) {
+	if request.URL.Path == "/json/version" {
+		_ = json.NewEncoder(writer).Encode(map[string]interface{}{
+			"Browser":              "HeadlessChrome",
+			"webSocketDebuggerUrl": "ws" + strings.TrimPrefix(f.URL, "http") + "/devtools/browser/B1",
+		})
+		return
+	}
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		message := &cdpMessage{}
+		if err = conn.ReadJSON(message); err != nil {
+			return
+		}
+		result, events := f.handle(message)
+		encoded, _ := json.Marshal(result)
+		if err = conn.WriteJSON(&cdpMessage{ID: message.ID, SessionID: message.SessionID, Result: encoded}); err != nil {
+			return
+		}
+		for _, event := range events {
+			if err = conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func newFakeDevTools() *fakeDevTools {
+	result := &fakeDevTools{fulfilled: make(chan *cdpMessage, 10)}
+	result.Server = httptest.NewServer(http.HandlerFunc(result.serve))
+	return result
+}
+
+func newDevToolsContext(devTools *fakeDevTools) *endly.Context {
+	context := endly.New().NewContext(toolbox.NewContext())
+	Sessions(context)["localhost:9222"] = &Session{
+		SessionID:         "localhost:9222",
+		Browser:           ChromeBrowser,
+		Backend:           BackendCDP,
+		Remote:            devTools.URL,
+		DownloadDirectory: "/tmp/downloads",
+	}
+	return context
+}
+
+func TestService_DevToolsRun(t *testing.T) {
+	devTools := newFakeDevTools()
+	defer devTools.Close()
+	context := newDevToolsContext(devTools)
+	defer context.Close()
+
+	response := &RunResponse{}
+	err := endly.Run(context, &RunRequest{
+		SessionID: "localhost:9222",
+		Commands: []interface{}{
+			"get(http://127.0.0.1/form.html)",
+			"(#name).sendKeys('Bob" + selenium.EnterKey + "')",
+			"(#submit).click",
+			"result = (#result).text",
+			"title = Title",
+			"url = CurrentURL",
+		},
+		Expect: map[string]interface{}{
+			"title": "Form",
+		},
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, "Hello Bob", response.Data["result"])
+	assert.EqualValues(t, "Form", response.Data["title"])
+	assert.EqualValues(t, "http://127.0.0.1/form.html", response.Data["url"])
+	assert.EqualValues(t, 1, devTools.clicks)
+	assert.EqualValues(t, []string{"Enter"}, devTools.keys)
+
+	session := Sessions(context)["localhost:9222"]
+	screenshot, err := session.Driver().Screenshot()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "png", string(screenshot))
+	_, err = session.Driver().FindElement("css selector", "#missing")
+	assert.NotNil(t, err)
+	messages, err := session.driver.(*cdpDriver).Log("performance")
+	assert.Nil(t, err)
+	if assert.True(t, len(messages) > 0) {
+		method, _, err := parsePerformanceLogMessage(messages[0].Message)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "Network.requestWillBeSent", method)
+	}
+}
+
+func TestService_DevToolsIntercept(t *testing.T) {
+	devTools := newFakeDevTools()
+	defer devTools.Close()
+	context := newDevToolsContext(devTools)
+	defer context.Close()
+
+	response := &InterceptResponse{}
+	err := endly.Run(context, &InterceptRequest{
+		SessionID: "localhost:9222",
+		Routes: []*Route{
+			{URL: "*/api/users", Body: map[string]interface{}{"id": 1}},
+			{URL: "~/tracking/", Abort: true},
+		},
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 2, response.Routes)
+	var calls = map[string]map[string]interface{}{}
+	for i := 0; i < 3; i++ {
+		message := <-devTools.fulfilled
+		params := map[string]interface{}{}
+		_ = json.Unmarshal(message.Params, &params)
+		params["method"] = message.Method
+		calls[toolbox.AsString(params["requestId"])] = params
+	}
+	assert.EqualValues(t, "Fetch.fulfillRequest", calls["1"]["method"])
+	assert.EqualValues(t, 200, calls["1"]["responseCode"])
+	body, _ := base64.StdEncoding.DecodeString(toolbox.AsString(calls["1"]["body"]))
+	assert.EqualValues(t, `{"id":1}`, string(body))
+	assert.EqualValues(t, "Fetch.failRequest", calls["2"]["method"])
+	assert.EqualValues(t, "Fetch.continueRequest", calls["3"]["method"])
+}
+
+func TestService_DevToolsDownloads(t *testing.T) {
+	devTools := newFakeDevTools()
+	defer devTools.Close()
+	context := newDevToolsContext(devTools)
+	defer context.Close()
+
+	response := &DownloadsResponse{}
+	err := endly.Run(context, &DownloadsRequest{
+		SessionID: "localhost:9222",
+		Expect: []interface{}{
+			map[string]interface{}{"Filename": "report.csv", "State": "completed"},
+		},
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, "/tmp/downloads", response.Directory)
+	if assert.Len(t, response.Downloads, 1) {
+		assert.EqualValues(t, "/tmp/downloads/report.csv", response.Downloads[0].Path)
+		assert.EqualValues(t, 12, response.Downloads[0].Size)
+	}
+	assert.EqualValues(t, 0, response.Assert.FailedCount)
+}
+
+func TestService_DevToolsBackendRequired(t *testing.T) {
+	context := endly.New().NewContext(toolbox.NewContext())
+	defer context.Close()
+	Sessions(context)["localhost:4444"] = &Session{SessionID: "localhost:4444", Browser: ChromeBrowser}
+	err := endly.Run(context, &DownloadsRequest{}, &DownloadsResponse{})
+	assert.NotNil(t, err)
+}
+
+func TestRoute_Match(t *testing.T) {
+	var useCases = []struct {
+		description string
+		route       *Route
+		method      string
+		URL         string
+		expect      bool
+	}{
+		{description: "exact", route: &Route{URL: "http://127.0.0.1/api"}, method: "GET", URL: "http://127.0.0.1/api", expect: true},
+		{description: "exact mismatch", route: &Route{URL: "http://127.0.0.1/api"}, method: "GET", URL: "http://127.0.0.1/api/users", expect: false},
+		{description: "glob", route: &Route{URL: "*/api/*"}, method: "GET", URL: "http://127.0.0.1/api/users?id=1", expect: true},
+		{description: "regexp", route: &Route{URL: "~/users\\?id=[0-9]+/"}, method: "GET", URL: "http://127.0.0.1/api/users?id=1", expect: true},
+		{description: "method", route: &Route{URL: "*", Method: "POST"}, method: "GET", URL: "http://127.0.0.1/", expect: false},
+	}
+	for _, useCase := range useCases {
+		if !assert.Nil(t, useCase.route.Init(), useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, useCase.route.Match(useCase.method, useCase.URL), useCase.description)
+	}
+}
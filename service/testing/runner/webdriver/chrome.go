@@ -0,0 +1,139 @@
+package webdriver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const chromeStartTimeout = 30 * time.Second
+
+// chromeCandidates represents well known Chrome/Chromium executables
+var chromeCandidates = []string{
+	"google-chrome",
+	"google-chrome-stable",
+	"chromium",
+	"chromium-browser",
+	"chrome",
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+	"/Applications/Chromium.app/Contents/MacOS/Chromium",
+	`C:\Program Files\Google\Chrome\Application\chrome.exe`,
+}
+
+// chromeProcess represents locally launched Chrome with remote debugging enabled
+type chromeProcess struct {
+	cmd         *exec.Cmd
+	userDataDir string
+	endpoint    string
+	exited      chan struct{}
+}
+
+// Pid returns process id
+func (p *chromeProcess) Pid() int {
+	if p.cmd == nil || p.cmd.Process == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+// stop kills Chrome and removes its temporary profile
+func (p *chromeProcess) stop() {
+	if p.cmd != nil && p.cmd.Process != nil {
+		_ = p.cmd.Process.Kill()
+		select {
+		case <-p.exited:
+		case <-time.After(5 * time.Second):
+		}
+	}
+	if p.userDataDir != "" {
+		_ = os.RemoveAll(p.userDataDir)
+	}
+}
+
+// lookupChrome returns Chrome executable path
+func lookupChrome(binary string) (string, error) {
+	if binary != "" {
+		return exec.LookPath(binary)
+	}
+	if candidate := os.Getenv("CHROME_PATH"); candidate != "" {
+		return exec.LookPath(candidate)
+	}
+	for _, candidate := range chromeCandidates {
+		if result, err := exec.LookPath(candidate); err == nil {
+			return result, nil
+		}
+	}
+	return "", fmt.Errorf("failed to locate Chrome/Chromium executable on %v, set chrome path or CHROME_PATH", runtime.GOOS)
+}
+
+// chromeArguments returns Chrome command line arguments, capabilities are passed as is
+func chromeArguments(port int, userDataDir string, headless bool, capabilities []string) []string {
+	var result = []string{
+		fmt.Sprintf("--remote-debugging-port=%v", port),
+		"--user-data-dir=" + userDataDir,
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--disable-background-networking",
+		"--disable-popup-blocking",
+	}
+	hasHeadless := false
+	for _, capability := range capabilities {
+		if strings.HasPrefix(capability, "--headless") || strings.HasPrefix(capability, "headless") {
+			hasHeadless = true
+		}
+	}
+	if headless && !hasHeadless {
+		result = append(result, "--headless=new")
+	}
+	for _, capability := range capabilities {
+		if !strings.HasPrefix(capability, "-") {
+			capability = "--" + capability
+		}
+		result = append(result, capability)
+	}
+	return append(result, "about:blank")
+}
+
+// startChrome launches Chrome with DevTools listening on supplied port
+func startChrome(binary string, port int, headless bool, capabilities []string) (*chromeProcess, error) {
+	executable, err := lookupChrome(binary)
+	if err != nil {
+		return nil, err
+	}
+	userDataDir, err := os.MkdirTemp("", "endly-chrome")
+	if err != nil {
+		return nil, err
+	}
+	result := &chromeProcess{
+		userDataDir: userDataDir,
+		endpoint:    fmt.Sprintf("http://127.0.0.1:%v", port),
+		exited:      make(chan struct{}),
+	}
+	result.cmd = exec.Command(executable, chromeArguments(port, userDataDir, headless, capabilities)...)
+	if err = result.cmd.Start(); err != nil {
+		_ = os.RemoveAll(userDataDir)
+		return nil, fmt.Errorf("failed to start %v: %w", executable, err)
+	}
+	go func() {
+		_ = result.cmd.Wait()
+		close(result.exited)
+	}()
+	deadline := time.Now().Add(chromeStartTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-result.exited:
+			result.stop()
+			return nil, fmt.Errorf("%v exited before devtools became available", executable)
+		default:
+		}
+		if _, err = browserWebSocketURL(result.endpoint); err == nil {
+			return result, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	result.stop()
+	return nil, fmt.Errorf("devtools was not available on %v: %v", result.endpoint, err)
+}
@@ -2,12 +2,14 @@ package webdriver
 
 import (
 	"fmt"
+	"github.com/viant/assertly"
 	"github.com/viant/endly/internal/util"
 	"github.com/viant/endly/model/criteria/eval"
 	"github.com/viant/endly/model/location"
 	"github.com/viant/endly/service/testing/validator"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
+	"regexp"
 	"strings"
 )
 
@@ -24,18 +26,25 @@ const (
 
 // StartRequest represents a selenium server start request
 type StartRequest struct {
-	Target       *location.Resource
-	Driver       string
-	Server       string
-	Sdk          string
-	Capabilities []string
-	Port         int
+	Target            *location.Resource
+	Driver            string
+	Server            string
+	Sdk               string
+	Capabilities      []string
+	Port              int
+	Backend           string `description:"browser automation backend: selenium (default) or cdp to drive Chrome directly over DevTools protocol"`
+	Chrome            string `description:"cdp backend Chrome/Chromium executable, default: CHROME_PATH or well known location"`
+	Headed            bool   `description:"cdp backend flag to start Chrome with a visible window"`
+	DownloadDirectory string `description:"cdp backend file downloads directory, default: browser profile Downloads directory"`
 }
 
 func (r *StartRequest) Init() error {
 	if r.Port == 0 {
 		r.Port = 4444
 	}
+	if r.Backend == "" {
+		r.Backend = BackendSelenium
+	}
 	if r.Driver == "" {
 		r.Driver = ChromeDriver
 	}
@@ -46,7 +55,11 @@ func (r *StartRequest) Init() error {
 }
 
 func (r *StartRequest) Validate() error {
-	return nil
+	switch r.Backend {
+	case BackendSelenium, BackendCDP:
+		return nil
+	}
+	return fmt.Errorf("unsupported backend: %v, expected %v or %v", r.Backend, BackendSelenium, BackendCDP)
 }
 
 // NewStartRequestFromURL creates a new start request from URL
@@ -156,14 +169,15 @@ type WebElementCallResponse struct {
 
 // RunRequest represents group of selenium web elements calls
 type RunRequest struct {
-	SessionID      string
-	Browser        string
-	RemoteSelenium string             //remote selenium resource
-	Navigation     *NavigationOptions `description:"optional Get(url) navigation guard options"`
-	Actions        []*Action
-	ActionDelaysMs int           `description:"slows down action with specified delay"`
-	Commands       []interface{} `description:"list of selenium command: {web element selector}.WebElementMethod(params),  or WebDriverMethod(params), or wait map "`
-	Expect         interface{}   `description:"If specified it will validated response as actual"`
+	SessionID           string
+	Browser             string
+	RemoteSelenium      string             //remote selenium resource
+	Navigation          *NavigationOptions `description:"optional Get(url) navigation guard options"`
+	Actions             []*Action
+	ActionDelaysMs      int           `description:"slows down action with specified delay"`
+	ScreenshotDirectory string        `description:"screenshot(name), fullPageScreenshot(name) and (selector).screenshot(name) commands directory, default: workflow log directory screenshots subfolder"`
+	Commands            []interface{} `description:"list of selenium command: {web element selector}.WebElementMethod(params),  or WebDriverMethod(params), or wait map "`
+	Expect              interface{}   `description:"If specified it will validated response as actual"`
 }
 
 type NavigationOptions struct {
@@ -363,6 +377,165 @@ type CaptureExportResponse struct {
 	Network   []*NetworkTransaction
 }
 
+// InterceptRequest represents cdp backend network interception request, routes replace previously registered routes
+type InterceptRequest struct {
+	SessionID string
+	Routes    []*Route `description:"request routes, the first matching route handles request, unmatched requests continue to network"`
+}
+
+// Init initializes request
+func (r *InterceptRequest) Init() error {
+	if r.SessionID == "" {
+		r.SessionID = "localhost:4444"
+	}
+	for _, route := range r.Routes {
+		if err := route.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InterceptResponse represents interception response
+type InterceptResponse struct {
+	SessionID string
+	Routes    int
+}
+
+// Route represents intercepted request route, it either mocks response, aborts or continues request
+type Route struct {
+	URL     string            `required:"true" description:"request URL, either exact, glob with * wildcard or ~/regexp/"`
+	Method  string            `description:"optional HTTP method"`
+	Status  int               `description:"mocked response status code, default 200"`
+	Header  map[string]string `description:"mocked response headers"`
+	Body    interface{}       `description:"mocked response body, non text body is encoded as JSON"`
+	Abort   bool              `description:"flag to fail request with network error"`
+	DelayMs int               `description:"delay before request is fulfilled, aborted or continued"`
+	matcher *regexp.Regexp
+}
+
+// Init initializes route URL matcher
+func (r *Route) Init() error {
+	if r.URL == "" {
+		return fmt.Errorf("route URL was empty")
+	}
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(r.URL), `\*`, ".*") + "$"
+	if strings.HasPrefix(r.URL, "~/") {
+		expr = strings.Trim(r.URL[1:], "/")
+	}
+	var err error
+	if r.matcher, err = regexp.Compile(expr); err != nil {
+		return fmt.Errorf("invalid route URL %v: %w", r.URL, err)
+	}
+	if r.Status == 0 && !r.Abort {
+		r.Status = 200
+	}
+	return nil
+}
+
+// Match returns true if route matches request
+func (r *Route) Match(method, URL string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	return r.matcher != nil && r.matcher.MatchString(URL)
+}
+
+// DownloadsRequest represents cdp backend downloads request, it waits for started downloads to complete
+type DownloadsRequest struct {
+	SessionID string
+	TimeoutMs int         `description:"max wait time for downloads in progress, default 30000"`
+	Expect    interface{} `description:"If specified it will validated downloads as actual"`
+}
+
+// Init initializes request
+func (r *DownloadsRequest) Init() error {
+	if r.SessionID == "" {
+		r.SessionID = "localhost:4444"
+	}
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = 30000
+	}
+	return nil
+}
+
+// DownloadsResponse represents downloads response
+type DownloadsResponse struct {
+	SessionID string
+	Directory string
+	Downloads []*Download
+	Assert    *validator.AssertResponse
+}
+
+// Download represents browser file download
+type Download struct {
+	GUID     string `json:"-"`
+	URL      string
+	Filename string
+	Path     string
+	State    string `description:"inProgress, completed or canceled"`
+	Size     int64
+}
+
+// VisualAssertRequest represents visual regression assertion request, it compares viewport, full page or element screenshot with baseline image
+type VisualAssertRequest struct {
+	SessionID      string
+	Name           string   `description:"assertion name, default baseline file name"`
+	Selector       string   `description:"optional element selector, element screenshot is compared"`
+	FullPage       bool     `description:"flag to compare full page instead of viewport screenshot"`
+	Baseline       string   `required:"true" description:"baseline PNG location, missing baseline is created from the current screenshot"`
+	Threshold      float64  `description:"per pixel color distance threshold (0..1), default 0.1"`
+	Perceptual     bool     `description:"flag to use perceptual (YIQ) color distance instead of max RGB channel distance"`
+	MaxDiffPixels  int      `description:"max number of different pixels, default 0"`
+	MaxDiffRatio   float64  `description:"max ratio of different to compared pixels (0..1)"`
+	Ignore         []string `description:"selectors of regions excluded from comparison, i.e. timestamps, ads"`
+	UpdateBaseline bool     `description:"flag to replace baseline with the current screenshot"`
+}
+
+// Init initializes request
+func (r *VisualAssertRequest) Init() error {
+	if r.SessionID == "" {
+		r.SessionID = "localhost:4444"
+	}
+	if r.Threshold == 0 {
+		r.Threshold = 0.1
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *VisualAssertRequest) Validate() error {
+	if r.Baseline == "" {
+		return fmt.Errorf("baseline was empty")
+	}
+	if r.FullPage && r.Selector != "" {
+		return fmt.Errorf("fullPage and selector are mutually exclusive")
+	}
+	if r.Threshold < 0 || r.Threshold > 1 {
+		return fmt.Errorf("invalid threshold: %v, expected 0..1", r.Threshold)
+	}
+	return nil
+}
+
+// VisualAssertResponse represents visual assertion response
+type VisualAssertResponse struct {
+	Baseline   string
+	Actual     string `description:"actual screenshot location, written on failure"`
+	Diff       string `description:"diff image location, written on failure"`
+	Created    bool   `description:"flag indicating that baseline was created or updated"`
+	DiffPixels int
+	DiffRatio  float64
+	Assert     *validator.AssertResponse
+}
+
+// Assertion returns validation slice
+func (r *VisualAssertResponse) Assertion() []*assertly.Validation {
+	if r == nil || r.Assert == nil {
+		return []*assertly.Validation{}
+	}
+	return r.Assert.Assertion()
+}
+
 // MethodCall represents selenium call.
 type MethodCall struct {
 	Wait
@@ -0,0 +1,67 @@
+package webdriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+const downloadInProgress = "inProgress"
+
+// onDownload tracks Browser.downloadWillBegin and Browser.downloadProgress events
+func (d *cdpDriver) onDownload(method string, params json.RawMessage) {
+	event := struct {
+		GUID              string `json:"guid"`
+		URL               string `json:"url"`
+		SuggestedFilename string `json:"suggestedFilename"`
+		State             string `json:"state"`
+		ReceivedBytes     int64  `json:"receivedBytes"`
+	}{}
+	if err := json.Unmarshal(params, &event); err != nil {
+		return
+	}
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if method == "Browser.downloadWillBegin" {
+		d.downloads = append(d.downloads, &Download{
+			GUID:     event.GUID,
+			URL:      event.URL,
+			Filename: event.SuggestedFilename,
+			Path:     filepath.Join(d.downloadDir, event.SuggestedFilename),
+			State:    downloadInProgress,
+		})
+		return
+	}
+	for _, download := range d.downloads {
+		if download.GUID == event.GUID {
+			download.State = event.State
+			download.Size = event.ReceivedBytes
+		}
+	}
+}
+
+// waitDownloads waits for downloads in progress and returns downloads snapshot
+func (d *cdpDriver) waitDownloads(timeout time.Duration) ([]*Download, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var result = make([]*Download, 0)
+		pending := 0
+		d.mux.Lock()
+		for _, download := range d.downloads {
+			item := *download
+			if item.State == downloadInProgress {
+				pending++
+			}
+			result = append(result, &item)
+		}
+		d.mux.Unlock()
+		if pending == 0 {
+			return result, nil
+		}
+		if time.Now().After(deadline) {
+			return result, fmt.Errorf("%v download(s) still in progress after %v", pending, timeout)
+		}
+		time.Sleep(defaultWaitInterval)
+	}
+}
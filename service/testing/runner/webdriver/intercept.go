@@ -0,0 +1,105 @@
+package webdriver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+// pausedRequest represents Fetch.requestPaused event
+type pausedRequest struct {
+	RequestID string `json:"requestId"`
+	Request   struct {
+		URL    string `json:"url"`
+		Method string `json:"method"`
+	} `json:"request"`
+}
+
+// setRoutes replaces interception routes, interception is disabled when no routes are given
+func (d *cdpDriver) setRoutes(routes []*Route) error {
+	d.mux.Lock()
+	d.routes = routes
+	d.mux.Unlock()
+	if len(routes) == 0 {
+		return d.call("Fetch.disable", nil, nil)
+	}
+	return d.enableInterception()
+}
+
+func (d *cdpDriver) enableInterception() error {
+	return d.call("Fetch.enable", map[string]interface{}{
+		"patterns": []map[string]interface{}{{"urlPattern": "*", "requestStage": "Request"}},
+	}, nil)
+}
+
+func (d *cdpDriver) matchRoute(method, URL string) *Route {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	for _, route := range d.routes {
+		if route.Match(method, URL) {
+			return route
+		}
+	}
+	return nil
+}
+
+// onRequestPaused fulfills, fails or continues intercepted request
+func (d *cdpDriver) onRequestPaused(sessionID string, params json.RawMessage) {
+	paused := &pausedRequest{}
+	if err := json.Unmarshal(params, paused); err != nil {
+		return
+	}
+	route := d.matchRoute(paused.Request.Method, paused.Request.URL)
+	if route == nil {
+		_ = d.conn.call(sessionID, "Fetch.continueRequest", map[string]interface{}{"requestId": paused.RequestID}, nil)
+		return
+	}
+	if route.DelayMs > 0 {
+		time.Sleep(time.Duration(route.DelayMs) * time.Millisecond)
+	}
+	if route.Abort {
+		_ = d.conn.call(sessionID, "Fetch.failRequest", map[string]interface{}{"requestId": paused.RequestID, "errorReason": "Failed"}, nil)
+		return
+	}
+	body, contentType := routeBody(route.Body)
+	var headers = make([]map[string]string, 0, len(route.Header)+1)
+	hasContentType := false
+	for name, value := range route.Header {
+		if strings.EqualFold(name, "Content-Type") {
+			hasContentType = true
+		}
+		headers = append(headers, map[string]string{"name": name, "value": value})
+	}
+	if !hasContentType && contentType != "" {
+		headers = append(headers, map[string]string{"name": "Content-Type", "value": contentType})
+	}
+	_ = d.conn.call(sessionID, "Fetch.fulfillRequest", map[string]interface{}{
+		"requestId":       paused.RequestID,
+		"responseCode":    route.Status,
+		"responseHeaders": headers,
+		"body":            base64.StdEncoding.EncodeToString(body),
+	}, nil)
+}
+
+// routeBody returns mocked body and its default content type
+func routeBody(body interface{}) ([]byte, string) {
+	switch actual := body.(type) {
+	case nil:
+		return nil, ""
+	case string:
+		if toolbox.IsStructuredJSON(actual) {
+			return []byte(actual), "application/json"
+		}
+		return []byte(actual), "text/plain; charset=utf-8"
+	case []byte:
+		return actual, "application/octet-stream"
+	}
+	encoded, err := toolbox.AsJSONText(body)
+	if err != nil {
+		return []byte(toolbox.AsString(body)), "text/plain; charset=utf-8"
+	}
+	return []byte(strings.TrimSpace(encoded)), "application/json"
+}
@@ -0,0 +1,183 @@
+package webdriver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"path"
+	"strings"
+
+	"github.com/tebeka/selenium"
+	"github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/toolbox"
+)
+
+const (
+	//ScreenshotViewport represents visible viewport screenshot
+	ScreenshotViewport = "viewport"
+	//ScreenshotFullPage represents full scrollable page screenshot
+	ScreenshotFullPage = "fullPage"
+	//ScreenshotElement represents web element screenshot
+	ScreenshotElement = "element"
+
+	screenshotMethod         = "Screenshot"
+	fullPageScreenshotMethod = "FullPageScreenshot"
+)
+
+func isScreenshotMethod(method string) bool {
+	return method == screenshotMethod || method == fullPageScreenshotMethod
+}
+
+// screenshotDirectory returns screenshot directory, default: workflow log directory screenshots subfolder
+func screenshotDirectory(context *endly.Context, directory string) string {
+	if directory != "" {
+		return context.Expand(directory)
+	}
+	if context.LogDirectory != "" {
+		return path.Join(context.LogDirectory, "screenshots")
+	}
+	return path.Join("logs", context.SessionID, "screenshots")
+}
+
+// runScreenshot handles screenshot(name), fullPageScreenshot(name) and (selector).screenshot(name) commands
+func (s *service) runScreenshot(context *endly.Context, session *Session, action *Action, call *MethodCall, directory string) (map[string]interface{}, error) {
+	kind := ScreenshotViewport
+	if call.Method == fullPageScreenshotMethod {
+		kind = ScreenshotFullPage
+	}
+	var element selenium.WebElement
+	if action.Selector != nil {
+		if kind == ScreenshotFullPage {
+			return nil, fmt.Errorf("full page screenshot does not support element selector: %v", action.Selector.Value)
+		}
+		var err error
+		if element, err = s.lookupElement(session, action.Selector); err != nil {
+			return nil, fmt.Errorf("failed to lookup element: %v %v, %v", action.Selector.By, action.Selector.Value, err)
+		}
+		kind = ScreenshotElement
+	}
+	data, err := s.screenshot(session, kind, element)
+	if err != nil {
+		return nil, err
+	}
+	name := ""
+	if len(call.Parameters) > 0 {
+		name = toolbox.AsString(call.Parameters[0])
+	}
+	if name == "" {
+		session.screenshots++
+		name = fmt.Sprintf("screenshot-%03d", session.screenshots)
+	}
+	URL, err := s.saveImage(context, url.Join(location.NewResource(directory).URL, name), data)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{
+		"Path": location.NewResource(URL).Path(),
+		"Kind": kind,
+	}
+	if config, err := png.DecodeConfig(bytes.NewReader(data)); err == nil {
+		result["Width"], result["Height"] = config.Width, config.Height
+	}
+	return result, nil
+}
+
+// screenshot takes PNG screenshot of the viewport, full page or element
+func (s *service) screenshot(session *Session, kind string, element selenium.WebElement) ([]byte, error) {
+	switch kind {
+	case ScreenshotElement:
+		return element.Screenshot(true)
+	case ScreenshotFullPage:
+		return fullPageScreenshot(session)
+	}
+	return session.driver.Screenshot()
+}
+
+// saveImage saves PNG image, png extension is added if missing
+func (s *service) saveImage(context *endly.Context, URL string, data []byte) (string, error) {
+	if path.Ext(URL) == "" {
+		URL += ".png"
+	}
+	if err := s.fs.Upload(context.Background(), URL, 0644, bytes.NewReader(data)); err != nil {
+		return "", fmt.Errorf("failed to save image %v: %w", URL, err)
+	}
+	return URL, nil
+}
+
+// fullPageScreenshot captures page beyond viewport with DevTools protocol (Chrome only)
+func fullPageScreenshot(session *Session) ([]byte, error) {
+	execute, err := devToolsExecutor(session)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := execute("Page.getLayoutMetrics", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	type size struct {
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	}
+	metrics := struct {
+		CSSContentSize *size `json:"cssContentSize"`
+		ContentSize    *size `json:"contentSize"`
+	}{}
+	if err = json.Unmarshal(raw, &metrics); err != nil {
+		return nil, err
+	}
+	content := metrics.CSSContentSize
+	if content == nil {
+		content = metrics.ContentSize
+	}
+	if content == nil {
+		return nil, fmt.Errorf("failed to get page content size")
+	}
+	if raw, err = execute("Page.captureScreenshot", map[string]interface{}{
+		"format":                "png",
+		"captureBeyondViewport": true,
+		"clip":                  map[string]interface{}{"x": 0, "y": 0, "width": content.Width, "height": content.Height, "scale": 1},
+	}); err != nil {
+		return nil, err
+	}
+	result := struct {
+		Data string `json:"data"`
+	}{}
+	if err = json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(result.Data)
+}
+
+// devToolsExecutor returns DevTools command executor, either cdp backend or chromedriver CDP endpoint
+func devToolsExecutor(session *Session) (func(method string, params map[string]interface{}) (json.RawMessage, error), error) {
+	if driver, ok := session.driver.(*cdpDriver); ok {
+		return driver.Execute, nil
+	}
+	if !strings.EqualFold(session.Browser, ChromeBrowser) {
+		return nil, fmt.Errorf("DevTools protocol is not supported with %v browser", session.Browser)
+	}
+	remote := session.Remote
+	if remote == "" {
+		host, port := pair(session.SessionID)
+		remote = fmt.Sprintf("http://%v:%v/wd/hub", host, port)
+	}
+	wdSession := session.driver.SessionID()
+	return func(method string, params map[string]interface{}) (json.RawMessage, error) {
+		return cdpExecute(remote, wdSession, method, params)
+	}, nil
+}
+
+// screenshotKey returns screenshot result key
+func screenshotKey(action *Action) string {
+	if action.Key != "" {
+		return action.Key
+	}
+	if action.Selector != nil && action.Selector.Key != "" {
+		return action.Selector.Key
+	}
+	return "screenshot"
+}
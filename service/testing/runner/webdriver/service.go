@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/viant/endly/model/msg"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -41,6 +42,11 @@ const (
 	Selenium       = "webdriver"
 	runnerCaller   = "runnerCaller"
 
+	//BackendSelenium represents webdriver protocol backend
+	BackendSelenium = "selenium"
+	//BackendCDP represents Chrome DevTools protocol backend
+	BackendCDP = "cdp"
+
 	defaultFindElementTimeout = 10 * time.Second
 )
 
@@ -121,7 +127,7 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 		return response, nil
 	}
 	var state = context.State()
-
+	directory := screenshotDirectory(context, request.ScreenshotDirectory)
 	actionDelay := time.Duration(request.ActionDelaysMs) * time.Millisecond
 	for _, action := range request.Actions {
 		for _, call := range action.Calls {
@@ -130,6 +136,14 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 					call.Parameters[i] = state.Expand(item)
 				}
 			}
+			if isScreenshotMethod(call.Method) {
+				screenshot, err := s.runScreenshot(context, session, action, call, directory)
+				if err != nil {
+					return nil, err
+				}
+				response.Data[screenshotKey(action)] = screenshot
+				continue
+			}
 			if action.Selector == nil {
 				if session != nil && isGetMethod(call.Method) && len(call.Parameters) == 1 && toolbox.IsString(call.Parameters[0]) {
 					URL := toolbox.AsString(call.Parameters[0])
@@ -321,17 +335,8 @@ func (s *service) callWebElement(context *endly.Context, request *WebElementCall
 		return nil, fmt.Errorf("invalid selector: %v", err)
 	}
 	var selector = request.Selector
-	var element selenium.WebElement
-
-	err = session.driver.WaitWithTimeout(func(wd selenium.WebDriver) (bool, error) {
-		element, err = session.driver.FindElement(selector.By, selector.Value)
-		if element != nil {
-			return true, nil
-		}
-		return false, nil
-	}, defaultFindElementTimeout)
-
-	if err != nil || element == nil {
+	element, err := s.lookupElement(session, selector)
+	if err != nil {
 		response.LookupError = fmt.Sprintf("failed to lookup element: %v %v, %v", selector.By, selector.Value, err)
 		return response, nil
 	}
@@ -357,6 +362,20 @@ func (s *service) callWebElement(context *endly.Context, request *WebElementCall
 	return response, nil
 }
 
+// lookupElement waits for the first element matching selector
+func (s *service) lookupElement(session *Session, selector *WebElementSelector) (selenium.WebElement, error) {
+	var element selenium.WebElement
+	var err error
+	err = session.driver.WaitWithTimeout(func(wd selenium.WebDriver) (bool, error) {
+		element, err = session.driver.FindElement(selector.By, selector.Value)
+		return element != nil, nil
+	}, defaultFindElementTimeout)
+	if err == nil && element == nil {
+		err = fmt.Errorf("element was not found")
+	}
+	return element, err
+}
+
 func (s *service) ensureVisible(element selenium.WebElement) error {
 	var err error
 	var ok bool
@@ -477,6 +496,9 @@ func (s *service) stop(context *endly.Context, request *StopRequest) (*StopRespo
 }
 
 func (s *service) start(context *endly.Context, request *StartRequest) (*StartResponse, error) {
+	if request.Backend == BackendCDP {
+		return s.startChrome(context, request)
+	}
 	target, err := context.ExpandResource(request.Target)
 	if err != nil {
 		return nil, err
@@ -551,6 +573,37 @@ func (s *service) start(context *endly.Context, request *StartRequest) (*StartRe
 	return response, nil
 }
 
+// startChrome starts Chrome with DevTools protocol enabled, browser is driven directly without webdriver server
+func (s *service) startChrome(context *endly.Context, request *StartRequest) (*StartResponse, error) {
+	sessionID := fmt.Sprintf("localhost:%v", request.Port)
+	sessions := Sessions(context)
+	if session, ok := sessions[sessionID]; ok {
+		session.Close()
+	}
+	chrome, err := startChrome(request.Chrome, request.Port, !request.Headed, request.Capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start chrome: %w", err)
+	}
+	downloadDirectory := filepath.Join(chrome.userDataDir, "Downloads")
+	if request.DownloadDirectory != "" {
+		downloadDirectory = location.NewResource(context.Expand(request.DownloadDirectory)).Path()
+	}
+	session := &Session{
+		SessionID:         sessionID,
+		Browser:           ChromeBrowser,
+		Backend:           BackendCDP,
+		Remote:            chrome.endpoint,
+		DownloadDirectory: downloadDirectory,
+		Capabilities:      request.Capabilities,
+		chrome:            chrome,
+	}
+	sessions[sessionID] = session
+	context.Deffer(func() {
+		chrome.stop()
+	})
+	return &StartResponse{Pid: chrome.Pid(), DriverPath: chrome.cmd.Path, SessionID: sessionID}, nil
+}
+
 func (s *service) session(context *endly.Context, sessionID string) (*Session, error) {
 	sessions := Sessions(context)
 	if seleniumSession, ok := sessions[sessionID]; ok {
@@ -567,6 +620,9 @@ func (s *service) openSession(context *endly.Context, request *OpenSessionReques
 	if !ok {
 		return nil, fmt.Errorf("webdriver service not running - start ?")
 	}
+	if session.Backend == BackendCDP {
+		return s.openDevToolsSession(context, session)
+	}
 	if session.driver != nil {
 		_ = session.driver.Close()
 	}
@@ -602,6 +658,65 @@ func (s *service) openSession(context *endly.Context, request *OpenSessionReques
 	return session, nil
 }
 
+// openDevToolsSession opens a new page target in Chrome started with cdp backend
+func (s *service) openDevToolsSession(context *endly.Context, session *Session) (*Session, error) {
+	if session.driver != nil {
+		_ = session.driver.Quit()
+	}
+	driver, err := newCDPDriver(session.Remote, session.DownloadDirectory)
+	if err != nil {
+		return nil, err
+	}
+	session.driver = driver
+	context.Deffer(func() {
+		_ = driver.Quit()
+	})
+	return session, nil
+}
+
+// devToolsDriver returns cdp backend driver, session is opened if needed
+func (s *service) devToolsDriver(context *endly.Context, sessionID string) (*cdpDriver, error) {
+	session, err := s.session(context, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Backend != BackendCDP {
+		return nil, fmt.Errorf("session %v: unsupported %v backend, start browser with %v backend", sessionID, session.Backend, BackendCDP)
+	}
+	if session.driver == nil {
+		if session, err = s.openSession(context, &OpenSessionRequest{SessionID: sessionID}); err != nil {
+			return nil, err
+		}
+	}
+	return session.driver.(*cdpDriver), nil
+}
+
+func (s *service) intercept(context *endly.Context, request *InterceptRequest) (*InterceptResponse, error) {
+	driver, err := s.devToolsDriver(context, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err = driver.setRoutes(request.Routes); err != nil {
+		return nil, err
+	}
+	return &InterceptResponse{SessionID: request.SessionID, Routes: len(request.Routes)}, nil
+}
+
+func (s *service) downloads(context *endly.Context, request *DownloadsRequest) (*DownloadsResponse, error) {
+	driver, err := s.devToolsDriver(context, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	response := &DownloadsResponse{SessionID: request.SessionID, Directory: driver.downloadDir}
+	if response.Downloads, err = driver.waitDownloads(time.Duration(request.TimeoutMs) * time.Millisecond); err != nil {
+		return response, err
+	}
+	if request.Expect != nil {
+		response.Assert, err = validator.Assert(context, request, request.Expect, response.Downloads, "webdriver.downloads", "assert webdriver downloads")
+	}
+	return response, err
+}
+
 func (s *service) registerRoutes() {
 	s.Register(&endly.Route{
 		Action: "start",
@@ -736,6 +851,63 @@ func (s *service) registerRoutes() {
 		},
 	})
 
+	s.Register(&endly.Route{
+		Action: "intercept",
+		RequestInfo: &endly.ActionInfo{
+			Description: "mock, abort or delay browser network requests (cdp backend)",
+		},
+		RequestProvider: func() interface{} {
+			return &InterceptRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &InterceptResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*InterceptRequest); ok {
+				return s.intercept(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "downloads",
+		RequestInfo: &endly.ActionInfo{
+			Description: "wait for browser file downloads and list them (cdp backend)",
+		},
+		RequestProvider: func() interface{} {
+			return &DownloadsRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &DownloadsResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*DownloadsRequest); ok {
+				return s.downloads(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "visualAssert",
+		RequestInfo: &endly.ActionInfo{
+			Description: "compare viewport, full page or element screenshot with baseline image",
+		},
+		RequestProvider: func() interface{} {
+			return &VisualAssertRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &VisualAssertResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*VisualAssertRequest); ok {
+				return s.visualAssert(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.Register(&endly.Route{
 		Action: "capture-start",
 		RequestInfo: &endly.ActionInfo{
@@ -7,16 +7,20 @@ import (
 
 // Session represents a selenium session
 type Session struct {
-	SessionID    string
-	Browser      string
-	Pid          int
-	Server       string
-	Remote       string
-	Capture      *CaptureState
-	Net          *netTracker
-	driver       selenium.WebDriver
-	service      *selenium.Service
-	Capabilities []string
+	SessionID         string
+	Browser           string
+	Backend           string
+	Pid               int
+	Server            string
+	Remote            string
+	DownloadDirectory string
+	Capture           *CaptureState
+	Net               *netTracker
+	driver            selenium.WebDriver
+	service           *selenium.Service
+	chrome            *chromeProcess
+	screenshots       int
+	Capabilities      []string
 }
 
 func (s Session) Driver() selenium.WebDriver {
@@ -30,6 +34,9 @@ func (s Session) Close() {
 	if s.service != nil {
 		s.service.Stop()
 	}
+	if s.chrome != nil {
+		s.chrome.stop()
+	}
 }
 
 // SeleniumSessions reprents selenium sessions.
@@ -0,0 +1,309 @@
+package webdriver
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/tebeka/selenium"
+	"github.com/viant/assertly"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/toolbox"
+)
+
+// maxYIQDelta represents max squared YIQ distance between two colors
+const maxYIQDelta = 35215.0
+
+const geometryScript = `return {scrollX: window.scrollX, scrollY: window.scrollY, width: window.innerWidth, pageWidth: document.documentElement.scrollWidth};`
+
+var (
+	diffColor    = color.RGBA{R: 255, A: 255}
+	ignoredColor = color.RGBA{R: 200, G: 220, B: 255, A: 255}
+)
+
+// imageDiff represents image comparison result
+type imageDiff struct {
+	pixels   int
+	compared int
+	image    *image.RGBA
+}
+
+func (d *imageDiff) ratio() float64 {
+	if d.compared == 0 {
+		return 0
+	}
+	return float64(d.pixels) / float64(d.compared)
+}
+
+func (s *service) visualAssert(context *endly.Context, request *VisualAssertRequest) (*VisualAssertResponse, error) {
+	session, err := s.session(context, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.driver == nil {
+		if session, err = s.openSession(context, &OpenSessionRequest{SessionID: request.SessionID}); err != nil {
+			return nil, err
+		}
+	}
+	kind := ScreenshotViewport
+	if request.FullPage {
+		kind = ScreenshotFullPage
+	}
+	var element selenium.WebElement
+	if request.Selector != "" {
+		selector := &WebElementSelector{Value: context.Expand(request.Selector)}
+		_ = selector.Init()
+		if element, err = s.lookupElement(session, selector); err != nil {
+			return nil, fmt.Errorf("failed to lookup element: %v, %v", selector.Value, err)
+		}
+		kind = ScreenshotElement
+	}
+	data, err := s.screenshot(session, kind, element)
+	if err != nil {
+		return nil, err
+	}
+	baselineURL := location.NewResource(context.Expand(request.Baseline)).URL
+	if path.Ext(baselineURL) == "" {
+		baselineURL += ".png"
+	}
+	name := request.Name
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(baselineURL), path.Ext(baselineURL))
+	}
+	response := &VisualAssertResponse{Baseline: location.NewResource(baselineURL).Path()}
+	assertRequest, _ := validator.NewAssertRequestFromContext(context, request, nil, nil, "webdriver.visualAssert", "visual assert "+name)
+	validation := &assertly.Validation{TagID: assertRequest.TagID, Description: assertRequest.Description}
+	response.Assert = &validator.AssertResponse{Validation: validation}
+
+	exists, _ := s.fs.Exists(context.Background(), baselineURL)
+	if !exists || request.UpdateBaseline {
+		if _, err = s.saveImage(context, baselineURL, data); err != nil {
+			return nil, err
+		}
+		response.Created = true
+		validation.PassedCount++
+		return response, nil
+	}
+	baselineData, err := s.fs.DownloadWithURL(context.Background(), baselineURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline %v: %w", baselineURL, err)
+	}
+	expected, err := png.Decode(bytes.NewReader(baselineData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode baseline %v: %w", baselineURL, err)
+	}
+	actual, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	failurePath := "visual/" + name
+	if expected.Bounds().Size() != actual.Bounds().Size() {
+		validation.AddFailure(&assertly.Failure{
+			Source:   request.Baseline,
+			Path:     failurePath,
+			Reason:   "SizeViolation",
+			Expected: sizeText(expected.Bounds()),
+			Actual:   sizeText(actual.Bounds()),
+			Message:  fmt.Sprintf("screenshot size %v was different than baseline %v", sizeText(actual.Bounds()), sizeText(expected.Bounds())),
+		})
+		response.Actual, err = s.saveVisualArtifact(context, baselineURL, "actual", data)
+		return response, err
+	}
+	regions, err := ignoreRegions(session, kind, element, request.Ignore, actual.Bounds().Dx())
+	if err != nil {
+		return nil, err
+	}
+	diff := compareImages(expected, actual, request.Threshold, request.Perceptual, regions)
+	response.DiffPixels, response.DiffRatio = diff.pixels, diff.ratio()
+	if withinTolerance(request, diff) {
+		validation.PassedCount++
+		return response, nil
+	}
+	validation.AddFailure(&assertly.Failure{
+		Source:   request.Baseline,
+		Path:     failurePath,
+		Reason:   "VisualViolation",
+		Expected: tolerance(request),
+		Actual:   fmt.Sprintf("%v pixels (%.4f)", diff.pixels, diff.ratio()),
+		Message:  fmt.Sprintf("%v of %v pixels (%.4f) were different than baseline, allowed: %v", diff.pixels, diff.compared, diff.ratio(), tolerance(request)),
+	})
+	if response.Actual, err = s.saveVisualArtifact(context, baselineURL, "actual", data); err != nil {
+		return response, err
+	}
+	buffer := new(bytes.Buffer)
+	if err = png.Encode(buffer, diff.image); err != nil {
+		return response, err
+	}
+	response.Diff, err = s.saveVisualArtifact(context, baselineURL, "diff", buffer.Bytes())
+	return response, err
+}
+
+// saveVisualArtifact saves image next to the baseline, i.e. home.png -> home.diff.png
+func (s *service) saveVisualArtifact(context *endly.Context, baselineURL, suffix string, data []byte) (string, error) {
+	URL := strings.TrimSuffix(baselineURL, path.Ext(baselineURL)) + "." + suffix + ".png"
+	URL, err := s.saveImage(context, URL, data)
+	if err != nil {
+		return "", err
+	}
+	return location.NewResource(URL).Path(), nil
+}
+
+func withinTolerance(request *VisualAssertRequest, diff *imageDiff) bool {
+	if request.MaxDiffPixels == 0 && request.MaxDiffRatio == 0 {
+		return diff.pixels == 0
+	}
+	if request.MaxDiffPixels > 0 && diff.pixels > request.MaxDiffPixels {
+		return false
+	}
+	if request.MaxDiffRatio > 0 && diff.ratio() > request.MaxDiffRatio {
+		return false
+	}
+	return true
+}
+
+func tolerance(request *VisualAssertRequest) string {
+	var result = make([]string, 0)
+	if request.MaxDiffPixels > 0 {
+		result = append(result, fmt.Sprintf("<= %v pixels", request.MaxDiffPixels))
+	}
+	if request.MaxDiffRatio > 0 {
+		result = append(result, fmt.Sprintf("<= %v ratio", request.MaxDiffRatio))
+	}
+	if len(result) == 0 {
+		return "0 pixels"
+	}
+	return strings.Join(result, ", ")
+}
+
+func sizeText(bounds image.Rectangle) string {
+	return fmt.Sprintf("%vx%v", bounds.Dx(), bounds.Dy())
+}
+
+// compareImages compares same size images, different pixels are marked red on faded baseline in the diff image
+func compareImages(expected, actual image.Image, threshold float64, perceptual bool, ignore []image.Rectangle) *imageDiff {
+	expectedMin, actualMin := expected.Bounds().Min, actual.Bounds().Min
+	width, height := expected.Bounds().Dx(), expected.Bounds().Dy()
+	result := &imageDiff{image: image.NewRGBA(image.Rect(0, 0, width, height))}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if isIgnored(x, y, ignore) {
+				result.image.Set(x, y, ignoredColor)
+				continue
+			}
+			result.compared++
+			expectedPixel := expected.At(expectedMin.X+x, expectedMin.Y+y)
+			if colorDistance(expectedPixel, actual.At(actualMin.X+x, actualMin.Y+y), perceptual) > threshold {
+				result.pixels++
+				result.image.Set(x, y, diffColor)
+				continue
+			}
+			result.image.Set(x, y, fade(expectedPixel))
+		}
+	}
+	return result
+}
+
+func isIgnored(x, y int, regions []image.Rectangle) bool {
+	point := image.Point{X: x, Y: y}
+	for _, region := range regions {
+		if point.In(region) {
+			return true
+		}
+	}
+	return false
+}
+
+// colorDistance returns normalized (0..1) distance between colors blended on white background
+func colorDistance(c1, c2 color.Color, perceptual bool) float64 {
+	r1, g1, b1 := blend(c1)
+	r2, g2, b2 := blend(c2)
+	if !perceptual {
+		return math.Max(math.Abs(r1-r2), math.Max(math.Abs(g1-g2), math.Abs(b1-b2))) / 255
+	}
+	y := rgb2y(r1, g1, b1) - rgb2y(r2, g2, b2)
+	i := rgb2i(r1, g1, b1) - rgb2i(r2, g2, b2)
+	q := rgb2q(r1, g1, b1) - rgb2q(r2, g2, b2)
+	delta := 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+	return math.Sqrt(delta / maxYIQDelta)
+}
+
+func blend(c color.Color) (float64, float64, float64) {
+	r, g, b, a := c.RGBA()
+	alpha := float64(a) / 0xffff
+	white := 255 * (1 - alpha)
+	//RGBA returns alpha premultiplied 16 bit values
+	return float64(r>>8) + white, float64(g>>8) + white, float64(b>>8) + white
+}
+
+func rgb2y(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func rgb2i(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func rgb2q(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
+
+func fade(c color.Color) color.Color {
+	r, g, b := blend(c)
+	gray := uint8(255 - (255-rgb2y(r, g, b))*0.1)
+	return color.RGBA{R: gray, G: gray, B: gray, A: 255}
+}
+
+// ignoreRegions returns ignored element regions in screenshot pixel coordinates
+func ignoreRegions(session *Session, kind string, element selenium.WebElement, selectors []string, imageWidth int) ([]image.Rectangle, error) {
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+	originX, originY, cssWidth := 0.0, 0.0, 0.0
+	geometry := map[string]interface{}{}
+	if value, err := session.driver.ExecuteScript(geometryScript, nil); err == nil && value != nil && toolbox.IsMap(value) {
+		geometry = toolbox.AsMap(value)
+	}
+	switch kind {
+	case ScreenshotElement:
+		position, err := element.Location()
+		if err != nil {
+			return nil, err
+		}
+		size, err := element.Size()
+		if err != nil {
+			return nil, err
+		}
+		originX, originY, cssWidth = float64(position.X), float64(position.Y), float64(size.Width)
+	case ScreenshotFullPage:
+		cssWidth = toolbox.AsFloat(geometry["pageWidth"])
+	default:
+		originX, originY = toolbox.AsFloat(geometry["scrollX"]), toolbox.AsFloat(geometry["scrollY"])
+		cssWidth = toolbox.AsFloat(geometry["width"])
+	}
+	scale := 1.0
+	if cssWidth > 0 {
+		scale = float64(imageWidth) / cssWidth
+	}
+	var result = make([]image.Rectangle, 0)
+	for _, selector := range selectors {
+		by, value := WebSelector(selector).ByAndValue()
+		elements, err := session.driver.FindElements(by, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find ignored elements %v, %w", selector, err)
+		}
+		for _, candidate := range elements {
+			position, err := candidate.Location()
+			if err != nil {
+				return nil, err
+			}
+			size, err := candidate.Size()
+			if err != nil {
+				return nil, err
+			}
+			x, y := float64(position.X)-originX, float64(position.Y)-originY
+			result = append(result, image.Rect(
+				int(math.Floor(x*scale)), int(math.Floor(y*scale)),
+				int(math.Ceil((x+float64(size.Width))*scale)), int(math.Ceil((y+float64(size.Height))*scale))))
+		}
+	}
+	return result, nil
+}
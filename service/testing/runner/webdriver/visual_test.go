@@ -0,0 +1,115 @@
+package webdriver
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+)
+
+func testImage(t *testing.T, block image.Rectangle) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 200; x++ {
+			img.Set(x, y, color.White)
+			if (image.Point{X: x, Y: y}).In(block) {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+	}
+	buffer := new(bytes.Buffer)
+	assert.Nil(t, png.Encode(buffer, img))
+	return buffer.Bytes()
+}
+
+func TestService_VisualAssert(t *testing.T) {
+	devTools := newFakeDevTools()
+	defer devTools.Close()
+	context := newDevToolsContext(devTools)
+	defer context.Close()
+	baseline := filepath.Join(t.TempDir(), "home.png")
+	changed := image.Rect(20, 25, 40, 35)
+
+	var useCases = []struct {
+		description string
+		screenshot  []byte
+		request     *VisualAssertRequest
+		created     bool
+		diffPixels  int
+		failed      int
+	}{
+		{description: "baseline created", screenshot: testImage(t, image.Rectangle{}), request: &VisualAssertRequest{}, created: true},
+		{description: "identical", screenshot: testImage(t, image.Rectangle{}), request: &VisualAssertRequest{}},
+		{description: "changed", screenshot: testImage(t, changed), request: &VisualAssertRequest{}, diffPixels: 200, failed: 1},
+		{description: "within tolerance", screenshot: testImage(t, changed), request: &VisualAssertRequest{MaxDiffRatio: 0.05}, diffPixels: 200},
+		{description: "ignored region", screenshot: testImage(t, changed), request: &VisualAssertRequest{Ignore: []string{"#name"}}},
+		{description: "perceptual", screenshot: testImage(t, changed), request: &VisualAssertRequest{Perceptual: true}, diffPixels: 200, failed: 1},
+	}
+	for _, useCase := range useCases {
+		devTools.screenshot = useCase.screenshot
+		useCase.request.SessionID = "localhost:9222"
+		useCase.request.Baseline = baseline
+		response := &VisualAssertResponse{}
+		err := endly.Run(context, useCase.request, response)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.created, response.Created, useCase.description)
+		assert.EqualValues(t, useCase.diffPixels, response.DiffPixels, useCase.description)
+		if assert.Len(t, response.Assertion(), 1, useCase.description) {
+			assert.EqualValues(t, useCase.failed, response.Assertion()[0].FailedCount, useCase.description)
+		}
+		if useCase.failed == 0 {
+			continue
+		}
+		assert.EqualValues(t, "visual/home", response.Assert.Failures[0].Path, useCase.description)
+		diff, err := os.ReadFile(response.Diff)
+		if assert.Nil(t, err, useCase.description) {
+			img, err := png.Decode(bytes.NewReader(diff))
+			assert.Nil(t, err, useCase.description)
+			assert.EqualValues(t, color.RGBA{R: 255, A: 255}, img.At(25, 30), useCase.description)
+		}
+		_, err = os.Stat(response.Actual)
+		assert.Nil(t, err, useCase.description)
+	}
+}
+
+func TestService_Screenshot(t *testing.T) {
+	devTools := newFakeDevTools()
+	defer devTools.Close()
+	devTools.screenshot = testImage(t, image.Rectangle{})
+	context := newDevToolsContext(devTools)
+	defer context.Close()
+	directory := t.TempDir()
+
+	response := &RunResponse{}
+	err := endly.Run(context, &RunRequest{
+		SessionID:           "localhost:9222",
+		ScreenshotDirectory: directory,
+		Commands: []interface{}{
+			"get(http://127.0.0.1/form.html)",
+			"home = screenshot(home)",
+			"(#name).screenshot",
+		},
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	home, ok := response.Data["home"].(map[string]interface{})
+	if assert.True(t, ok) {
+		assert.EqualValues(t, filepath.Join(directory, "home.png"), home["Path"])
+		assert.EqualValues(t, ScreenshotViewport, home["Kind"])
+		assert.EqualValues(t, 200, home["Width"])
+	}
+	element, ok := response.Data["screenshot"].(map[string]interface{})
+	if assert.True(t, ok) {
+		assert.EqualValues(t, filepath.Join(directory, "screenshot-001.png"), element["Path"])
+		assert.EqualValues(t, ScreenshotElement, element["Kind"])
+	}
+}
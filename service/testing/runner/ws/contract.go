@@ -0,0 +1,161 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/endly/service/testing/validator"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	//ProtocolWebSocket represents WebSocket protocol
+	ProtocolWebSocket = "websocket"
+	//ProtocolSSE represents Server-Sent Events protocol
+	ProtocolSSE = "sse"
+	//DefaultSessionID represents default session ID
+	DefaultSessionID = "default"
+)
+
+// ConnectRequest represents WebSocket connect or SSE subscribe request
+type ConnectRequest struct {
+	ID        string `description:"session ID, default: default"`
+	URL       string `required:"true" description:"ws:// or wss:// URL for WebSocket, http:// or https:// URL for Server-Sent Events"`
+	Protocol  string `description:"websocket or sse, default: inferred from URL scheme"`
+	Header    http.Header
+	TimeoutMs int `description:"connection timeout, default 30000"`
+}
+
+// Init initializes request
+func (r *ConnectRequest) Init() error {
+	if r.ID == "" {
+		r.ID = DefaultSessionID
+	}
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = 30000
+	}
+	if r.Protocol == "" {
+		r.Protocol = ProtocolWebSocket
+		if strings.HasPrefix(r.URL, "http://") || strings.HasPrefix(r.URL, "https://") {
+			r.Protocol = ProtocolSSE
+		}
+	}
+	r.Protocol = strings.ToLower(r.Protocol)
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *ConnectRequest) Validate() error {
+	if r.URL == "" {
+		return errors.New("URL was empty")
+	}
+	if r.Protocol != ProtocolWebSocket && r.Protocol != ProtocolSSE {
+		return fmt.Errorf("unsupported protocol: %v", r.Protocol)
+	}
+	return nil
+}
+
+// ConnectResponse represents connect response
+type ConnectResponse struct {
+	ID         string
+	StatusCode int
+	Header     http.Header
+}
+
+// SendRequest represents WebSocket send request
+type SendRequest struct {
+	ID      string        `description:"session ID, default: default"`
+	Message interface{}   `description:"text message, non text message is JSON encoded"`
+	Batch   []interface{} `description:"messages to send sequentially"`
+	Binary  bool          `description:"flag to send messages as binary frames"`
+}
+
+// Init initializes request
+func (r *SendRequest) Init() error {
+	if r.ID == "" {
+		r.ID = DefaultSessionID
+	}
+	if r.Message != nil {
+		r.Batch = append([]interface{}{r.Message}, r.Batch...)
+		r.Message = nil
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *SendRequest) Validate() error {
+	if len(r.Batch) == 0 {
+		return errors.New("message was empty")
+	}
+	return nil
+}
+
+// SendResponse represents send response
+type SendResponse struct {
+	Sent int
+}
+
+// ReceiveRequest represents receive request
+type ReceiveRequest struct {
+	ID        string      `description:"session ID, default: default"`
+	Count     int         `description:"number of matching messages to receive, if 0 all matching messages received within timeout are returned"`
+	TimeoutMs int         `description:"receive timeout, default 5000"`
+	Match     string      `description:"criteria matching messages, received message is accessible as $message, i.e. $message.JSONBody.type:report, non matching messages are skipped"`
+	Expect    interface{} `description:"If specified it will validated received messages as actual"`
+}
+
+// Init initializes request
+func (r *ReceiveRequest) Init() error {
+	if r.ID == "" {
+		r.ID = DefaultSessionID
+	}
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = 5000
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *ReceiveRequest) Validate() error {
+	if r.Count < 0 {
+		return fmt.Errorf("invalid count: %v", r.Count)
+	}
+	return nil
+}
+
+// ReceiveResponse represents receive response
+type ReceiveResponse struct {
+	Received []*Message
+	Skipped  int `description:"number of received messages not matching criteria"`
+	Assert   *validator.AssertResponse
+}
+
+// CloseRequest represents close request
+type CloseRequest struct {
+	ID string `description:"session ID, default: default"`
+}
+
+// Init initializes request
+func (r *CloseRequest) Init() error {
+	if r.ID == "" {
+		r.ID = DefaultSessionID
+	}
+	return nil
+}
+
+// CloseResponse represents close response
+type CloseResponse struct {
+	Received int `description:"number of messages received by session"`
+	Sent     int `description:"number of messages sent by session"`
+}
+
+// Message represents received message
+type Message struct {
+	Type      string      `description:"text, binary or event (SSE)"`
+	Event     string      `json:",omitempty" description:"SSE event type"`
+	EventID   string      `json:",omitempty" description:"SSE event id"`
+	Body      string      `description:"message data, binary data is base64 encoded"`
+	JSONBody  interface{} `json:",omitempty" description:"structured JSON message data"`
+	Timestamp time.Time
+}
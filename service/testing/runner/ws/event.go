@@ -0,0 +1,43 @@
+package ws
+
+import (
+	"fmt"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+)
+
+// Messages returns messages
+func (r *SendRequest) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	for _, message := range r.Batch {
+		text, ok := message.(string)
+		if !ok {
+			text, _ = toolbox.AsJSONText(message)
+		}
+		response = append(response, msg.NewMessage(msg.NewStyled(fmt.Sprintf("send %v", r.ID), msg.MessageStyleGeneric), msg.NewStyled("ws.SendRequest", msg.MessageStyleGeneric),
+			msg.NewStyled(text, msg.MessageStyleInput),
+		))
+	}
+	return response
+}
+
+// Messages returns messages
+func (r *ReceiveResponse) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	for _, message := range r.Received {
+		response = append(response, msg.NewMessage(msg.NewStyled(fmt.Sprintf("received %v", message.Type), msg.MessageStyleGeneric), msg.NewStyled("ws.ReceiveResponse", msg.MessageStyleGeneric),
+			msg.NewStyled(message.Body, msg.MessageStyleOutput),
+		))
+	}
+	return response
+}
+
+// IsInput returns this request (CLI reporter interface)
+func (r *SendRequest) IsInput() bool {
+	return true
+}
+
+// IsOutput returns this response (CLI reporter interface)
+func (r *ReceiveResponse) IsOutput() bool {
+	return true
+}
@@ -0,0 +1,301 @@
+package ws
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"time"
+)
+
+const (
+	//ServiceID represents WebSocket and SSE runner service id.
+	ServiceID = "ws/runner"
+	//TripsKey represents state key with session sent and received messages keyed by session ID
+	TripsKey = "wsTrips"
+	//TripSent represents session sent messages key
+	TripSent = "Sent"
+	//TripReceived represents session received messages key
+	TripReceived = "Received"
+	//MessageKey represents received message key used by receive match criteria
+	MessageKey = "message"
+)
+
+type service struct {
+	*endly.AbstractService
+	sessions map[string]*session
+}
+
+func (s *service) connect(context *endly.Context, request *ConnectRequest) (*ConnectResponse, error) {
+	s.Mutex().Lock()
+	previous, ok := s.sessions[request.ID]
+	s.Mutex().Unlock()
+	if ok {
+		_ = previous.close()
+	}
+	var response = &ConnectResponse{ID: request.ID}
+	session, err := connect(request, response)
+	if err != nil {
+		return response, err
+	}
+	s.Mutex().Lock()
+	s.sessions[request.ID] = session
+	s.Mutex().Unlock()
+	context.Deffer(func() {
+		_ = session.close()
+	})
+	state := context.State()
+	trips := state.GetMap(TripsKey)
+	if trips == nil {
+		trips = data.NewMap()
+		state.Put(TripsKey, trips)
+	}
+	trips.Put(request.ID, data.Map{TripSent: []interface{}{}, TripReceived: []interface{}{}})
+	return response, nil
+}
+
+func (s *service) session(ID string) (*session, error) {
+	s.Mutex().Lock()
+	defer s.Mutex().Unlock()
+	result, ok := s.sessions[ID]
+	if !ok {
+		return nil, fmt.Errorf("session %v was not found, connect first", ID)
+	}
+	return result, nil
+}
+
+// addTrip appends sent or received message to session trips state
+func (s *service) addTrip(context *endly.Context, ID, key string, value interface{}) {
+	state := context.State()
+	trips := state.GetMap(TripsKey)
+	if trips == nil {
+		return
+	}
+	sessionTrips := trips.GetMap(ID)
+	if sessionTrips == nil {
+		return
+	}
+	sessionTrips[key] = append(toolbox.AsSlice(sessionTrips[key]), value)
+}
+
+func (s *service) send(context *endly.Context, request *SendRequest) (*SendResponse, error) {
+	session, err := s.session(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	var response = &SendResponse{}
+	for _, message := range request.Batch {
+		if err = session.send(message, request.Binary); err != nil {
+			return response, err
+		}
+		response.Sent++
+		s.addTrip(context, request.ID, TripSent, message)
+	}
+	return response, nil
+}
+
+func (s *service) receive(context *endly.Context, request *ReceiveRequest) (*ReceiveResponse, error) {
+	session, err := s.session(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	var response = &ReceiveResponse{Received: make([]*Message, 0)}
+	var matchState = data.NewMap()
+	for key, value := range context.State() {
+		matchState[key] = value
+	}
+	var compute eval.Compute
+	timeout := time.After(time.Duration(request.TimeoutMs) * time.Millisecond)
+	for request.Count == 0 || len(response.Received) < request.Count {
+		message, open := session.next(timeout)
+		if !open {
+			if request.Count > 0 {
+				err = fmt.Errorf("session %v was closed after receiving %v/%v message(s)", request.ID, len(response.Received), request.Count)
+				if readErr := session.readError(); readErr != nil {
+					err = fmt.Errorf("%w: %v", err, readErr)
+				}
+			}
+			break
+		}
+		if message == nil {
+			if request.Count > 0 {
+				err = fmt.Errorf("session %v received %v/%v message(s) within %vms", request.ID, len(response.Received), request.Count, request.TimeoutMs)
+			}
+			break
+		}
+		messageMap := asMap(message)
+		if request.Match != "" {
+			matchState.Put(MessageKey, messageMap)
+			matched, err := criteria.Evaluate(nil, matchState, request.Match, &compute, "Receive.Match", false)
+			if err != nil {
+				return response, err
+			}
+			if !matched {
+				response.Skipped++
+				continue
+			}
+		}
+		response.Received = append(response.Received, message)
+		s.addTrip(context, request.ID, TripReceived, messageMap)
+	}
+	if err != nil {
+		return response, err
+	}
+	if request.Expect != nil {
+		var actual = map[string]interface{}{
+			"Received": asSlice(response.Received),
+		}
+		response.Assert, err = validator.Assert(context, request, request.Expect, actual, "WS.messages", "assert received messages")
+	}
+	return response, err
+}
+
+func (s *service) close(context *endly.Context, request *CloseRequest) (*CloseResponse, error) {
+	session, err := s.session(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.Mutex().Lock()
+	delete(s.sessions, request.ID)
+	s.Mutex().Unlock()
+	err = session.close()
+	session.mux.Lock()
+	defer session.mux.Unlock()
+	return &CloseResponse{Received: session.received, Sent: session.sent}, err
+}
+
+func asMap(message *Message) map[string]interface{} {
+	var result = make(map[string]interface{})
+	_ = toolbox.DefaultConverter.AssignConverted(&result, message)
+	return result
+}
+
+func asSlice(messages []*Message) []interface{} {
+	var result = make([]interface{}, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, asMap(message))
+	}
+	return result
+}
+
+const connectExample = `{
+  "ID": "reporter",
+  "URL": "ws://127.0.0.1:8085/ws",
+  "Header": {
+    "Authorization": ["Bearer ${token}"]
+  }
+}`
+
+const receiveExample = `{
+  "ID": "reporter",
+  "Count": 2,
+  "TimeoutMs": 3000,
+  "Match": "$message.JSONBody.type:report",
+  "Expect": {
+    "Received": [
+      {"JSONBody": {"name": "report1"}},
+      {"JSONBody": {"name": "report2"}}
+    ]
+  }
+}`
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "connect",
+		RequestInfo: &endly.ActionInfo{
+			Description: "open WebSocket connection or subscribe to Server-Sent Events",
+			Examples: []*endly.UseCase{
+				{
+					Description: "connect",
+					Data:        connectExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ConnectRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ConnectResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ConnectRequest); ok {
+				return s.connect(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "send",
+		RequestInfo: &endly.ActionInfo{
+			Description: "send WebSocket messages",
+		},
+		RequestProvider: func() interface{} {
+			return &SendRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &SendResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*SendRequest); ok {
+				return s.send(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "receive",
+		RequestInfo: &endly.ActionInfo{
+			Description: "receive WebSocket messages or Server-Sent Events",
+			Examples: []*endly.UseCase{
+				{
+					Description: "receive matching messages",
+					Data:        receiveExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ReceiveRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ReceiveResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ReceiveRequest); ok {
+				return s.receive(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "close",
+		RequestInfo: &endly.ActionInfo{
+			Description: "close WebSocket connection or SSE subscription",
+		},
+		RequestProvider: func() interface{} {
+			return &CloseRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CloseResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CloseRequest); ok {
+				return s.close(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+// New creates a new WebSocket and SSE runner service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+		sessions:        make(map[string]*session),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,154 @@
+package ws_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	runner "github.com/viant/endly/service/testing/runner/ws"
+	"github.com/viant/toolbox"
+)
+
+func newTestServer() *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", func(writer http.ResponseWriter, request *http.Request) {
+		conn, err := upgrader.Upgrade(writer, request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"welcome","user":"`+request.Header.Get("X-User")+`"}`))
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(messageType, []byte(`{"type":"ping"}`))
+			_ = conn.WriteMessage(messageType, data)
+		}
+	})
+	mux.HandleFunc("/events", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		flusher := writer.(http.Flusher)
+		for i := 1; i <= 3; i++ {
+			_, _ = fmt.Fprintf(writer, ": heartbeat\nevent: report\nid: %d\ndata: {\"name\":\n", i)
+			_, _ = fmt.Fprintf(writer, "data: \"report%d\"}\n\n", i)
+			flusher.Flush()
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestService_WebSocket(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+
+	connectResponse := &runner.ConnectResponse{}
+	err := endly.Run(context, &runner.ConnectRequest{
+		URL:    strings.Replace(server.URL, "http://", "ws://", 1) + "/ws",
+		Header: http.Header{"X-User": []string{"bob"}},
+	}, connectResponse)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusSwitchingProtocols, connectResponse.StatusCode)
+
+	var useCases = []struct {
+		description string
+		send        *runner.SendRequest
+		receive     *runner.ReceiveRequest
+		expectBody  []string
+		skipped     int
+		hasError    bool
+	}{
+		{
+			description: "welcome message",
+			receive: &runner.ReceiveRequest{Count: 1, Expect: map[string]interface{}{
+				"Received": []interface{}{map[string]interface{}{"JSONBody": map[string]interface{}{"type": "welcome", "user": "bob"}}},
+			}},
+			expectBody: []string{`{"type":"welcome","user":"bob"}`},
+		},
+		{
+			description: "matching echo messages",
+			send:        &runner.SendRequest{Batch: []interface{}{"hello", map[string]interface{}{"id": 1}}},
+			receive:     &runner.ReceiveRequest{Count: 2, Match: "$message.JSONBody.type:!ping"},
+			expectBody:  []string{"hello", `{"id":1}`},
+			skipped:     2,
+		},
+		{
+			description: "receive timeout",
+			receive:     &runner.ReceiveRequest{Count: 1, TimeoutMs: 100},
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		if useCase.send != nil {
+			sendResponse := &runner.SendResponse{}
+			if !assert.Nil(t, endly.Run(context, useCase.send, sendResponse), useCase.description) {
+				continue
+			}
+			assert.Equal(t, len(useCase.send.Batch), sendResponse.Sent, useCase.description)
+		}
+		response := &runner.ReceiveResponse{}
+		err := endly.Run(context, useCase.receive, response)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		var bodies = make([]string, 0)
+		for _, message := range response.Received {
+			bodies = append(bodies, message.Body)
+		}
+		assert.EqualValues(t, useCase.expectBody, bodies, useCase.description)
+		assert.Equal(t, useCase.skipped, response.Skipped, useCase.description)
+		if useCase.receive.Expect != nil && assert.NotNil(t, response.Assert, useCase.description) {
+			assert.Equal(t, 0, response.Assert.Validation.FailedCount, useCase.description)
+		}
+	}
+	state := context.State()
+	received, _ := state.GetValue(runner.TripsKey + ".default.Received")
+	assert.Equal(t, 3, len(toolbox.AsSlice(received)))
+
+	closeResponse := &runner.CloseResponse{}
+	if assert.Nil(t, endly.Run(context, &runner.CloseRequest{}, closeResponse)) {
+		assert.Equal(t, 2, closeResponse.Sent)
+		assert.Equal(t, 5, closeResponse.Received)
+	}
+}
+
+func TestService_ServerSentEvents(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	err := endly.Run(context, &runner.ConnectRequest{ID: "events", URL: server.URL + "/events"}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	response := &runner.ReceiveResponse{}
+	err = endly.Run(context, &runner.ReceiveRequest{ID: "events", TimeoutMs: 500, Match: "$message.EventID:!2"}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if assert.Equal(t, 2, len(response.Received)) {
+		assert.Equal(t, "report", response.Received[0].Event)
+		assert.Equal(t, "1", response.Received[0].EventID)
+		assert.EqualValues(t, map[string]interface{}{"name": "report3"}, response.Received[1].JSONBody)
+	}
+	assert.Equal(t, 1, response.Skipped)
+	err = endly.Run(context, &runner.SendRequest{ID: "events", Message: "hello"}, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,238 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/viant/toolbox"
+)
+
+// messageBufferSize represents number of received messages buffered by session
+const messageBufferSize = 1024
+
+// session represents WebSocket connection or SSE subscription
+type session struct {
+	ID       string
+	protocol string
+	conn     *websocket.Conn
+	body     io.ReadCloser
+	messages chan *Message
+	done     chan struct{} //closed with session, to unblock reader waiting on full messages buffer
+	err      error
+	received int
+	sent     int
+	mux      sync.Mutex
+	closed   bool
+}
+
+// send sends WebSocket text or binary message
+func (s *session) send(message interface{}, binary bool) error {
+	if s.conn == nil {
+		return fmt.Errorf("session %v: send is only supported with %v protocol", s.ID, ProtocolWebSocket)
+	}
+	text, ok := message.(string)
+	if !ok {
+		var err error
+		if text, err = toolbox.AsJSONText(message); err != nil {
+			return err
+		}
+		text = strings.TrimSpace(text)
+	}
+	messageType := websocket.TextMessage
+	if binary {
+		messageType = websocket.BinaryMessage
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.conn.WriteMessage(messageType, []byte(text)); err != nil {
+		return fmt.Errorf("session %v: failed to send message: %w", s.ID, err)
+	}
+	s.sent++
+	return nil
+}
+
+// next returns next received message, nil if session was closed
+func (s *session) next(timeout <-chan time.Time) (*Message, bool) {
+	select {
+	case message, ok := <-s.messages:
+		if ok {
+			s.mux.Lock()
+			s.received++
+			s.mux.Unlock()
+		}
+		return message, ok
+	case <-timeout:
+		return nil, true
+	}
+}
+
+// readError returns error that terminated reading
+func (s *session) readError() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.err
+}
+
+func (s *session) close() error {
+	s.mux.Lock()
+	if s.closed {
+		s.mux.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	s.mux.Unlock()
+	if s.body != nil {
+		return s.body.Close()
+	}
+	_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return s.conn.Close()
+}
+
+func (s *session) terminate(err error) {
+	s.mux.Lock()
+	if !s.closed && err != nil && !errors.Is(err, io.EOF) && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		s.err = err
+	}
+	s.mux.Unlock()
+	close(s.messages)
+}
+
+// deliver buffers received message, it returns false if session was closed in the meantime
+func (s *session) deliver(message *Message) bool {
+	select {
+	case s.messages <- newMessage(message):
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// readWebSocket reads WebSocket messages until connection is closed
+func (s *session) readWebSocket() {
+	for {
+		messageType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.terminate(err)
+			return
+		}
+		message := &Message{Type: "text", Body: string(data), Timestamp: time.Now()}
+		if messageType == websocket.BinaryMessage {
+			message.Type, message.Body = "binary", base64.StdEncoding.EncodeToString(data)
+		}
+		if !s.deliver(message) {
+			s.terminate(nil)
+			return
+		}
+	}
+}
+
+// readEvents reads Server-Sent Events until stream is closed
+func (s *session) readEvents() {
+	scanner := bufio.NewScanner(s.body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var event = &Message{Type: "event"}
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				event.Body = strings.Join(data, "\n")
+				event.Timestamp = time.Now()
+				if !s.deliver(event) {
+					s.terminate(nil)
+					return
+				}
+			}
+			event, data = &Message{Type: "event"}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") { //comment
+			continue
+		}
+		field, value := line, ""
+		if index := strings.Index(line, ":"); index != -1 {
+			field, value = line[:index], strings.TrimPrefix(line[index+1:], " ")
+		}
+		switch field {
+		case "event":
+			event.Event = value
+		case "id":
+			event.EventID = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	s.terminate(scanner.Err())
+}
+
+func newMessage(message *Message) *Message {
+	if message.Type != "binary" && toolbox.IsStructuredJSON(message.Body) {
+		message.JSONBody, _ = toolbox.JSONToInterface(message.Body)
+	}
+	return message
+}
+
+// connect opens WebSocket connection or SSE subscription
+func connect(request *ConnectRequest, response *ConnectResponse) (*session, error) {
+	result := &session{ID: request.ID, protocol: request.Protocol, messages: make(chan *Message, messageBufferSize), done: make(chan struct{})}
+	timeout := time.Duration(request.TimeoutMs) * time.Millisecond
+	if request.Protocol == ProtocolSSE {
+		ctx, cancel := context.WithCancel(context.Background())
+		httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, request.URL, nil)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		for name, values := range request.Header {
+			httpRequest.Header[name] = values
+		}
+		httpRequest.Header.Set("Accept", "text/event-stream")
+		client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, ResponseHeaderTimeout: timeout}}
+		httpResponse, err := client.Do(httpRequest)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to subscribe to %v: %w", request.URL, err)
+		}
+		response.StatusCode, response.Header = httpResponse.StatusCode, httpResponse.Header
+		if httpResponse.StatusCode != http.StatusOK {
+			_ = httpResponse.Body.Close()
+			cancel()
+			return nil, fmt.Errorf("failed to subscribe to %v: status code %v", request.URL, httpResponse.StatusCode)
+		}
+		result.body = &cancelReadCloser{ReadCloser: httpResponse.Body, cancel: cancel}
+		go result.readEvents()
+		return result, nil
+	}
+	dialer := &websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: timeout}
+	conn, httpResponse, err := dialer.Dial(request.URL, request.Header)
+	if httpResponse != nil {
+		response.StatusCode, response.Header = httpResponse.StatusCode, httpResponse.Header
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v: %w", request.URL, err)
+	}
+	result.conn = conn
+	go result.readWebSocket()
+	return result, nil
+}
+
+// cancelReadCloser cancels request context on close, to unblock pending stream read
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelReadCloser) Close() error {
+	c.cancel()
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,57 @@
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_CloseWithFullBuffer(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, err := upgrader.Upgrade(writer, request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for i := 0; i < messageBufferSize+10; i++ {
+			if err = conn.WriteMessage(websocket.TextMessage, []byte("message")); err != nil {
+				return
+			}
+		}
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	request := &ConnectRequest{ID: "flood", URL: strings.Replace(server.URL, "http://", "ws://", 1), Protocol: ProtocolWebSocket}
+	session, err := connect(request, &ConnectResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	for deadline := time.Now().Add(5 * time.Second); len(session.messages) < messageBufferSize && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.EqualValues(t, messageBufferSize, len(session.messages))
+	assert.Nil(t, session.close())
+	time.Sleep(100 * time.Millisecond) //reader blocked on full buffer should terminate without delivering pending message
+	received := 0
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-session.messages:
+			if !ok {
+				assert.EqualValues(t, messageBufferSize, received)
+				return
+			}
+			received++
+		case <-timeout:
+			assert.Fail(t, "reader should terminate once session is closed")
+			return
+		}
+	}
+}
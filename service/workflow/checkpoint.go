@@ -0,0 +1,290 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+var checkpointKey = (*Checkpoint)(nil)
+
+// checkpointSkippedKeys represents state keys that are republished by each run, thus not persisted, published parameters are skipped too
+var checkpointSkippedKeys = map[string]bool{
+	data.UDFKey:    true,
+	"ts":           true,
+	selfStateKey:   true,
+	tasksStateKey:  true,
+	paramsStateKey: true,
+	dataStateKey:   true,
+	model.OwnerURL: true,
+}
+
+// Checkpoint represents workflow run progress persisted after each completed task
+type Checkpoint struct {
+	SessionID      string
+	Workflow       string
+	Completed      []string               `description:"completed task paths"`
+	State          map[string]interface{} `description:"workflow state after the last completed task"`
+	Data           map[string]interface{} `description:"workflow run response data"`
+	Unserializable []string               `json:",omitempty" description:"state keys that could not be persisted"`
+	URL            string                 `json:"-"`
+	stateKey       string
+	completed      map[string]bool
+	reported       map[string]bool
+	mux            sync.Mutex
+}
+
+// IsCompleted returns true if task with supplied path completed in the checkpointed run
+func (c *Checkpoint) IsCompleted(task string) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.completed[task]
+}
+
+// Save records completed task path with current workflow state and persists checkpoint, run data is updated only if supplied
+func (c *Checkpoint) Save(task string, state data.Map, runData map[string]interface{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if task != "" && !c.completed[task] {
+		c.completed[task] = true
+		c.Completed = append(c.Completed, task)
+	}
+	c.Unserializable = nil
+	c.State = make(map[string]interface{})
+	params := toolbox.AsMap(state.Get(paramsStateKey))
+	for key, value := range state {
+		if _, isParam := params[key]; isParam || checkpointSkippedKeys[key] || key == c.SessionID || key == c.stateKey {
+			continue
+		}
+		if encoded, ok := c.encode(key, value); ok {
+			c.State[key] = encoded
+		}
+	}
+	if runData != nil {
+		c.Data = make(map[string]interface{})
+		for key, value := range runData {
+			if encoded, ok := c.encode(key, value); ok {
+				c.Data[key] = encoded
+			}
+		}
+	}
+	sort.Strings(c.Unserializable)
+	payload, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if err = os.MkdirAll(path.Dir(c.URL), 0744); err == nil {
+		err = os.WriteFile(c.URL, payload, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %v, %w", c.URL, err)
+	}
+	return nil
+}
+
+// unreported returns unserializable state keys that have not been reported yet
+func (c *Checkpoint) unreported() []string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	var result []string
+	for _, key := range c.Unserializable {
+		if !c.reported[key] {
+			c.reported[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// encode returns JSON compatible value, values that can not be encoded are recorded as unserializable
+func (c *Checkpoint) encode(keyPath string, value interface{}) (interface{}, bool) {
+	switch {
+	case value == nil:
+		return nil, true
+	case toolbox.IsMap(value):
+		var result = make(map[string]interface{})
+		for key, item := range toolbox.AsMap(value) {
+			if encoded, ok := c.encode(keyPath+"."+key, item); ok {
+				result[key] = encoded
+			}
+		}
+		return result, true
+	case toolbox.IsSlice(value):
+		var result = make([]interface{}, 0)
+		for i, item := range toolbox.AsSlice(value) {
+			encoded, _ := c.encode(fmt.Sprintf("%v[%v]", keyPath, i), item)
+			result = append(result, encoded)
+		}
+		return result, true
+	}
+	if _, err := json.Marshal(value); err != nil {
+		c.Unserializable = append(c.Unserializable, keyPath)
+		return nil, false
+	}
+	return value, true
+}
+
+// NewCheckpoint creates a checkpoint for supplied session, completed tasks of resumed checkpoint are carried over
+func NewCheckpoint(URL, sessionID, workflow, stateKey string, resumed *Checkpoint) *Checkpoint {
+	var result = &Checkpoint{
+		URL:       URL,
+		SessionID: sessionID,
+		Workflow:  workflow,
+		Completed: []string{},
+		Data:      map[string]interface{}{},
+		stateKey:  stateKey,
+		completed: map[string]bool{},
+		reported:  map[string]bool{},
+	}
+	if resumed != nil {
+		for _, task := range resumed.Completed {
+			result.completed[task] = true
+			result.Completed = append(result.Completed, task)
+		}
+	}
+	return result
+}
+
+// LoadCheckpoint loads checkpoint from supplied URL
+func LoadCheckpoint(URL string) (*Checkpoint, error) {
+	payload, err := os.ReadFile(URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	var result = &Checkpoint{URL: URL}
+	if err = json.Unmarshal(payload, result); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %v, %w", URL, err)
+	}
+	result.completed = map[string]bool{}
+	for _, task := range result.Completed {
+		result.completed[task] = true
+	}
+	return result, nil
+}
+
+// checkpointURL returns session checkpoint location
+func checkpointURL(request *RunRequest, sessionID string) string {
+	logDirectory := request.LogDirectory
+	if logDirectory == "" {
+		logDirectory = defaultLogDirectory
+	}
+	return path.Join(logDirectory, checkpointDirectory, sessionID+".json")
+}
+
+// initCheckpoint creates root workflow checkpoint if requested, when resuming, checkpoint state and data are restored after the workflow init stage
+func (s *Service) initCheckpoint(context *endly.Context, request *RunRequest, process *model.Process, response *RunResponse) error {
+	if process.Depth > 0 || !(request.Checkpoint || request.ResumeSessionID != "") {
+		return nil
+	}
+	var resumed *Checkpoint
+	if request.ResumeSessionID != "" {
+		var err error
+		if resumed, err = LoadCheckpoint(checkpointURL(request, request.ResumeSessionID)); err != nil {
+			return err
+		}
+		if resumed.Workflow != process.Workflow.Name {
+			return fmt.Errorf("checkpoint %v was created by workflow: %v, but had %v", resumed.URL, resumed.Workflow, process.Workflow.Name)
+		}
+		state := context.State()
+		for key, value := range resumed.State {
+			state.Put(key, value)
+		}
+		for key, value := range resumed.Data {
+			response.Data[key] = value
+		}
+	}
+	checkpoint := NewCheckpoint(checkpointURL(request, context.SessionID), context.SessionID, process.Workflow.Name, request.StateKey, resumed)
+	if err := context.Replace(checkpointKey, checkpoint); err != nil {
+		return err
+	}
+	if resumed == nil {
+		return nil
+	}
+	context.Publish(NewCheckpointEvent(checkpointResumed, request.ResumeSessionID, resumed))
+	return s.saveCheckpoint(context, process, nil, resumed.Data) //resumed session progress is carried over even if no task completes
+}
+
+// getCheckpoint returns root workflow checkpoint or nil
+func getCheckpoint(context *endly.Context, process *model.Process) *Checkpoint {
+	if process.Depth > 0 || !context.Contains(checkpointKey) {
+		return nil
+	}
+	var result *Checkpoint
+	context.GetInto(checkpointKey, &result)
+	return result
+}
+
+// taskPath returns task path from the workflow root, i.e. build/compile, so that nested tasks with the same name are distinguished,
+// segment of a task with tag index (i.e. matrix combination, template instance) is suffixed with the index
+func taskPath(process *model.Process, task *model.Task) string {
+	if process.Workflow != nil {
+		if segments := findTaskPath(process.Workflow.TasksNode, task); len(segments) > 0 {
+			return strings.Join(segments, "/")
+		}
+	}
+	return taskPathSegment(task)
+}
+
+func findTaskPath(node *model.TasksNode, task *model.Task) []string {
+	if node == nil {
+		return nil
+	}
+	for _, candidate := range node.Tasks {
+		if candidate == task {
+			return []string{taskPathSegment(candidate)}
+		}
+		if segments := findTaskPath(candidate.TasksNode, task); len(segments) > 0 {
+			return append([]string{taskPathSegment(candidate)}, segments...)
+		}
+	}
+	return nil
+}
+
+func taskPathSegment(task *model.Task) string {
+	if task.MetaTag != nil && task.TagIndex != "" {
+		return task.Name + "[" + task.TagIndex + "]"
+	}
+	return task.Name
+}
+
+// isCheckpointed returns true if task completed in the resumed run
+func (s *Service) isCheckpointed(context *endly.Context, process *model.Process, task *model.Task) bool {
+	checkpoint := getCheckpoint(context, process)
+	if checkpoint == nil {
+		return false
+	}
+	key := taskPath(process, task)
+	if !checkpoint.IsCompleted(key) {
+		return false
+	}
+	context.Publish(NewCheckpointEvent(checkpointSkipped, key, checkpoint))
+	return true
+}
+
+// saveCheckpoint persists checkpoint after completed task, nil task saves the final workflow state and data, dry run is never persisted
+func (s *Service) saveCheckpoint(context *endly.Context, process *model.Process, task *model.Task, runData map[string]interface{}) error {
+	checkpoint := getCheckpoint(context, process)
+	if checkpoint == nil || context.DryRun {
+		return nil
+	}
+	var key string
+	if task != nil {
+		key = taskPath(process, task)
+	}
+	if err := checkpoint.Save(key, context.State(), runData); err != nil {
+		return err
+	}
+	event := NewCheckpointEvent(checkpointSaved, key, checkpoint)
+	event.Unserializable = checkpoint.unreported()
+	context.Publish(event)
+	return nil
+}
@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+func TestCheckpoint_NestedTaskPath(t *testing.T) {
+	var newTask = func(name string, tag *model.MetaTag, subTasks ...*model.Task) *model.Task {
+		return &model.Task{
+			AbstractNode: &model.AbstractNode{Name: name},
+			MetaTag:      tag,
+			TasksNode:    &model.TasksNode{Tasks: subTasks},
+		}
+	}
+	buildCompile := newTask("compile", nil)
+	testCompile := newTask("compile", nil)
+	build := newTask("build", nil, buildCompile)
+	test := newTask("test", nil, testCompile)
+	matrixCompile1 := newTask("compile", nil)
+	matrixCompile2 := newTask("compile", nil)
+	matrix1 := newTask("matrix", &model.MetaTag{TagIndex: "1"}, matrixCompile1)
+	matrix2 := newTask("matrix", &model.MetaTag{TagIndex: "2"}, matrixCompile2)
+	process := &model.Process{Workflow: &model.Workflow{
+		TasksNode: &model.TasksNode{Tasks: []*model.Task{build, test, matrix1, matrix2}},
+	}}
+
+	assert.EqualValues(t, "build/compile", taskPath(process, buildCompile))
+	assert.EqualValues(t, "test/compile", taskPath(process, testCompile))
+	assert.EqualValues(t, "matrix[1]/compile", taskPath(process, matrixCompile1))
+	assert.EqualValues(t, "matrix[2]/compile", taskPath(process, matrixCompile2))
+	assert.EqualValues(t, "build", taskPath(process, build))
+	assert.EqualValues(t, "compile", taskPath(process, newTask("compile", nil)), "task outside of workflow should use its name")
+
+	checkpoint := NewCheckpoint(path.Join(t.TempDir(), "session.json"), "session", "app", "", nil)
+	for _, task := range []*model.Task{buildCompile, build, matrixCompile1} {
+		assert.Nil(t, checkpoint.Save(taskPath(process, task), data.NewMap(), nil))
+	}
+	resumed, err := LoadCheckpoint(checkpoint.URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, candidate := range []*Checkpoint{checkpoint, NewCheckpoint(checkpoint.URL, "resumed", "app", "", resumed)} {
+		assert.True(t, candidate.IsCompleted(taskPath(process, buildCompile)))
+		assert.True(t, candidate.IsCompleted(taskPath(process, build)))
+		assert.True(t, candidate.IsCompleted(taskPath(process, matrixCompile1)))
+		assert.False(t, candidate.IsCompleted(taskPath(process, testCompile)), "nested task with the same name should not be completed")
+		assert.False(t, candidate.IsCompleted(taskPath(process, matrixCompile2)), "task of other matrix combination should not be completed")
+		assert.False(t, candidate.IsCompleted(taskPath(process, test)))
+	}
+}
+
+func TestService_CheckpointCaughtError(t *testing.T) {
+	baseDir := t.TempDir()
+	URL := path.Join(baseDir, "run.yaml")
+	if !assert.Nil(t, os.WriteFile(URL, []byte(`pipeline:
+  init:
+    action: nop
+  fail:
+    action: fail
+    message: boom
+  catch:
+    action: nop
+`), 0644)) {
+		return
+	}
+	request, err := NewRunRequestFromURL(URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	request.AssetURL = URL
+	request.Checkpoint = true
+	request.LogDirectory = path.Join(baseDir, "logs")
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	assert.Nil(t, endly.Run(context, request, &RunResponse{}), "error should be caught")
+
+	checkpoint, err := LoadCheckpoint(checkpointURL(request, context.SessionID))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, checkpoint.IsCompleted("init"))
+	assert.False(t, checkpoint.IsCompleted("fail"), "failed task should not be checkpointed even if its error was caught")
+}
@@ -6,3 +6,13 @@ const (
 	tasksStateKey  = "tasks"
 	selfStateKey   = "self"
 )
+
+// defaultTaskConcurrency represents default max number of concurrently running parallel tasks
+const defaultTaskConcurrency = 4
+
+const (
+	//defaultLogDirectory represents default log directory used by checkpoints if run request does not specify one
+	defaultLogDirectory = "logs"
+	//checkpointDirectory represents log subdirectory with session checkpoints
+	checkpointDirectory = "checkpoint"
+)
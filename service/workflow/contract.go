@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"github.com/viant/afs/file"
 	"github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
 	"github.com/viant/endly/model/location"
 	"path"
 	"strings"
@@ -35,6 +38,9 @@ type RunRequest struct {
 	TagIDs            string `description:"coma separated TagID list, if present in a task, only matched runs, other task runWorkflow as normal"`
 	Tasks             string `required:"true" description:"coma separated task list, if empty or '*' runs all tasks sequentially"` //tasks to runWorkflow with coma separated list or '*', or empty string for all tasks
 	Interactive       bool
+	Checkpoint        bool   `description:"flag to persist run checkpoint into LogDirectory/checkpoint/<sessionID>.json after each completed task"`
+	ResumeSessionID   string `description:"session ID of the failed run to resume from its checkpoint, completed tasks are skipped"`
+	DryRun            bool   `description:"flag to expand workflow and publish resolved service action requests without running them"`
 	*model.Inlined
 	workflow *model.Workflow //inline workflow from pipeline
 }
@@ -165,7 +171,9 @@ type LoadResponse struct {
 type SwitchCase struct {
 	*model.ServiceRequest `description:"action to runWorkflow if matched"`
 	Task                  string      `description:"task to runWorkflow if matched"`
-	Value                 interface{} `required:"true" description:"matching sourceKey value"`
+	Value                 interface{} `description:"matching sourceKey value"`
+	When                  string      `description:"optional matching criteria, i.e. $status:~/^(running|ready)$/, if specified value is ignored"`
+	whenEval              eval.Compute
 }
 
 // SwitchRequest represent switch action request
@@ -175,14 +183,27 @@ type SwitchRequest struct {
 	Default   *SwitchCase   `description:"in case no value was match case"`
 }
 
-// Match matches source with supplied action request.
-func (r *SwitchRequest) Match(source interface{}) *SwitchCase {
+// Match matches source with supplied action request, case with When criteria is matched with the context state.
+func (r *SwitchRequest) Match(context *endly.Context, source interface{}) (*SwitchCase, error) {
 	for _, switchCase := range r.Cases {
+		if switchCase.When != "" {
+			matched, captures, err := criteria.EvaluateWithCaptures(context, context.State(), switchCase.When, &switchCase.whenEval, "Switch.When", false)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				if captures != nil {
+					captures.Publish(context.State())
+				}
+				return switchCase, nil
+			}
+			continue
+		}
 		if toolbox.AsString(switchCase.Value) == toolbox.AsString(source) {
-			return switchCase
+			return switchCase, nil
 		}
 	}
-	return r.Default
+	return r.Default, nil
 }
 
 // SwitchResponse represents actual action or task response
@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"path"
+	"strings"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/debug"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
+)
+
+// nodeLevels represents node nesting level within a workflow, used to step over nested nodes
+var nodeLevels = map[string]int{
+	debug.KindWorkflow: 0,
+	debug.KindTask:     1,
+	debug.KindAction:   2,
+}
+
+// pause blocks node execution while context debugger is paused on it
+func (s *Service) pause(context *endly.Context, nodeType string, process *model.Process, node *model.AbstractNode) {
+	if context.Debugger == nil {
+		return
+	}
+	state := context.State()
+	context.Debugger.Pause(&debug.Point{
+		Kind:  nodeType,
+		Path:  debugPath(nodeType, process, node),
+		Depth: process.Depth*len(nodeLevels) + nodeLevels[nodeType],
+		State: state,
+		Eval: func(expression string) (bool, error) {
+			var compute eval.Compute
+			return criteria.Evaluate(nil, state, expression, &compute, "Breakpoint.When", false)
+		},
+	})
+}
+
+// debugPath returns workflow/task/action node path
+func debugPath(nodeType string, process *model.Process, node *model.AbstractNode) string {
+	var result string
+	if process.Workflow != nil {
+		result = process.Workflow.Name
+		if result == "" && process.Workflow.Source != nil {
+			result = path.Base(process.Workflow.Source.URL)
+			result = strings.TrimSuffix(result, path.Ext(result))
+		}
+	}
+	if nodeType == debug.KindWorkflow || process.Task == nil {
+		return result
+	}
+	result += "/" + process.Task.Name
+	if nodeType == debug.KindTask {
+		return result
+	}
+	name := node.Name
+	if name == "" {
+		for _, action := range process.Task.Actions {
+			if action.AbstractNode == node {
+				name = action.Service + ":" + action.Action
+				break
+			}
+		}
+	}
+	return result + "/" + name
+}
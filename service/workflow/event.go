@@ -1,8 +1,14 @@
 package workflow
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
 	"github.com/viant/toolbox/data"
+	"gopkg.in/yaml.v2"
 )
 
 // LoadedEvent represents workflow load event
@@ -50,3 +56,111 @@ type AsyncEvent struct {
 func NewAsyncEvent(action *model.Action) *AsyncEvent {
 	return &AsyncEvent{action}
 }
+
+// RetryEvent represents an action retry attempt event
+type RetryEvent struct {
+	TagID       string
+	Action      string
+	Attempt     int
+	MaxAttempts int
+	DelayMs     int
+	Error       string
+}
+
+// Messages returns retry messages
+func (e *RetryEvent) Messages() []*msg.Message {
+	info := fmt.Sprintf("%v attempt %v/%v in %v ms", e.Action, e.Attempt, e.MaxAttempts, e.DelayMs)
+	if e.Error != "" {
+		info += ", previous attempt error: " + e.Error
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(info, msg.MessageStyleGeneric), msg.NewStyled("retry", msg.MessageStyleGeneric)),
+	}
+}
+
+// NewRetryEvent creates a new retry event, attempt is the upcoming attempt number
+func NewRetryEvent(action *model.Action, attempt int, delay time.Duration, err error) *RetryEvent {
+	var result = &RetryEvent{
+		TagID:       action.TagID,
+		Action:      action.Service + "." + action.Action,
+		Attempt:     attempt,
+		MaxAttempts: action.Retry.MaxAttempts,
+		DelayMs:     int(delay / time.Millisecond),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+const (
+	checkpointSaved   = "saved"
+	checkpointSkipped = "skipped"
+	checkpointResumed = "resumed"
+)
+
+// CheckpointEvent represents workflow checkpoint event
+type CheckpointEvent struct {
+	Status         string
+	Subject        string //completed or skipped task, or resumed session ID
+	URL            string
+	Completed      int
+	Unserializable []string
+}
+
+// Messages returns checkpoint messages, saved checkpoint reports only newly detected unserializable state
+func (e *CheckpointEvent) Messages() []*msg.Message {
+	switch e.Status {
+	case checkpointResumed:
+		info := fmt.Sprintf("resumed session %v with %v completed task(s)", e.Subject, e.Completed)
+		return []*msg.Message{msg.NewMessage(msg.NewStyled(info, msg.MessageStyleGeneric), msg.NewStyled("checkpoint", msg.MessageStyleGeneric))}
+	case checkpointSkipped:
+		info := fmt.Sprintf("skipped completed task %v", e.Subject)
+		return []*msg.Message{msg.NewMessage(msg.NewStyled(info, msg.MessageStyleGeneric), msg.NewStyled("checkpoint", msg.MessageStyleGeneric))}
+	}
+	if len(e.Unserializable) == 0 {
+		return nil
+	}
+	warning := fmt.Sprintf("state was not persisted in %v: %v", e.URL, strings.Join(e.Unserializable, ", "))
+	return []*msg.Message{msg.NewMessage(msg.NewStyled(warning, msg.MessageStyleError), msg.NewStyled("checkpoint", msg.MessageStyleError))}
+}
+
+// NewCheckpointEvent creates a new checkpoint event
+func NewCheckpointEvent(status, subject string, checkpoint *Checkpoint) *CheckpointEvent {
+	return &CheckpointEvent{
+		Status:    status,
+		Subject:   subject,
+		URL:       checkpoint.URL,
+		Completed: len(checkpoint.Completed),
+	}
+}
+
+// PlanEvent represents resolved action request published in dry run mode instead of running the action
+type PlanEvent struct {
+	TagID   string
+	Service string
+	Action  string
+	Request map[string]interface{}
+}
+
+// Messages returns plan messages
+func (e *PlanEvent) Messages() []*msg.Message {
+	info := ""
+	if content, err := yaml.Marshal(e.Request); err == nil {
+		info = string(content)
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(e.Service+":"+e.Action, msg.MessageStyleGeneric), msg.NewStyled("plan", msg.MessageStyleGeneric),
+			msg.NewStyled(info, msg.MessageStyleInput)),
+	}
+}
+
+// NewPlanEvent creates a new plan event
+func NewPlanEvent(action *model.Action, request map[string]interface{}) *PlanEvent {
+	return &PlanEvent{
+		TagID:   action.TagID,
+		Service: action.Service,
+		Action:  action.Action,
+		Request: request,
+	}
+}
@@ -38,6 +38,12 @@ func Pop(context *endly.Context) *model.Process {
 	return process
 }
 
+// forkProcesses replaces context process stack with its copy having supplied process on top
+func forkProcesses(context *endly.Context, process *model.Process) {
+	var forked = processes(context).Fork(process)
+	_ = context.Replace(processesKey, forked)
+}
+
 // Last returns last process
 func Last(context *endly.Context) *model.Process {
 	var processes = processes(context)
@@ -0,0 +1,194 @@
+package workflow
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/data"
+)
+
+// runParallelTasks runs sub tasks following their dependency graph with bounded concurrency,
+// the first task error stops scheduling of not yet started tasks and is returned once all running tasks complete.
+func (s *Service) runParallelTasks(context *endly.Context, process *model.Process, tasks *model.TasksNode) error {
+	graph, err := model.NewTaskGraph(tasks)
+	if err != nil {
+		return err
+	}
+	concurrency := tasks.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultTaskConcurrency
+	}
+	var (
+		mux           = &sync.Mutex{}
+		group         = &sync.WaitGroup{}
+		limiter       = make(chan struct{}, concurrency)
+		done          = make(map[string]chan struct{})
+		taskErr       error
+		failedProcess *model.Process
+	)
+	for _, task := range graph.Tasks {
+		done[task.Name] = make(chan struct{})
+	}
+	group.Add(len(graph.Tasks))
+	for _, task := range graph.Tasks {
+		go func(task *model.Task) {
+			defer group.Done()
+			defer close(done[task.Name])
+			for _, dependency := range graph.Dependencies[task.Name] {
+				<-done[dependency]
+			}
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+			mux.Lock()
+			canRun := taskErr == nil && process.CanRun() && !s.isCheckpointed(context, process, task)
+			mux.Unlock()
+			if !canRun {
+				return
+			}
+			taskProcess, err := s.runParallelTask(context, process, task, mux)
+			mux.Lock()
+			defer mux.Unlock()
+			if err == nil {
+				err = s.saveCheckpoint(context, process, task, nil)
+			}
+			if taskProcess.IsTerminated() {
+				process.Terminate()
+			}
+			if taskProcess.Scheduled != nil && process.Scheduled == nil {
+				process.Scheduled = taskProcess.Scheduled
+			}
+			if err != nil && taskErr == nil {
+				taskErr = err
+				failedProcess = taskProcess
+			}
+		}(task)
+	}
+	group.Wait()
+	if failedProcess != nil {
+		process.Task = failedProcess.Task
+		process.Activity = failedProcess.Activity
+	}
+	return taskErr
+}
+
+// runParallelTask runs a task with a cloned context and forked process, buffered task events are published to the parent context
+// once the task completes, so that concurrent tasks output is not interleaved; task result and modified state are merged back to the parent state.
+func (s *Service) runParallelTask(parent *endly.Context, process *model.Process, task *model.Task, mux *sync.Mutex) (*model.Process, error) {
+	mux.Lock() //parent state is modified by sibling tasks merge
+	context := parent.Clone()
+	state := context.State()
+	shared := sharedKeys(state, process)
+	var snapshot = data.NewMap()
+	for key, value := range state {
+		if shared[key] {
+			continue
+		}
+		state[key] = deepCopy(value)
+		snapshot[key] = deepCopy(value)
+	}
+	mux.Unlock()
+	events := context.MakeAsyncSafe()
+	taskProcess := process.Fork()
+	forkProcesses(context, taskProcess)
+
+	result, err := s.runTask(context, taskProcess, task)
+
+	mux.Lock()
+	defer mux.Unlock()
+	parentState := parent.State()
+	var changed = make(map[string]interface{})
+	for key, value := range state {
+		if !shared[key] {
+			changed[key] = value
+		}
+	}
+	mergeState(parentState, snapshot, changed)
+	parentState.Apply(result)
+	for _, event := range events.Events {
+		parent.Publish(event)
+	}
+	return taskProcess, err
+}
+
+// sharedKeys returns state keys that are not copied for a concurrent task: UDFs and keys holding process state,
+// which is shared by forked processes by design and modified under service mutex
+func sharedKeys(state data.Map, process *model.Process) map[string]bool {
+	var result = map[string]bool{data.UDFKey: true}
+	processState := reflect.ValueOf(process.State).Pointer()
+	for key, value := range state {
+		if aMap, ok := value.(data.Map); ok && reflect.ValueOf(aMap).Pointer() == processState {
+			result[key] = true
+		}
+	}
+	return result
+}
+
+// mergeState applies values changed by a task to the target state, nested maps are merged, so that concurrent tasks
+// modifying different keys of the same map do not override each other
+func mergeState(target, original, changed map[string]interface{}) {
+	for key, value := range changed {
+		previous, ok := original[key]
+		if ok && reflect.DeepEqual(previous, value) {
+			continue
+		}
+		if ok {
+			targetMap, isTargetMap := asStateMap(target[key])
+			previousMap, isPreviousMap := asStateMap(previous)
+			valueMap, isValueMap := asStateMap(value)
+			if isTargetMap && isPreviousMap && isValueMap {
+				mergeState(targetMap, previousMap, valueMap)
+				continue
+			}
+		}
+		target[key] = value
+	}
+}
+
+func asStateMap(value interface{}) (map[string]interface{}, bool) {
+	switch actual := value.(type) {
+	case data.Map:
+		return actual, true
+	case map[string]interface{}:
+		return actual, true
+	}
+	return nil, false
+}
+
+// deepCopy returns a copy of maps and slices, so that concurrent tasks do not share nested state
+func deepCopy(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case data.Map:
+		var result = make(data.Map, len(actual))
+		for k, v := range actual {
+			result[k] = deepCopy(v)
+		}
+		return result
+	case map[string]interface{}:
+		var result = make(map[string]interface{}, len(actual))
+		for k, v := range actual {
+			result[k] = deepCopy(v)
+		}
+		return result
+	case map[interface{}]interface{}:
+		var result = make(map[interface{}]interface{}, len(actual))
+		for k, v := range actual {
+			result[k] = deepCopy(v)
+		}
+		return result
+	case []interface{}:
+		var result = make([]interface{}, len(actual))
+		for i, v := range actual {
+			result[i] = deepCopy(v)
+		}
+		return result
+	case *data.Collection:
+		var result = make(data.Collection, len(*actual))
+		for i, v := range *actual {
+			result[i] = deepCopy(v)
+		}
+		return &result
+	}
+	return value
+}
@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+// runPipeline runs inline pipeline with state shared with the returned context
+func runPipeline(t *testing.T, YAML string) (data.Map, time.Duration, error) {
+	URL := path.Join(t.TempDir(), "run.yaml")
+	if err := os.WriteFile(URL, []byte(YAML), 0644); err != nil {
+		return nil, 0, err
+	}
+	request, err := NewRunRequestFromURL(URL)
+	if err != nil {
+		return nil, 0, err
+	}
+	request.AssetURL = URL
+	request.SharedState = true
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	started := time.Now()
+	err = endly.Run(context, request, &RunResponse{})
+	return context.State(), time.Since(started), err
+}
+
+func TestService_RunParallelTasks(t *testing.T) {
+	state, elapsed, err := runPipeline(t, `pipeline:
+  run:
+    concurrency: 4
+    a:
+      parallel: true
+      action: nop
+      sleepTimeMs: 300
+      post:
+        a: done
+    b:
+      parallel: true
+      action: nop
+      sleepTimeMs: 300
+      post:
+        b: done
+    c:
+      dependsOn: a,b
+      action: nop
+      post:
+        seen: ${a}-${b}
+`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, elapsed < 550*time.Millisecond, "independent tasks should overlap, elapsed: %v", elapsed)
+	assert.EqualValues(t, "done", state["a"], "parallel task state should be merged")
+	assert.EqualValues(t, "done", state["b"], "parallel task state should be merged")
+	assert.EqualValues(t, "done-done", state["seen"], "dependent task should wait for all its dependencies")
+}
+
+func TestService_RunParallelTasksError(t *testing.T) {
+	var pipeline = `pipeline:
+  run:
+    concurrency: 4
+    a:
+      parallel: true
+      action: nop
+      sleepTimeMs: 300
+      post:
+        a: done
+    f:
+      parallel: true
+      wait:
+        action: nop
+        sleepTimeMs: 50
+      fail:
+        action: fail
+        message: boom
+    c:
+      dependsOn: a
+      action: nop
+      post:
+        seen: $a
+`
+	var useCases = []struct {
+		description string
+		catch       string
+		hasError    bool
+	}{
+		{
+			description: "failing branch without catch",
+			hasError:    true,
+		},
+		{
+			description: "failing branch with catch",
+			catch: `    catch:
+      action: nop
+      post:
+        caught: $error.Error
+`,
+		},
+	}
+	for _, useCase := range useCases {
+		state, _, err := runPipeline(t, pipeline+useCase.catch)
+		if useCase.hasError {
+			if assert.NotNil(t, err, useCase.description) {
+				assert.Contains(t, err.Error(), "boom", useCase.description)
+			}
+		} else {
+			assert.Nil(t, err, useCase.description)
+			assert.Contains(t, state["caught"], "boom", useCase.description)
+		}
+		assert.EqualValues(t, "done", state["a"], "running branch should complete: "+useCase.description)
+		assert.Nil(t, state["seen"], "not started branch should be cancelled: "+useCase.description)
+	}
+}
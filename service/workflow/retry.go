@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+)
+
+// runWithRetry runs action attempt, the attempt is repeated with backoff delay as long as the action retry policy permits
+func (s *Service) runWithRetry(context *endly.Context, action *model.Action, attempt func() (interface{}, error)) error {
+	retry := action.Retry
+	for i := 1; ; i++ {
+		response, err := attempt()
+		if i >= retry.MaxAttempts || context.IsClosed() {
+			if err != nil && i > 1 {
+				return fmt.Errorf("failed after %v attempts: %w", i, err)
+			}
+			return err
+		}
+		var responseMap = map[string]interface{}{}
+		if response != nil {
+			_ = toolbox.DefaultConverter.AssignConverted(&responseMap, response)
+		}
+		canRetry, retryErr := retry.ShouldRetry(context, responseMap, err)
+		if retryErr != nil {
+			return retryErr
+		}
+		if !canRetry {
+			return err
+		}
+		delay := retry.Delay(i)
+		context.Publish(NewRetryEvent(action, i+1, delay, err))
+		if waitErr := waitRetryDelay(context, delay); waitErr != nil {
+			if err != nil {
+				return fmt.Errorf("retry interrupted after %v attempts: %v, %w", i, waitErr, err)
+			}
+			return waitErr
+		}
+	}
+}
+
+// waitRetryDelay waits for retry delay, it returns early with error once the context background deadline is exceeded or cancelled
+func waitRetryDelay(context *endly.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-context.Background().Done():
+		return context.Background().Err()
+	}
+}
+
+// runAttempt runs service request, unlike endly.Run it returns the attempt response also with an error
+func runAttempt(context *endly.Context, request interface{}, serviceResponse *endly.ServiceResponse) (interface{}, error) {
+	manager, err := context.Manager()
+	if err != nil {
+		return nil, err
+	}
+	response, err := manager.Run(context, request)
+	serviceResponse.Response = response
+	serviceResponse.Status = "ok"
+	serviceResponse.Err = err
+	serviceResponse.Error = ""
+	if err != nil {
+		serviceResponse.Status = "error"
+		serviceResponse.Error = err.Error()
+	}
+	return response, err
+}
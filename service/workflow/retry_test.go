@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+)
+
+func TestService_RunWithRetry(t *testing.T) {
+	var boom = errors.New("boom")
+	var useCases = []struct {
+		description    string
+		retry          *model.Retry
+		responses      []interface{}
+		errors         []error
+		expectAttempts int
+		expectDelays   []int
+		expectError    string
+	}{
+		{
+			description:    "retry until success",
+			retry:          &model.Retry{MaxAttempts: 5, DelayMs: 10},
+			errors:         []error{boom, boom, nil},
+			expectAttempts: 3,
+			expectDelays:   []int{10, 20},
+		},
+		{
+			description:    "failed after max attempts",
+			retry:          &model.Retry{MaxAttempts: 3, DelayMs: 10, Multiplier: 3},
+			errors:         []error{boom, boom, boom},
+			expectAttempts: 3,
+			expectDelays:   []int{10, 30},
+			expectError:    "failed after 3 attempts: boom",
+		},
+		{
+			description:    "when criteria with attempt response",
+			retry:          &model.Retry{MaxAttempts: 5, DelayMs: 10, When: "$response.Code >= 500"},
+			responses:      []interface{}{map[string]interface{}{"Code": 503}, map[string]interface{}{"Code": 502}, map[string]interface{}{"Code": 200}},
+			errors:         []error{nil, nil, nil},
+			expectAttempts: 3,
+			expectDelays:   []int{10, 20},
+		},
+		{
+			description:    "error not matching onError",
+			retry:          &model.Retry{MaxAttempts: 3, DelayMs: 10, OnError: "timeout"},
+			errors:         []error{boom},
+			expectAttempts: 1,
+			expectDelays:   []int{},
+			expectError:    "boom",
+		},
+	}
+	for _, useCase := range useCases {
+		if !assert.Nil(t, useCase.retry.Init(), useCase.description) {
+			continue
+		}
+		manager := endly.New()
+		context := manager.NewContext(toolbox.NewContext())
+		var delays = make([]int, 0)
+		context.SetListener(func(event msg.Event) {
+			if retryEvent, ok := event.Value().(*RetryEvent); ok {
+				delays = append(delays, retryEvent.DelayMs)
+			}
+		})
+		attempts := 0
+		action := &model.Action{MetaTag: &model.MetaTag{}, ServiceRequest: &model.ServiceRequest{Service: "workflow", Action: "fail"}, Retry: useCase.retry}
+		err := (&Service{}).runWithRetry(context, action, func() (interface{}, error) {
+			var response interface{}
+			if attempts < len(useCase.responses) {
+				response = useCase.responses[attempts]
+			}
+			err := useCase.errors[attempts]
+			attempts++
+			return response, err
+		})
+		context.Close()
+		assert.EqualValues(t, useCase.expectAttempts, attempts, useCase.description)
+		assert.EqualValues(t, useCase.expectDelays, delays, useCase.description)
+		if useCase.expectError == "" {
+			assert.Nil(t, err, useCase.description)
+			continue
+		}
+		if assert.NotNil(t, err, useCase.description) {
+			assert.EqualValues(t, useCase.expectError, err.Error(), useCase.description)
+			assert.True(t, errors.Is(err, boom), useCase.description)
+		}
+	}
+}
+
+func TestService_RunWithRetryDeadline(t *testing.T) {
+	var boom = errors.New("boom")
+	retry := &model.Retry{MaxAttempts: 3, DelayMs: 5000}
+	if !assert.Nil(t, retry.Init()) {
+		return
+	}
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	timedContext, cancel := context.WithTimeout(100 * time.Millisecond)
+	defer cancel()
+	attempts := 0
+	action := &model.Action{MetaTag: &model.MetaTag{}, ServiceRequest: &model.ServiceRequest{Service: "workflow", Action: "fail"}, Retry: retry}
+	started := time.Now()
+	err := (&Service{}).runWithRetry(timedContext, action, func() (interface{}, error) {
+		attempts++
+		return nil, boom
+	})
+	assert.True(t, time.Since(started) < 2*time.Second, "retry delay should be interrupted")
+	assert.EqualValues(t, 1, attempts)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "retry interrupted after 1 attempts")
+		assert.True(t, errors.Is(err, boom))
+	}
+}
+
+func TestService_RunActionRetry(t *testing.T) {
+	var useCases = []struct {
+		description string
+		retry       string
+		expectError string
+	}{
+		{
+			description: "action without retry",
+			expectError: "boom",
+		},
+		{
+			description: "action with retry",
+			retry: `
+    retry:
+      maxAttempts: 2
+      delayMs: 10`,
+			expectError: "failed after 2 attempts",
+		},
+	}
+	for _, useCase := range useCases {
+		_, _, err := runPipeline(t, `pipeline:
+  run:
+    action: fail
+    message: boom`+useCase.retry+"\n")
+		if assert.NotNil(t, err, useCase.description) {
+			assert.Contains(t, err.Error(), useCase.expectError, useCase.description)
+			if useCase.retry == "" {
+				assert.NotContains(t, err.Error(), "attempts", useCase.description)
+			}
+		}
+	}
+}
@@ -122,7 +122,17 @@ func (s *Service) runAction(context *endly.Context, action *model.Action, proces
 		}); err != nil {
 			return nil, nil, err
 		}
-		err = endly.Run(context, request, activity.ServiceResponse)
+		if context.DryRun && !isWorkflowRunAction(action) {
+			context.Publish(NewPlanEvent(action, toolbox.AsMap(state.Expand(requestMap))))
+			return nil, state, nil
+		}
+		if action.Retry == nil {
+			err = endly.Run(context, request, activity.ServiceResponse)
+		} else {
+			err = s.runWithRetry(context, action, func() (interface{}, error) {
+				return runAttempt(context, request, activity.ServiceResponse)
+			})
+		}
 		if err != nil {
 			return nil, nil, err
 		}
@@ -211,10 +221,16 @@ func (s *Service) runTask(context *endly.Context, process *model.Process, task *
 					return response, nil
 				}
 			}
-			moveToNextTag, err := criteria.Evaluate(context, context.State(), action.Skip, action.SkipEval(), "Skip", false)
+			moveToNextTag, captures, err := criteria.EvaluateWithCaptures(context, context.State(), action.Skip, action.SkipEval(), "Skip", false)
+			if err != nil && context.DryRun { //dry run plans actions with criteria depending on skipped responses
+				moveToNextTag, err = false, nil
+			}
 			if err != nil {
 				return nil, nil, err
 			}
+			if captures != nil {
+				captures.Publish(context.State())
+			}
 			if moveToNextTag {
 				for j := i + 1; j < len(task.Actions) && action.TagID == task.Actions[j].TagID; j++ {
 					i++
@@ -355,6 +371,7 @@ func (s *Service) enableLoggingIfNeeded(context *endly.Context, request *RunRequ
 
 		logger := NewLogger(logDirectory, context.Listener)
 		context.Listener = logger.AsEventListener()
+		context.LogDirectory = logDirectory
 	}
 }
 
@@ -404,6 +421,9 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 	}
 
 	s.enableLoggingIfNeeded(upstreamContext, request)
+	if request.DryRun {
+		upstreamContext.DryRun = true
+	}
 	workflow, err := s.getWorkflow(upstreamContext, request)
 	if err != nil {
 		return nil, err
@@ -516,9 +536,15 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 	}
 	filteredTasks := workflow.TasksNode.Select(taskSelector)
 	err = s.runNode(context, "workflow", process, workflow.AbstractNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
+		if err = s.initCheckpoint(context, request, process, response); err != nil {
+			return nil, nil, err
+		}
 		err = s.runTasks(context, process, filteredTasks)
 		return state, response.Data, err
 	})
+	if err == nil {
+		err = s.saveCheckpoint(context, process, nil, response.Data)
+	}
 
 	if len(response.Data) > 0 {
 		for k, v := range response.Data {
@@ -538,16 +564,23 @@ func (s *Service) runNode(context *endly.Context, nodeType string, process *mode
 		context.Logging = original
 	}()
 	var state = context.State()
-	canRun, err := criteria.Evaluate(context, context.State(), node.When, node.WhenEval(), fmt.Sprintf("%v.When", nodeType), true)
+	canRun, captures, err := criteria.EvaluateWithCaptures(context, state, node.When, node.WhenEval(), fmt.Sprintf("%v.When", nodeType), true)
+	if err != nil && context.DryRun {
+		canRun, err = true, nil
+	}
 	if err != nil || !canRun {
 		return err
 	}
+	if captures != nil {
+		captures.Publish(state)
+	}
 	err = node.Init.Apply(state, state)
 	s.addVariableEvent(fmt.Sprintf("%v.Init", nodeType), node.Init, context, state, state)
 	if err != nil {
 		return err
 	}
-	in, out, err := runHandler(context, process)
+	s.pause(context, nodeType, process, node)
+	in, out, err := s.runWithTimeout(context, nodeType, process, node, runHandler)
 	if err != nil {
 		return err
 	}
@@ -568,7 +601,7 @@ func (s *Service) runDeferredTask(context *endly.Context, process *model.Process
 		return nil
 	}
 	task, _ := parent.Task(parent.DeferredTask)
-	_, err := s.runTask(context, process, task)
+	_, err := s.runTask(context.WithoutTimeout(), process, task) //deferred task runs even if a node deadline was exceeded
 	return err
 }
 
@@ -597,7 +630,7 @@ func (s *Service) runOnErrorTask(context *endly.Context, process *model.Process,
 		if !task.Fail {
 			context.Publish(&msg.ResetError{})
 		}
-		_, err = s.runTask(context, process, task)
+		_, err = s.runTask(context.WithoutTimeout(), process, task) //catch task runs even if a node deadline was exceeded
 		return err
 	}
 	return err
@@ -611,19 +644,33 @@ func (s *Service) runTasks(context *endly.Context, process *model.Process, tasks
 			err = e
 		}
 	}()
-	for _, task := range tasks.Tasks {
-		if task.Name == tasks.OnErrorTask || task.Name == tasks.DeferredTask {
-			continue
-		}
-		if process.IsTerminated() {
-			break
-		}
-		if _, err = s.runTask(context, process, task); err != nil {
+	if tasks.IsConcurrent() {
+		if err = s.runParallelTasks(context, process, tasks); err != nil {
 			err = s.runOnErrorTask(context, process, tasks, err)
 		}
 		if err != nil {
 			return err
 		}
+	} else {
+		for _, task := range tasks.Tasks {
+			if task.Name == tasks.OnErrorTask || task.Name == tasks.DeferredTask {
+				continue
+			}
+			if process.IsTerminated() {
+				break
+			}
+			if s.isCheckpointed(context, process, task) {
+				continue
+			}
+			if _, err = s.runTask(context, process, task); err != nil {
+				err = s.runOnErrorTask(context, process, tasks, err) //task failed, it is not checkpointed even if the error was caught
+			} else {
+				err = s.saveCheckpoint(context, process, task, nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
 	}
 	var scheduledTask = process.Scheduled
 	if scheduledTask != nil {
@@ -728,7 +775,10 @@ func (s *Service) runSwitch(context *endly.Context, request *SwitchRequest) (Swi
 	}
 	var response interface{}
 	var source = getSwitchSource(context, request.SourceKey)
-	matched := request.Match(source)
+	matched, err := request.Match(context, source)
+	if err != nil {
+		return nil, err
+	}
 	if matched != nil {
 		if matched.Task != "" {
 			task, err := process.Workflow.Task(matched.Task)
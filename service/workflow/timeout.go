@@ -0,0 +1,44 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/data"
+)
+
+// runWithTimeout runs node handler with a derived context having the node deadline set on its background, so that services can honor it.
+// The handler is always awaited, once the deadline is exceeded the node fails with timeout error even if the handler completed.
+func (s *Service) runWithTimeout(context *endly.Context, nodeType string, process *model.Process, node *model.AbstractNode, handler func(context *endly.Context, process *model.Process) (in, out data.Map, err error)) (in, out data.Map, err error) {
+	if node.TimeoutMs > 0 {
+		var cancel func()
+		context, cancel = context.WithTimeout(time.Duration(node.TimeoutMs) * time.Millisecond)
+		defer cancel()
+	}
+	ctx := context.Background()
+	if ctx.Err() != nil {
+		return nil, nil, timeoutError(nodeType, node, ctx.Err())
+	}
+	in, out, err = handler(context, process)
+	if node.TimeoutMs > 0 && ctx.Err() != nil {
+		if err == nil {
+			err = ctx.Err()
+		}
+		err = timeoutError(nodeType, node, err)
+	}
+	return in, out, err
+}
+
+// timeoutError returns node timeout error, node without own timeout reports its cancellation by the enclosing node deadline
+func timeoutError(nodeType string, node *model.AbstractNode, err error) error {
+	label := nodeType
+	if node.Name != "" {
+		label += " " + node.Name
+	}
+	if node.TimeoutMs > 0 {
+		return fmt.Errorf("%v timed out after %v ms: %w", label, node.TimeoutMs, err)
+	}
+	return fmt.Errorf("%v cancelled: %w", label, err)
+}